@@ -20,6 +20,7 @@ statsmodel library.
 
 import sys
 import math
+import json
 from json import JSONDecodeError
 from dataclasses import dataclass
 from typing import List, Optional
@@ -46,6 +47,26 @@ warnings.simplefilter('ignore', ConvergenceWarning)
 #       4
 #   ],
 #   "dampedTrend": false,
+#   "dampingTrend": 0.9,
+#   "lookAheadSteps": 3,
+#   "lookAheadAggregation": "maximum",
+#   "forecastDetail": false,
+#   "tune": false
+# }
+#
+# If forecast detail is requested the forecast for every step is output along with the sum of squared errors of the
+# fitted model, rather than the aggregated prediction:
+# {
+#   "forecast": [3.2, 4.1, 2.9],
+#   "sse": 1.5
+# }
+#
+# If tuning is requested the alpha, beta and gamma provided are ignored, with the values optimised by statsmodels to
+# fit the series output instead of a prediction:
+# {
+#   "alpha": 0.5,
+#   "beta": 0.1,
+#   "gamma": 0.3
 # }
 
 
@@ -63,10 +84,15 @@ class AlgorithmInput:
     gamma: float
     series: List[int]
     damped_trend: bool = False
+    damping_trend: Optional[float] = None
     initialization_method: str = "estimated"
     initial_level: Optional[float] = None
     initial_trend: Optional[float] = None
     initial_seasonal: Optional[float] = None
+    look_ahead_steps: int = 1
+    look_ahead_aggregation: str = "maximum"
+    forecast_detail: bool = False
+    tune: bool = False
 
 
 stdin = sys.stdin.read()
@@ -93,6 +119,15 @@ if len(algorithm_input.series) < 10 + 2 * (algorithm_input.seasonal_periods // 2
           file=sys.stderr)
     sys.exit(1)
 
+if algorithm_input.look_ahead_steps < 1:
+    print("Invalid look ahead steps provided, must be at least 1, exiting", file=sys.stderr)
+    sys.exit(1)
+
+if algorithm_input.look_ahead_aggregation not in ("maximum", "last"):
+    print(f"Unknown look ahead aggregation '{algorithm_input.look_ahead_aggregation}' provided, exiting",
+          file=sys.stderr)
+    sys.exit(1)
+
 model = sm.ExponentialSmoothing(algorithm_input.series,
                                 trend=algorithm_input.trend,
                                 seasonal=algorithm_input.seasonal,
@@ -103,10 +138,32 @@ model = sm.ExponentialSmoothing(algorithm_input.series,
                                 initial_trend=algorithm_input.initial_trend,
                                 initial_seasonal=algorithm_input.initial_seasonal)
 
-fitted_model = model.fit(smoothing_level=algorithm_input.alpha,
-                         smoothing_trend=algorithm_input.beta,
-                         smoothing_seasonal=algorithm_input.gamma,
-                         optimized=False)
+if algorithm_input.tune:
+    # Let statsmodels optimise the smoothing parameters to fit the series
+    fitted_model = model.fit(damping_trend=algorithm_input.damping_trend,
+                             optimized=True)
+
+    print(json.dumps({
+        "alpha": float(fitted_model.params["smoothing_level"]),
+        "beta": float(fitted_model.params["smoothing_trend"]),
+        "gamma": float(fitted_model.params["smoothing_seasonal"])
+    }), end="")
+else:
+    fitted_model = model.fit(smoothing_level=algorithm_input.alpha,
+                             smoothing_trend=algorithm_input.beta,
+                             smoothing_seasonal=algorithm_input.gamma,
+                             damping_trend=algorithm_input.damping_trend,
+                             optimized=False)
+
+    # Predict the values over the look ahead horizon, then combine them into a single prediction
+    forecast = fitted_model.forecast(steps=algorithm_input.look_ahead_steps)
 
-# Predict the value one ahead
-print(math.ceil(fitted_model.forecast(steps=1)[0]), end="")
+    if algorithm_input.forecast_detail:
+        print(json.dumps({
+            "forecast": [float(value) for value in forecast],
+            "sse": float(fitted_model.sse)
+        }), end="")
+    elif algorithm_input.look_ahead_aggregation == "last":
+        print(math.ceil(forecast[-1]), end="")
+    else:
+        print(math.ceil(max(forecast)), end="")
@@ -77,6 +77,47 @@ def test_holt_winters(subtests):
                 "seasonalPeriods": 3,
                 "series": [1,3]
             }"""
+    }, {
+        "description":
+        "Failure, invalid look ahead steps",
+        "expected_status_code":
+        1,
+        "expected_stderr":
+        "Invalid look ahead steps provided, must be at least 1, exiting\n",
+        "expected_stdout":
+        "",
+        "stdin":
+        """{
+                "trend": "add",
+                "seasonal": "add",
+                "alpha": 0.9,
+                "beta": 0.9,
+                "gamma": 0.3,
+                "seasonalPeriods": 3,
+                "lookAheadSteps": 0,
+                "series": [1,3,1,1,3,1,1,3,1,1,3,1,1]
+            }"""
+    }, {
+        "description":
+        "Failure, unknown look ahead aggregation",
+        "expected_status_code":
+        1,
+        "expected_stderr":
+        "Unknown look ahead aggregation 'invalid' provided, exiting\n",
+        "expected_stdout":
+        "",
+        "stdin":
+        """{
+                "trend": "add",
+                "seasonal": "add",
+                "alpha": 0.9,
+                "beta": 0.9,
+                "gamma": 0.3,
+                "seasonalPeriods": 3,
+                "lookAheadSteps": 3,
+                "lookAheadAggregation": "invalid",
+                "series": [1,3,1,1,3,1,1,3,1,1,3,1,1]
+            }"""
     }, {
         "description":
         "Successful prediction, additive, 13 observations",
@@ -96,6 +137,48 @@ def test_holt_winters(subtests):
                 "seasonalPeriods": 3,
                 "series": [1,3,1,1,3,1,1,3,1,1,3,1,1]
             }"""
+    }, {
+        "description":
+        "Successful prediction, additive, 13 observations, look ahead 3 steps, maximum aggregation",
+        "expected_status_code":
+        0,
+        "expected_stderr":
+        "",
+        "expected_stdout":
+        "3",
+        "stdin":
+        """{
+                "trend": "add",
+                "seasonal": "add",
+                "alpha": 0.9,
+                "beta": 0.9,
+                "gamma": 0.3,
+                "seasonalPeriods": 3,
+                "lookAheadSteps": 3,
+                "lookAheadAggregation": "maximum",
+                "series": [1,3,1,1,3,1,1,3,1,1,3,1,1]
+            }"""
+    }, {
+        "description":
+        "Successful prediction, additive, 13 observations, look ahead 3 steps, last aggregation",
+        "expected_status_code":
+        0,
+        "expected_stderr":
+        "",
+        "expected_stdout":
+        "1",
+        "stdin":
+        """{
+                "trend": "add",
+                "seasonal": "add",
+                "alpha": 0.9,
+                "beta": 0.9,
+                "gamma": 0.3,
+                "seasonalPeriods": 3,
+                "lookAheadSteps": 3,
+                "lookAheadAggregation": "last",
+                "series": [1,3,1,1,3,1,1,3,1,1,3,1,1]
+            }"""
     }, {
         "description":
         "Successful prediction, multiplicative trend, 15 observations",
@@ -20,6 +20,7 @@ statsmodel library.
 
 import sys
 import math
+import json
 from json import JSONDecodeError
 from datetime import datetime, timedelta
 from dataclasses import dataclass
@@ -39,7 +40,18 @@ from dataclasses_json import dataclass_json, LetterCase
 #           "time": "2020-02-01T00:56:33Z",
 #           "replicas": 6
 #       }
-#   ]
+#   ],
+#   "currentTime": "2020-02-01T00:57:33Z",
+#   "intervalConfidence": 0.8
+# }
+#
+# The current time is the time the prediction is made from, if it is not provided the system time is used.
+#
+# If an interval confidence is provided the prediction is output along with the bounds of the prediction interval:
+# {
+#   "prediction": 7.5,
+#   "lower": 5.2,
+#   "upper": 9.8
 # }
 
 
@@ -62,6 +74,7 @@ class AlgorithmInput:
     look_ahead: int
     replica_history: List[TimestampedReplica]
     current_time: Optional[str] = None
+    interval_confidence: Optional[float] = None
 
 
 stdin = sys.stdin.read()
@@ -79,6 +92,10 @@ except KeyError as ex:
     print(f"Invalid JSON provided: missing {str(ex)}, exiting", file=sys.stderr)
     sys.exit(1)
 
+if algorithm_input.interval_confidence is not None and not 0 <= algorithm_input.interval_confidence < 1:
+    print("Invalid interval confidence provided, must be at least 0 and less than 1, exiting", file=sys.stderr)
+    sys.exit(1)
+
 current_time = datetime.utcnow()
 
 if algorithm_input.current_time is not None:
@@ -112,4 +129,13 @@ model = sm.OLS(y, x).fit()
 
 # Predict the value at the search time (0), include the constant (1).
 # The search time is 0 as the values used in training are search time - evaluation time, so the search time will be 0
-print(math.ceil(model.predict([[1, 0]])[0]), end="")
+if algorithm_input.interval_confidence is None:
+    print(math.ceil(model.predict([[1, 0]])[0]), end="")
+else:
+    prediction = model.get_prediction([[1, 0]]).summary_frame(alpha=1 - algorithm_input.interval_confidence)
+
+    print(json.dumps({
+        "prediction": float(prediction["mean"][0]),
+        "lower": float(prediction["obs_ci_lower"][0]),
+        "upper": float(prediction["obs_ci_upper"][0]),
+    }), end="")
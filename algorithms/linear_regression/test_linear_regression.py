@@ -150,6 +150,35 @@ def test_linear_regression(subtests):
                     }
                 ]
             }"""
+    }, {
+        "description":
+        "Invalid interval confidence",
+        "expected_status_code":
+        1,
+        "expected_stderr":
+        "Invalid interval confidence provided, must be at least 0 and less than 1, exiting\n",
+        "expected_stdout":
+        "",
+        "stdin":
+        """{
+                "lookAhead": 15000,
+                "currentTime": "2020-02-01T00:56:12Z",
+                "intervalConfidence": 1.5,
+                "replicaHistory": [
+                    {
+                        "replicas": 1,
+                        "time": "2020-02-01T00:55:33Z"
+                    },
+                    {
+                        "replicas": 2,
+                        "time": "2020-02-01T00:55:43Z"
+                    },
+                    {
+                        "replicas": 3,
+                        "time": "2020-02-01T00:55:53Z"
+                    }
+                ]
+            }"""
     }, {
         "description":
         "Successful prediction, 15 seconds in the future",
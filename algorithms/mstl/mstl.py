@@ -0,0 +1,106 @@
+# Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# pylint: disable=no-member, invalid-name
+"""
+This MSTL script decomposes the provided values into a trend and multiple seasonal components using the statsmodel
+library, then forecasts the next value by forecasting the deseasonalized data with exponential smoothing and adding
+on the seasonal components from one season ago.
+"""
+
+import sys
+import math
+from json import JSONDecodeError
+from dataclasses import dataclass
+from typing import List
+import warnings
+import numpy as np
+import statsmodels.tsa.api as sm
+from statsmodels.tsa.seasonal import MSTL
+from dataclasses_json import dataclass_json, LetterCase
+from statsmodels.tools.sm_exceptions import ConvergenceWarning
+
+warnings.simplefilter('ignore', ConvergenceWarning)
+
+# Takes in the replica series and the seasonal periods
+# {
+#   "seasonalPeriods": [3, 6],
+#   "series": [
+#       0,
+#       1,
+#       2,
+#       3,
+#       4
+#   ]
+# }
+
+
+@dataclass_json(letter_case=LetterCase.CAMEL)
+@dataclass
+class AlgorithmInput:
+    """
+    JSON data representation of the data this algorithm requires to be provided to it.
+    """
+    seasonal_periods: List[int]
+    series: List[int]
+
+
+stdin = sys.stdin.read()
+
+if stdin is None or stdin == "":
+    print("No standard input provided to MSTL algorithm, exiting", file=sys.stderr)
+    sys.exit(1)
+
+try:
+    algorithm_input = AlgorithmInput.from_json(stdin)
+except JSONDecodeError as ex:
+    print(f"Invalid JSON provided: {str(ex)}, exiting", file=sys.stderr)
+    sys.exit(1)
+except KeyError as ex:
+    print(f"Invalid JSON provided: missing {str(ex)}, exiting", file=sys.stderr)
+    sys.exit(1)
+
+if len(algorithm_input.seasonal_periods) == 0:
+    print("Invalid seasonal periods provided, must provide at least 1 seasonal period, exiting", file=sys.stderr)
+    sys.exit(1)
+
+if min(algorithm_input.seasonal_periods) < 2:
+    print("Invalid seasonal periods provided, every seasonal period must be at least 2, exiting", file=sys.stderr)
+    sys.exit(1)
+
+# MSTL orders the seasonal components from shortest to longest season
+seasonal_periods = sorted(algorithm_input.seasonal_periods)
+
+if len(algorithm_input.series) < 2 * seasonal_periods[-1]:
+    print("Invalid data provided, must be at least 2 * the longest seasonal period observations, exiting",
+          file=sys.stderr)
+    sys.exit(1)
+
+series = np.asarray(algorithm_input.series, dtype=float)
+
+decomposition = MSTL(series, periods=seasonal_periods).fit()
+
+seasonal = np.asarray(decomposition.seasonal).reshape(len(series), len(seasonal_periods))
+deseasonalized = series - seasonal.sum(axis=1)
+
+# Forecast the deseasonalized data using exponential smoothing with an additive trend
+fitted_model = sm.ExponentialSmoothing(deseasonalized, trend="add", initialization_method="estimated").fit()
+forecast = fitted_model.forecast(steps=1)[0]
+
+# Each seasonal component for the next value is assumed to be the same as it was one season ago
+for i, period in enumerate(seasonal_periods):
+    forecast += seasonal[len(series) - period, i]
+
+# Predict the value one ahead
+print(math.ceil(forecast), end="")
@@ -0,0 +1,98 @@
+"""
+Tests the MSTL algorithm by calling it from the shell, giving different stdin and checking the return code and stderr
+and stdout.
+"""
+import subprocess
+
+
+def test_mstl(subtests):
+    """
+    Test the MSTL algorithm
+    """
+    test_cases = [{
+        "description": "Empty stdin",
+        "expected_status_code": 1,
+        "expected_stderr": "No standard input provided to MSTL algorithm, exiting\n",
+        "expected_stdout": "",
+        "stdin": ""
+    }, {
+        "description": "Invalid JSON stdin",
+        "expected_status_code": 1,
+        "expected_stderr": "Invalid JSON provided: Expecting value: line 1 column 1 (char 0), exiting\n",
+        "expected_stdout": "",
+        "stdin": "invalid"
+    }, {
+        "description":
+        "JSON stdin missing 'seasonalPeriods'",
+        "expected_status_code":
+        1,
+        "expected_stderr":
+        "Invalid JSON provided: missing 'seasonal_periods', exiting\n",
+        "expected_stdout":
+        "",
+        "stdin":
+        """{
+                "series": [1,3,1,1,3,1,1,3,1]
+            }"""
+    }, {
+        "description":
+        "Failure, no seasonal periods",
+        "expected_status_code":
+        1,
+        "expected_stderr":
+        "Invalid seasonal periods provided, must provide at least 1 seasonal period, exiting\n",
+        "expected_stdout":
+        "",
+        "stdin":
+        """{
+                "seasonalPeriods": [],
+                "series": [1,3,1,1,3,1,1,3,1]
+            }"""
+    }, {
+        "description":
+        "Failure, seasonal period less than 2",
+        "expected_status_code":
+        1,
+        "expected_stderr":
+        "Invalid seasonal periods provided, every seasonal period must be at least 2, exiting\n",
+        "expected_stdout":
+        "",
+        "stdin":
+        """{
+                "seasonalPeriods": [3, 1],
+                "series": [1,3,1,1,3,1,1,3,1]
+            }"""
+    }, {
+        "description":
+        "Failure, less than required observations, 11 observations",
+        "expected_status_code":
+        1,
+        "expected_stderr":
+        "Invalid data provided, must be at least 2 * the longest seasonal period observations, exiting\n",
+        "expected_stdout":
+        "",
+        "stdin":
+        """{
+                "seasonalPeriods": [6, 3],
+                "series": [1,3,1,1,3,1,1,3,1,1,3]
+            }"""
+    }]
+
+    for i, test_case in enumerate(test_cases):
+        with subtests.test(msg=test_case["description"], i=i):
+            result = subprocess.run(["python", "./algorithms/mstl/mstl.py"],
+                                    input=test_case["stdin"].encode("utf-8"),
+                                    capture_output=True,
+                                    check=False)
+
+            stderr = result.stderr
+            if stderr is not None:
+                stderr = stderr.decode("utf-8")
+
+            stdout = result.stdout
+            if stdout is not None:
+                stdout = stdout.decode("utf-8")
+
+            assert test_case["expected_status_code"] == result.returncode
+            assert test_case["expected_stderr"] == stderr
+            assert test_case["expected_stdout"] == stdout
@@ -0,0 +1,106 @@
+# Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# pylint: disable=no-member, invalid-name
+"""
+This SARIMA script fits a seasonal autoregressive integrated moving average model to the provided values using the
+statsmodel library, and uses it to forecast the next value.
+"""
+
+import sys
+import math
+from json import JSONDecodeError
+from dataclasses import dataclass
+from typing import List, Optional
+import warnings
+import statsmodels.tsa.api as sm
+from dataclasses_json import dataclass_json, LetterCase
+from statsmodels.tools.sm_exceptions import ConvergenceWarning
+
+warnings.simplefilter('ignore', ConvergenceWarning)
+warnings.simplefilter('ignore', UserWarning)
+
+# Takes in the replica series, the (p,d,q) order, the (P,D,Q,s) seasonal order, and the trend
+# {
+#   "order": [1, 0, 1],
+#   "seasonalOrder": [1, 1, 0, 3],
+#   "trend": "c",
+#   "series": [
+#       0,
+#       1,
+#       2,
+#       3,
+#       4
+#   ]
+# }
+
+
+@dataclass_json(letter_case=LetterCase.CAMEL)
+@dataclass
+class AlgorithmInput:
+    """
+    JSON data representation of the data this algorithm requires to be provided to it.
+    """
+    order: List[int]
+    series: List[int]
+    seasonal_order: List[int]
+    trend: Optional[str] = None
+
+
+stdin = sys.stdin.read()
+
+if stdin is None or stdin == "":
+    print("No standard input provided to SARIMA algorithm, exiting", file=sys.stderr)
+    sys.exit(1)
+
+try:
+    algorithm_input = AlgorithmInput.from_json(stdin)
+except JSONDecodeError as ex:
+    print(f"Invalid JSON provided: {str(ex)}, exiting", file=sys.stderr)
+    sys.exit(1)
+except KeyError as ex:
+    print(f"Invalid JSON provided: missing {str(ex)}, exiting", file=sys.stderr)
+    sys.exit(1)
+
+if len(algorithm_input.order) != 3:
+    print("Invalid order provided, must be (p,d,q), exiting", file=sys.stderr)
+    sys.exit(1)
+
+if len(algorithm_input.seasonal_order) != 4:
+    print("Invalid seasonal order provided, must be (P,D,Q,s), exiting", file=sys.stderr)
+    sys.exit(1)
+
+p, d, q = algorithm_input.order
+P, D, Q, s = algorithm_input.seasonal_order
+
+if s > 1 and len(algorithm_input.series) < 2 * s:
+    print("Invalid data provided, must be at least 2 * s observations, exiting", file=sys.stderr)
+    sys.exit(1)
+
+if len(algorithm_input.series) < d + D * s + max(p + P * s, q + Q * s) + 1:
+    print("Invalid data provided, must be at least d + D * s + max(p + P * s, q + Q * s) + 1 observations, exiting",
+          file=sys.stderr)
+    sys.exit(1)
+
+model = sm.SARIMAX(algorithm_input.series,
+                   order=(p, d, q),
+                   seasonal_order=(P, D, Q, s),
+                   trend=algorithm_input.trend,
+                   enforce_stationarity=False,
+                   enforce_invertibility=False)
+
+fitted_model = model.fit(disp=False)
+
+# Predict the value one ahead
+print(math.ceil(fitted_model.forecast(steps=1)[0]), end="")
@@ -0,0 +1,117 @@
+"""
+Tests the SARIMA algorithm by calling it from the shell, giving different stdin and checking the return code and stderr
+and stdout.
+"""
+import subprocess
+
+
+def test_sarima(subtests):
+    """
+    Test the SARIMA algorithm
+    """
+    test_cases = [{
+        "description": "Empty stdin",
+        "expected_status_code": 1,
+        "expected_stderr": "No standard input provided to SARIMA algorithm, exiting\n",
+        "expected_stdout": "",
+        "stdin": ""
+    }, {
+        "description": "Invalid JSON stdin",
+        "expected_status_code": 1,
+        "expected_stderr": "Invalid JSON provided: Expecting value: line 1 column 1 (char 0), exiting\n",
+        "expected_stdout": "",
+        "stdin": "invalid"
+    }, {
+        "description":
+        "JSON stdin missing 'order'",
+        "expected_status_code":
+        1,
+        "expected_stderr":
+        "Invalid JSON provided: missing 'order', exiting\n",
+        "expected_stdout":
+        "",
+        "stdin":
+        """{
+                "seasonalOrder": [1, 1, 0, 3],
+                "series": [1,3,1,1,3,1,1,3,1]
+            }"""
+    }, {
+        "description":
+        "Failure, invalid order",
+        "expected_status_code":
+        1,
+        "expected_stderr":
+        "Invalid order provided, must be (p,d,q), exiting\n",
+        "expected_stdout":
+        "",
+        "stdin":
+        """{
+                "order": [1, 0],
+                "seasonalOrder": [1, 1, 0, 3],
+                "series": [1,3,1,1,3,1,1,3,1]
+            }"""
+    }, {
+        "description":
+        "Failure, invalid seasonal order",
+        "expected_status_code":
+        1,
+        "expected_stderr":
+        "Invalid seasonal order provided, must be (P,D,Q,s), exiting\n",
+        "expected_stdout":
+        "",
+        "stdin":
+        """{
+                "order": [1, 0, 1],
+                "seasonalOrder": [1, 1, 0],
+                "series": [1,3,1,1,3,1,1,3,1]
+            }"""
+    }, {
+        "description":
+        "Failure, less than required observations, 5 observations",
+        "expected_status_code":
+        1,
+        "expected_stderr":
+        "Invalid data provided, must be at least 2 * s observations, exiting\n",
+        "expected_stdout":
+        "",
+        "stdin":
+        """{
+                "order": [1, 0, 1],
+                "seasonalOrder": [1, 1, 0, 3],
+                "series": [1,3,1,1,3]
+            }"""
+    }, {
+        "description":
+        "Failure, less than required observations, 6 observations",
+        "expected_status_code":
+        1,
+        "expected_stderr":
+        "Invalid data provided, must be at least d + D * s + max(p + P * s, q + Q * s) + 1 observations, exiting\n",
+        "expected_stdout":
+        "",
+        "stdin":
+        """{
+                "order": [1, 0, 1],
+                "seasonalOrder": [1, 1, 0, 3],
+                "series": [1,3,1,1,3,1]
+            }"""
+    }]
+
+    for i, test_case in enumerate(test_cases):
+        with subtests.test(msg=test_case["description"], i=i):
+            result = subprocess.run(["python", "./algorithms/sarima/sarima.py"],
+                                    input=test_case["stdin"].encode("utf-8"),
+                                    capture_output=True,
+                                    check=False)
+
+            stderr = result.stderr
+            if stderr is not None:
+                stderr = stderr.decode("utf-8")
+
+            stdout = result.stdout
+            if stdout is not None:
+                stdout = stdout.decode("utf-8")
+
+            assert test_case["expected_status_code"] == result.returncode
+            assert test_case["expected_stderr"] == stderr
+            assert test_case["expected_stdout"] == stdout
@@ -0,0 +1,127 @@
+# Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+
+#     http://www.apache.org/licenses/LICENSE-2.0
+
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+"""
+Tests the algorithm worker by starting it from the shell, sending it framed requests to run test scripts and checking
+the framed responses.
+"""
+import json
+import struct
+import subprocess
+
+HEADER = struct.Struct(">I")
+
+SCRIPTS = {
+    "echo.py": "import sys\nprint(sys.stdin.read(), end='')\n",
+    "fail.py": "import sys\nprint('algorithm failed', file=sys.stderr)\nsys.exit(1)\n",
+    "exit_message.py": "import sys\nsys.exit('exit message')\n",
+    "raise.py": "raise ValueError('bad value')\n",
+}
+
+
+def send(worker, request):
+    """
+    Sends a framed request to the worker and reads the framed response
+    """
+    payload = request.encode("utf-8")
+    worker.stdin.write(HEADER.pack(len(payload)) + payload)
+    worker.stdin.flush()
+    (size, ) = HEADER.unpack(worker.stdout.read(HEADER.size))
+    return json.loads(worker.stdout.read(size))
+
+
+def test_worker(subtests, tmp_path):
+    """
+    Test the algorithm worker
+    """
+    for name, script in SCRIPTS.items():
+        (tmp_path / name).write_text(script)
+
+    test_cases = [{
+        "description": "Successful algorithm",
+        "expected": {
+            "exitCode": 0,
+            "stdout": "test value",
+            "stderr": ""
+        },
+        "request": json.dumps({
+            "algorithmPath": str(tmp_path / "echo.py"),
+            "value": "test value"
+        })
+    }, {
+        "description": "Successful algorithm, run again in the same worker",
+        "expected": {
+            "exitCode": 0,
+            "stdout": "another value",
+            "stderr": ""
+        },
+        "request": json.dumps({
+            "algorithmPath": str(tmp_path / "echo.py"),
+            "value": "another value"
+        })
+    }, {
+        "description": "Algorithm exits with non-zero exit code",
+        "expected": {
+            "exitCode": 1,
+            "stdout": "",
+            "stderr": "algorithm failed\n"
+        },
+        "request": json.dumps({
+            "algorithmPath": str(tmp_path / "fail.py"),
+            "value": "test value"
+        })
+    }, {
+        "description": "Algorithm exits with message",
+        "expected": {
+            "exitCode": 1,
+            "stdout": "",
+            "stderr": "exit message\n"
+        },
+        "request": json.dumps({
+            "algorithmPath": str(tmp_path / "exit_message.py"),
+            "value": "test value"
+        })
+    }, {
+        "description": "Invalid JSON request",
+        "expected": {
+            "exitCode": 1,
+            "stdout": "",
+            "stderr": "Invalid request provided to worker: Expecting value: line 1 column 1 (char 0)"
+        },
+        "request": "invalid"
+    }, {
+        "description": "Request missing 'value'",
+        "expected": {
+            "exitCode": 1,
+            "stdout": "",
+            "stderr": "Invalid request provided to worker: 'value'"
+        },
+        "request": json.dumps({
+            "algorithmPath": str(tmp_path / "echo.py"),
+        })
+    }]
+
+    with subprocess.Popen(["python", "./algorithms/worker/worker.py"], stdin=subprocess.PIPE,
+                          stdout=subprocess.PIPE) as worker:
+        for i, test_case in enumerate(test_cases):
+            with subtests.test(msg=test_case["description"], i=i):
+                assert test_case["expected"] == send(worker, test_case["request"])
+
+        with subtests.test(msg="Algorithm raises exception"):
+            response = send(worker, json.dumps({"algorithmPath": str(tmp_path / "raise.py"), "value": "test value"}))
+            assert response["exitCode"] == 1
+            assert response["stdout"] == ""
+            assert "ValueError: bad value" in response["stderr"]
+
+        worker.stdin.close()
+        assert worker.wait(timeout=5) == 0
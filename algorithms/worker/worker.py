@@ -0,0 +1,127 @@
+# Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# pylint: disable=invalid-name, broad-except
+"""
+This worker script is a long-lived process that runs algorithm scripts on request, avoiding the cost of starting a new
+Python process and importing the algorithm dependencies every time an algorithm is run.
+"""
+
+import io
+import os
+import sys
+import json
+import runpy
+import struct
+import traceback
+
+# Requests and responses are sent as frames, a 4 byte big endian length followed by a JSON payload.
+#
+# Takes in a request to run an algorithm:
+# {
+#   "algorithmPath": "/app/algorithms/linear_regression/linear_regression.py",
+#   "value": "{\"lookAhead\": 3, ...}"
+# }
+#
+# Responds with the result of running the algorithm:
+# {
+#   "exitCode": 0,
+#   "stdout": "5",
+#   "stderr": ""
+# }
+
+HEADER = struct.Struct(">I")
+
+
+def read_frame(stream):
+    """
+    Reads a single frame from the stream, returning None if the stream has been closed.
+    """
+    header = stream.read(HEADER.size)
+    if len(header) < HEADER.size:
+        return None
+    (size, ) = HEADER.unpack(header)
+    payload = stream.read(size)
+    if len(payload) < size:
+        return None
+    return payload
+
+
+def write_frame(stream, payload):
+    """
+    Writes a single frame to the stream.
+    """
+    stream.write(HEADER.pack(len(payload)) + payload)
+    stream.flush()
+
+
+def run_algorithm(algorithm_path, value):
+    """
+    Runs the algorithm script at the path provided as if it was run as a separate process, with the value as stdin,
+    returning the exit code, stdout and stderr.
+    """
+    stdin = io.StringIO(value)
+    stdout = io.StringIO()
+    stderr = io.StringIO()
+
+    original_stdin, original_stdout, original_stderr, original_argv = sys.stdin, sys.stdout, sys.stderr, sys.argv
+    sys.stdin, sys.stdout, sys.stderr, sys.argv = stdin, stdout, stderr, [algorithm_path]
+
+    exit_code = 0
+    try:
+        runpy.run_path(algorithm_path, run_name="__main__")
+    except SystemExit as ex:
+        if ex.code is None:
+            exit_code = 0
+        elif isinstance(ex.code, int):
+            exit_code = ex.code
+        else:
+            print(ex.code, file=stderr)
+            exit_code = 1
+    except Exception:
+        traceback.print_exc(file=stderr)
+        exit_code = 1
+    finally:
+        sys.stdin, sys.stdout, sys.stderr, sys.argv = original_stdin, original_stdout, original_stderr, original_argv
+
+    return exit_code, stdout.getvalue(), stderr.getvalue()
+
+
+def main():
+    """
+    Reads requests until stdin is closed, running the requested algorithm for each one.
+    """
+    requests = sys.stdin.buffer
+
+    # Keep a private copy of stdout for responses, and point the original stdout at stderr so anything written
+    # directly to it (e.g. by a C extension) can't corrupt the responses
+    responses = os.fdopen(os.dup(sys.stdout.fileno()), "wb")
+    os.dup2(sys.stderr.fileno(), sys.stdout.fileno())
+
+    while True:
+        frame = read_frame(requests)
+        if frame is None:
+            return
+
+        try:
+            request = json.loads(frame)
+            exit_code, stdout, stderr = run_algorithm(request["algorithmPath"], request["value"])
+        except (ValueError, KeyError) as ex:
+            exit_code, stdout, stderr = 1, "", f"Invalid request provided to worker: {str(ex)}"
+
+        write_frame(responses, json.dumps({"exitCode": exit_code, "stdout": stdout, "stderr": stderr}).encode())
+
+
+if __name__ == "__main__":
+    main()
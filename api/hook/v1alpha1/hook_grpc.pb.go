@@ -0,0 +1,111 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: api/hook/v1alpha1/hook.proto
+
+package hookv1alpha1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// HookClient is the client API for Hook service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type HookClient interface {
+	// Execute calls the hook with a value, the hook responds with a value. The format of the values depends on what the
+	// hook is used for, for example a Remote model prediction hook is provided with the model and replica history as
+	// JSON and must respond with the predicted replicas as JSON.
+	Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error)
+}
+
+type hookClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHookClient(cc grpc.ClientConnInterface) HookClient {
+	return &hookClient{cc}
+}
+
+func (c *hookClient) Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error) {
+	out := new(ExecuteResponse)
+	err := c.cc.Invoke(ctx, "/jamiethompsonme.hook.v1alpha1.Hook/Execute", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HookServer is the server API for Hook service.
+// All implementations must embed UnimplementedHookServer
+// for forward compatibility
+type HookServer interface {
+	// Execute calls the hook with a value, the hook responds with a value. The format of the values depends on what the
+	// hook is used for, for example a Remote model prediction hook is provided with the model and replica history as
+	// JSON and must respond with the predicted replicas as JSON.
+	Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error)
+	mustEmbedUnimplementedHookServer()
+}
+
+// UnimplementedHookServer must be embedded to have forward compatible implementations.
+type UnimplementedHookServer struct {
+}
+
+func (UnimplementedHookServer) Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedHookServer) mustEmbedUnimplementedHookServer() {}
+
+// UnsafeHookServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HookServer will
+// result in compilation errors.
+type UnsafeHookServer interface {
+	mustEmbedUnimplementedHookServer()
+}
+
+func RegisterHookServer(s grpc.ServiceRegistrar, srv HookServer) {
+	s.RegisterService(&Hook_ServiceDesc, srv)
+}
+
+func _Hook_Execute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HookServer).Execute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jamiethompsonme.hook.v1alpha1.Hook/Execute",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HookServer).Execute(ctx, req.(*ExecuteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Hook_ServiceDesc is the grpc.ServiceDesc for Hook service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Hook_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "jamiethompsonme.hook.v1alpha1.Hook",
+	HandlerType: (*HookServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Execute",
+			Handler:    _Hook_Execute_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/hook/v1alpha1/hook.proto",
+}
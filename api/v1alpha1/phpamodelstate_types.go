@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CompactReplicaHistory is a list of timestamped replica counts stored as columns, with the time of each replica count
+// stored as the number of seconds since the previous replica count
+type CompactReplicaHistory struct {
+	// start is the time that the intervals are measured from.
+	Start metav1.Time `json:"start"`
+	// intervals is the number of seconds between each replica count and the previous replica count, with the first
+	// interval measured from the start time.
+	Intervals []int64 `json:"intervals"`
+	// replicas is the replica count at each time.
+	Replicas []int32 `json:"replicas"`
+}
+
+// CompactMetricHistory is a list of timestamped raw metric values stored as columns, with the time of each set of
+// metric values stored as the number of seconds since the previous set of metric values
+type CompactMetricHistory struct {
+	// start is the time that the intervals are measured from.
+	Start metav1.Time `json:"start"`
+	// intervals is the number of seconds between each set of metric values and the previous set of metric values,
+	// with the first interval measured from the start time.
+	Intervals []int64 `json:"intervals"`
+	// values is the raw value of each metric at each time, in the same order as the metrics are defined in
+	// spec.metrics of the PHPA.
+	Values [][]int64 `json:"values"`
+}
+
+// ModelState is the history stored for a single model, the same as a ModelHistory but with the replica and metric
+// histories stored in a compact encoding.
+type ModelState struct {
+	// type is the type of the model the history is for, useful to check for model mismatches with the data.
+	Type string `json:"type"`
+	// syncPeriodsPassed is the number of sync periods that have passed since the last time this model was used, used
+	// when determining if a model should run based on the perSyncPeriod
+	SyncPeriodsPassed int `json:"syncPeriodsPassed"`
+	// replicaHistory is the timestamped replica counts fed into the model to calculate a predicted value.
+	// +optional
+	ReplicaHistory *CompactReplicaHistory `json:"replicaHistory,omitempty"`
+	// startTime is the time after which the model should start applying and recording data.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// metricHistory is the timestamped raw metric values fed into the model to forecast metric values if the model's
+	// input is 'metrics'.
+	// +optional
+	MetricHistory *CompactMetricHistory `json:"metricHistory,omitempty"`
+	// pendingPredictions is a list of the model's predictions that have not yet been scored, timestamped with the time
+	// that each prediction is for.
+	// +optional
+	PendingPredictions []TimestampedReplicas `json:"pendingPredictions,omitempty"`
+	// scoredPredictions is a list of the model's most recent predictions paired with the replica count that was
+	// calculated at the time each prediction was for.
+	// +optional
+	ScoredPredictions []ScoredPrediction `json:"scoredPredictions,omitempty"`
+	// excluded is if the model has been excluded from the decision by its fallback policy because of inaccurate
+	// predictions.
+	// +optional
+	Excluded bool `json:"excluded,omitempty"`
+	// tunedParameters are the smoothing parameters fit to the replica history for Holt-Winters models using autoTune.
+	// +optional
+	TunedParameters *HoltWintersTunedParameters `json:"tunedParameters,omitempty"`
+	// lastPrediction is the replica count most recently predicted by the model, timestamped with the time the model
+	// ran.
+	// +optional
+	LastPrediction *TimestampedReplicas `json:"lastPrediction,omitempty"`
+}
+
+// PHPAModelStateSpec is the stored model histories of a PHPA
+type PHPAModelStateSpec struct {
+	// modelHistories is a mapping of model names to the model's stored history.
+	// +optional
+	ModelHistories map[string]ModelState `json:"modelHistories,omitempty"`
+	// replicaHistory is the shared history of the replica counts calculated each sync period, recorded independently
+	// of the models.
+	// +optional
+	ReplicaHistory *CompactReplicaHistory `json:"replicaHistory,omitempty"`
+	// metricHistory is the shared history of the raw metric values gathered each sync period.
+	// +optional
+	MetricHistory *CompactMetricHistory `json:"metricHistory,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=phpastate
+// PHPAModelState stores the model histories of a PredictiveHorizontalPodAutoscaler, it has the same name as the PHPA
+// and is owned by it. It is managed by the PHPA operator and should not be modified while the PHPA is running.
+type PHPAModelState struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PHPAModelStateSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PHPAModelStateList contains a list of PHPAModelState
+type PHPAModelStateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PHPAModelState `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PHPAModelState{}, &PHPAModelStateList{})
+}
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -30,25 +31,142 @@ const (
 	DecisionMean = "mean"
 	// DecisionMedian means use the median average of predicted values
 	DecisionMedian = "median"
+	// DecisionQuantile means use the highest upper bound of the models' prediction intervals at the quantile provided
+	// by decisionQuantile
+	DecisionQuantile = "quantile"
+	// DecisionWeighted means use the weighted mean average of predicted values, using the weight of each model
+	DecisionWeighted = "weighted"
+)
+
+const (
+	// ModeActive means the PHPA scales its target to the replica count it decides
+	ModeActive = "Active"
+	// ModeDryRun means the PHPA decides a replica count and records it without scaling its target
+	ModeDryRun = "DryRun"
 )
 
 const (
 	TypeHoltWinters = "HoltWinters"
 	TypeLinear      = "Linear"
+	TypeCustom      = "Custom"
+	TypeRemote      = "Remote"
+	TypeSARIMA      = "SARIMA"
+	TypeMSTL        = "MSTL"
+)
+
+const (
+	// BackendPython means the model is calculated by running a Python algorithm as a separate process
+	BackendPython = "python"
+	// BackendNative means the model is calculated in-process using a native Go implementation
+	BackendNative = "native"
+)
+
+const (
+	// InputReplicas means the model is fed the replica counts calculated from the metrics
+	InputReplicas = "replicas"
+	// InputMetrics means the model is fed the raw metric values gathered, with the forecast metric values then used to
+	// calculate the replica count
+	InputMetrics = "metrics"
+)
+
+const (
+	// LookAheadAggregationMaximum means use the highest value forecast over the look ahead horizon
+	LookAheadAggregationMaximum = "maximum"
+	// LookAheadAggregationLast means use the value forecast for the final step of the look ahead horizon
+	LookAheadAggregationLast = "last"
+)
+
+const (
+	// AccuracyMeanAbsoluteError means measure a model's accuracy using the mean absolute error of its predictions
+	AccuracyMeanAbsoluteError = "meanAbsoluteError"
+	// AccuracyMeanAbsolutePercentageError means measure a model's accuracy using the mean absolute percentage error of
+	// its predictions
+	AccuracyMeanAbsolutePercentageError = "meanAbsolutePercentageError"
+)
+
+const (
+	// CustomRuntimePython means the custom model algorithm is a Python script, run using the Python algorithm runner
+	CustomRuntimePython = "python"
+	// CustomRuntimeExecutable means the custom model algorithm is an executable, run directly
+	CustomRuntimeExecutable = "executable"
+)
+
+const (
+	// HistoryBootstrapPrometheus means the model's replica history is bootstrapped by querying Prometheus
+	HistoryBootstrapPrometheus = "Prometheus"
 )
 
 const (
 	HookTypeHTTP = "http"
+	HookTypeGRPC = "grpc"
+)
+
+const (
+	// ConditionAbleToScale indicates whether the PHPA is able to get and update the scale of its target
+	ConditionAbleToScale = "AbleToScale"
+	// ConditionScalingActive indicates whether the PHPA is able to calculate a replica count from its metrics
+	ConditionScalingActive = "ScalingActive"
+	// ConditionScalingLimited indicates whether the replica count decided was limited by the min and max replicas or
+	// the scaling behavior
+	ConditionScalingLimited = "ScalingLimited"
+	// ConditionModelsHealthy indicates whether every model that ran was able to make a prediction
+	ConditionModelsHealthy = "ModelsHealthy"
+	// ConditionValid indicates whether the PHPA spec is valid, an invalid PHPA is disabled until it is changed
+	ConditionValid = "Valid"
 )
 
+const (
+	// ModelReasonStartInterval means the model did not run because it is waiting for its start interval
+	ModelReasonStartInterval = "StartInterval"
+	// ModelReasonPerSyncPeriod means the model did not run because it only runs every perSyncPeriod sync periods
+	ModelReasonPerSyncPeriod = "PerSyncPeriod"
+	// ModelReasonInsufficientHistory means the model did not run because there is not enough history stored to make a
+	// prediction yet
+	ModelReasonInsufficientHistory = "InsufficientHistory"
+	// ModelReasonError means the model failed to be processed
+	ModelReasonError = "Error"
+)
+
+// ModelFallback defines when a model should be excluded from the decision because of inaccurate predictions, and when
+// it should be included again.
+type ModelFallback struct {
+	// accuracyMetric is the measure of the model's accuracy to compare against the thresholds, either
+	// 'meanAbsoluteError' or 'meanAbsolutePercentageError'.
+	// Default value is 'meanAbsolutePercentageError'
+	// +kubebuilder:validation:Enum=meanAbsoluteError;meanAbsolutePercentageError
+	// +optional
+	AccuracyMetric *string `json:"accuracyMetric"`
+
+	// threshold is the error above which the model is excluded from the decision.
+	// +kubebuilder:validation:Minimum=0
+	Threshold float64 `json:"threshold"`
+
+	// recoveryThreshold is the error that an excluded model's error must fall to or below before it is included in the
+	// decision again, setting this lower than the threshold stops a model with an error around the threshold from
+	// repeatedly being excluded and included.
+	// Default value is the same as the threshold.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RecoveryThreshold *float64 `json:"recoveryThreshold"`
+
+	// minimumSamples is the number of scored predictions needed before the model's accuracy is compared against the
+	// thresholds, until then the model stays in its current state.
+	// Default value is 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinimumSamples *int32 `json:"minimumSamples"`
+}
+
 // HookDefinition describes a hook for passing data/triggering logic, such as through a shell command
 type HookDefinition struct {
-	// +kubebuilder:validation:Enum=http
+	// +kubebuilder:validation:Enum=http;grpc
 	Type string `json:"type"`
 	// +kubebuilder:validation:Minimum=1
 	Timeout int `json:"timeout"`
 	// +optional
 	HTTP *HTTPHook `json:"http"`
+	// +optional
+	GRPC *GRPCHook `json:"grpc"`
 }
 
 // HTTPHook describes configuration options for an HTTP request hook
@@ -62,6 +180,20 @@ type HTTPHook struct {
 	ParameterMode string `json:"parameterMode"`
 }
 
+// GRPCHook describes configuration options for a gRPC hook, calling a service that implements the Hook service
+// defined in api/hook/v1alpha1/hook.proto
+type GRPCHook struct {
+	// address is the address of the gRPC service to call, for example 'prediction-service:50051'
+	Address string `json:"address"`
+	// tls enables calling the gRPC service using TLS, verified using the system's certificate authorities.
+	// Default value is false
+	// +optional
+	TLS bool `json:"tls"`
+	// metadata is a set of key value pairs to send as gRPC metadata with every request
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
 // Linear represents a linear regression prediction model configuration
 type Linear struct {
 	// historySize is how many timestamped replica counts should be stored for this linear regression, with older
@@ -104,6 +236,13 @@ type HoltWinters struct {
 	// +optional
 	DampedTrend *bool `json:"dampedTrend"`
 
+	// dampingTrend is the damping factor (phi) to apply to the trend if dampedTrend is enabled. If not provided no
+	// damping factor is applied, matching the statsmodels behavior when the damping factor is not optimised.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	DampingTrend *float64 `json:"dampingTrend"`
+
 	// +optional
 	// +kubebuilder:validation:Enum=estimated;heuristic;known;legacy-heuristic
 	InitializationMethod *string `json:"initializationMethod"`
@@ -119,17 +258,197 @@ type HoltWinters struct {
 
 	// +optional
 	RuntimeTuningFetchHook *HookDefinition `json:"runtimeTuningFetchHook"`
+
+	// lookAhead configures how many sync periods ahead the model should forecast, if not provided the model forecasts
+	// one sync period ahead.
+	// +optional
+	LookAhead *HoltWintersLookAhead `json:"lookAhead"`
+
+	// autoTune configures the model to fit its own alpha, beta and gamma values to the stored replica history rather
+	// than using the values provided, with the fitted values cached and only refit periodically. Only supported by the
+	// 'python' backend with an input of 'replicas'.
+	// +optional
+	AutoTune *HoltWintersAutoTune `json:"autoTune"`
+}
+
+// HoltWintersAutoTune defines how a Holt-Winters model should fit its own smoothing parameters
+type HoltWintersAutoTune struct {
+	// refitInterval is how long the fitted smoothing parameters are used for before they are fit again to the latest
+	// replica history.
+	// This value is a string duration, e.g. 2m30s is 2 minutes and 30 seconds.
+	// Default value is 1h.
+	// +optional
+	RefitInterval *metav1.Duration `json:"refitInterval"`
+}
+
+// HoltWintersTunedParameters are the smoothing parameters fit to a Holt-Winters model's replica history
+type HoltWintersTunedParameters struct {
+	// time is the time that the parameters were fit at.
+	Time *metav1.Time `json:"time"`
+	// alpha is the fitted level smoothing parameter.
+	Alpha float64 `json:"alpha"`
+	// beta is the fitted trend smoothing parameter.
+	Beta float64 `json:"beta"`
+	// gamma is the fitted seasonal smoothing parameter.
+	Gamma float64 `json:"gamma"`
+}
+
+// HoltWintersLookAhead defines how far ahead a Holt-Winters model should forecast, and how the values forecast over
+// that horizon are combined into a single prediction
+type HoltWintersLookAhead struct {
+	// steps is the number of sync periods ahead to forecast.
+	// +kubebuilder:validation:Minimum=1
+	Steps int `json:"steps"`
+
+	// aggregation is how the values forecast over the horizon are combined, either 'maximum' to use the highest value
+	// forecast over the next steps, or 'last' to use the value forecast for the final step.
+	// Default value is 'maximum'
+	// +kubebuilder:validation:Enum=maximum;last
+	// +optional
+	Aggregation *string `json:"aggregation"`
+}
+
+// Custom represents a user supplied prediction model configuration, with the model calculated by running a user
+// supplied algorithm
+type Custom struct {
+	// path is the path to the algorithm to run, for example a Python script mounted into the PHPA operator's container.
+	// Relative paths are relative to the PHPA operator's working directory.
+	Path string `json:"path"`
+
+	// runtime is how the algorithm should be run, either 'python' to run the algorithm as a Python script, or
+	// 'executable' to run the algorithm directly as an executable.
+	// Default value is 'python'
+	// +kubebuilder:validation:Enum=python;executable
+	// +optional
+	Runtime *string `json:"runtime"`
+
+	// historySize is how many timestamped replica counts should be stored for this model, with older timestamped
+	// replica counts being removed from the data as new ones are added.
+	// +kubebuilder:validation:Minimum=1
+	HistorySize int `json:"historySize"`
+
+	// parameters is arbitrary JSON that is passed through to the algorithm, allowing the algorithm to be configured.
+	// +optional
+	Parameters *apiextensionsv1.JSON `json:"parameters,omitempty"`
+}
+
+// Remote represents a prediction model that is calculated by an external prediction service
+type Remote struct {
+	// predictionHook is the hook to call to get a prediction, it is provided with the model and the replica history
+	// and must return the predicted replica count.
+	PredictionHook HookDefinition `json:"predictionHook"`
+
+	// historySize is how many timestamped replica counts should be stored for this model, with older timestamped
+	// replica counts being removed from the data as new ones are added.
+	// +kubebuilder:validation:Minimum=1
+	HistorySize int `json:"historySize"`
+}
+
+// HistoryBootstrap describes a source of historical replica counts used to fill an empty model replica history
+type HistoryBootstrap struct {
+	// type is the type of the source to bootstrap the history from, currently only 'Prometheus' is supported.
+	// +kubebuilder:validation:Enum=Prometheus
+	Type string `json:"type"`
+
+	// prometheus is the configuration to use for bootstrapping from Prometheus, it will only be used if the type is
+	// set to 'Prometheus'
+	// +optional
+	Prometheus *PrometheusHistoryBootstrap `json:"prometheus,omitempty"`
+}
+
+// PrometheusHistoryBootstrap describes a PromQL range query used to bootstrap a model's replica history
+type PrometheusHistoryBootstrap struct {
+	// address is the address of the Prometheus server to query, e.g. http://prometheus.monitoring:9090
+	Address string `json:"address"`
+
+	// query is the PromQL query to run, it must evaluate to a single series of the replica counts to use as the
+	// replica history. Any fractional values are rounded up to the nearest whole replica.
+	Query string `json:"query"`
+
+	// lookback is how far back from the current time to query for the replica history.
+	// This value is a string duration, e.g. 48h is 48 hours.
+	Lookback metav1.Duration `json:"lookback"`
+
+	// step is the resolution of the range query, the time between each replica count in the replica history.
+	// This value is a string duration, e.g. 2m30s is 2 minutes and 30 seconds.
+	// Default value is the sync period of the PHPA.
+	// +optional
+	Step *metav1.Duration `json:"step,omitempty"`
+
+	// timeout is how long the PHPA should allow for the query to complete in milliseconds.
+	// Default value is 30000 milliseconds (30 seconds)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout *int `json:"timeout,omitempty"`
+}
+
+// SARIMAOrder is the order of an ARIMA process, the number of autoregressive terms (p), the number of differences
+// (d) and the number of moving average terms (q)
+type SARIMAOrder struct {
+	// p is the number of autoregressive terms
+	// +kubebuilder:validation:Minimum=0
+	P int `json:"p"`
+
+	// d is the number of differences to apply to the series
+	// +kubebuilder:validation:Minimum=0
+	D int `json:"d"`
+
+	// q is the number of moving average terms
+	// +kubebuilder:validation:Minimum=0
+	Q int `json:"q"`
+}
+
+// SARIMA represents a seasonal autoregressive integrated moving average prediction model configuration
+type SARIMA struct {
+	// order is the (p,d,q) order of the non-seasonal part of the model
+	Order SARIMAOrder `json:"order"`
+
+	// seasonalOrder is the (P,D,Q) order of the seasonal part of the model, with the seasonal period (s) provided by
+	// seasonalPeriods. If not provided the model has no seasonal component (an ARIMA model).
+	// +optional
+	SeasonalOrder *SARIMAOrder `json:"seasonalOrder"`
+
+	// seasonalPeriods is the number of replica counts in a single season (s)
+	// +kubebuilder:validation:Minimum=1
+	SeasonalPeriods int `json:"seasonalPeriods"`
+
+	// storedSeasons is how many full seasons of replica counts should be stored for this model, with the oldest
+	// seasons being removed as new ones are added
+	// +kubebuilder:validation:Minimum=1
+	StoredSeasons int `json:"storedSeasons"`
+
+	// trend is the deterministic trend to include in the model, either 'n' for no trend, 'c' for a constant, 't' for
+	// a linear trend, or 'ct' for both a constant and a linear trend.
+	// Default value is 'n'
+	// +kubebuilder:validation:Enum=n;c;t;ct
+	// +optional
+	Trend *string `json:"trend"`
+}
+
+// MSTL represents a multiple seasonal-trend decomposition prediction model configuration, allowing for data with more
+// than one seasonal pattern, for example daily and weekly cycles
+type MSTL struct {
+	// seasonalPeriods is the list of season lengths in the data, each value is the number of replica counts in a single
+	// season. For example with a sync period of 1 hour, daily and weekly seasons would be [24, 168].
+	// +kubebuilder:validation:MinItems=1
+	SeasonalPeriods []int `json:"seasonalPeriods"`
+
+	// storedSeasons is how many of the longest seasons of replica counts should be stored for this model, with the
+	// oldest seasons being removed as new ones are added
+	// +kubebuilder:validation:Minimum=2
+	StoredSeasons int `json:"storedSeasons"`
 }
 
 // Model represents a prediction model to use, e.g. a linear regression
 type Model struct {
 	// type is the type of the model, for example 'Linear'. To see a full list of supported model types visit
 	// https://predictive-horizontal-pod-autoscaler.readthedocs.io/en/latest/user-guide/models/.
-	// +kubebuilder:validation:Enum=Linear;HoltWinters
+	// +kubebuilder:validation:Enum=Linear;HoltWinters;Custom;Remote;SARIMA;MSTL
 	Type string `json:"type"`
 
 	// name is the name of the model, this can be any arbitrary name and is just used to distinguish between models if
-	// you have multiple and to keep track of model data if you modify your model parameters.
+	// you have multiple and to keep track of model data if you modify your model parameters. The name 'calculated' is
+	// reserved for the replica count calculated from the current metrics.
 	Name string `json:"name"`
 
 	// startInterval is the next interval to start applying this model at. This allows you to make sure a model starts
@@ -150,6 +469,10 @@ type Model struct {
 	// takes longer than this timeout it should skip processing the model.
 	// Default varies based on model type:
 	// Linear is 30000 milliseconds (30 seconds)
+	// HoltWinters is 30000 milliseconds (30 seconds)
+	// Custom is 30000 milliseconds (30 seconds)
+	// SARIMA is 30000 milliseconds (30 seconds)
+	// MSTL is 30000 milliseconds (30 seconds)
 	// +kubebuilder:validation:Minimum=1
 	// +optional
 	CalculationTimeout *int `json:"calculationTimeout"`
@@ -164,6 +487,45 @@ type Model struct {
 	// +optional
 	PerSyncPeriod *int `json:"perSyncPeriod"`
 
+	// backend is the implementation to use to calculate the model, either 'python' to run the statsmodels based
+	// Python algorithm as a separate process, or 'native' to calculate the model in-process.
+	// Default varies based on model type:
+	// Linear is 'native'
+	// HoltWinters is 'python'
+	// +kubebuilder:validation:Enum=python;native
+	// +optional
+	Backend *string `json:"backend"`
+
+	// input is the data that the model is fed and forecasts, either 'replicas' to forecast the replica counts
+	// calculated from the metrics, or 'metrics' to forecast the raw values of the metrics in spec.metrics. When using
+	// 'metrics' the forecast metric values are used to calculate the predicted replica count in the same way the
+	// replica count is calculated from the current metric values.
+	// Only the Linear, HoltWinters, SARIMA and MSTL model types support 'metrics'.
+	// Default value is 'replicas'
+	// +kubebuilder:validation:Enum=replicas;metrics
+	// +optional
+	Input *string `json:"input"`
+
+	// weight is the weight given to this model's predicted replica count when using the 'weighted' decisionType, a
+	// model with a weight of 2 has twice the influence on the target replica count as a model with a weight of 1.
+	// Default value 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Weight *float64 `json:"weight"`
+
+	// fallback is the policy for excluding this model from the decision if its recent predictions have been
+	// inaccurate. While excluded the model keeps recording history and making predictions so its accuracy can be
+	// tracked, and it is included again once its accuracy recovers.
+	// +optional
+	Fallback *ModelFallback `json:"fallback"`
+
+	// historyBootstrap is a source of historical replica counts used to fill the model's replica history when the
+	// model has no history, for example when the model is first created or after it is reset. This allows models
+	// that need a lot of history, such as Holt Winters, to make predictions without waiting for the history to be
+	// gathered. Only supported for models using the 'replicas' input.
+	// +optional
+	HistoryBootstrap *HistoryBootstrap `json:"historyBootstrap,omitempty"`
+
 	// linear is the configuration to use for the linear regression model, it will only be used if the type is set to
 	// 'Linear'.
 	// +optional
@@ -173,6 +535,22 @@ type Model struct {
 	// 'HoltWinters'
 	// +optional
 	HoltWinters *HoltWinters `json:"holtWinters"`
+
+	// custom is the configuration to use for a custom model, it will only be used if the type is set to 'Custom'
+	// +optional
+	Custom *Custom `json:"custom"`
+
+	// remote is the configuration to use for a remote model, it will only be used if the type is set to 'Remote'
+	// +optional
+	Remote *Remote `json:"remote"`
+
+	// sarima is the configuration to use for the SARIMA model, it will only be used if the type is set to 'SARIMA'
+	// +optional
+	SARIMA *SARIMA `json:"sarima"`
+
+	// mstl is the configuration to use for the MSTL model, it will only be used if the type is set to 'MSTL'
+	// +optional
+	MSTL *MSTL `json:"mstl"`
 }
 
 // TimestampedReplicas is a replica count paired with the time that the replica count was created at.
@@ -183,11 +561,20 @@ type TimestampedReplicas struct {
 	Replicas int32 `json:"replicas"`
 }
 
-// PredictiveHorizontalPodAutoscalerData is the data storage format for the PHPA, this is stored in a ConfigMap
+// PredictiveHorizontalPodAutoscalerData is the model histories of the PHPA, this is stored in a PHPAModelState
 type PredictiveHorizontalPodAutoscalerData struct {
 	// modelHistories is a mapping of model names to model histories. This allows looking up a model's model history,
 	// while allowing all of the model histories for a single PHPA to be stored in a single place.
 	ModelHistories map[string]ModelHistory `json:"modelHistories"`
+
+	// replicaHistory is the shared history of the replica counts calculated each sync period, this is recorded
+	// independently of the models so that a model's replica history can be derived from it if the model is added,
+	// renamed or reconfigured.
+	ReplicaHistory []TimestampedReplicas `json:"replicaHistory,omitempty"`
+
+	// metricHistory is the shared history of the raw metric values gathered each sync period, this is only recorded
+	// if a model uses the 'metrics' input.
+	MetricHistory []TimestampedMetrics `json:"metricHistory,omitempty"`
 }
 
 // ModelHistory is the data stored for a single model's history, with all of the replica data the model is fed to
@@ -204,6 +591,51 @@ type ModelHistory struct {
 	// no data will be recorded and the model will be skipped.
 	// +optional
 	StartTime *metav1.Time `json:"startTime"`
+	// metricHistory is a list of timestamped raw metric values, this data is fed into the model to forecast metric
+	// values if the model's input is 'metrics'.
+	MetricHistory []TimestampedMetrics `json:"metricHistory,omitempty"`
+	// pendingPredictions is a list of the model's predictions that have not yet been scored, timestamped with the time
+	// that each prediction is for.
+	PendingPredictions []TimestampedReplicas `json:"pendingPredictions,omitempty"`
+	// scoredPredictions is a list of the model's most recent predictions paired with the replica count that was
+	// calculated at the time each prediction was for, used to calculate the accuracy of the model.
+	ScoredPredictions []ScoredPrediction `json:"scoredPredictions,omitempty"`
+	// excluded is if the model has been excluded from the decision by its fallback policy because of inaccurate
+	// predictions.
+	Excluded bool `json:"excluded,omitempty"`
+	// tunedParameters are the smoothing parameters fit to the replica history for Holt-Winters models using autoTune.
+	TunedParameters *HoltWintersTunedParameters `json:"tunedParameters,omitempty"`
+	// lastPrediction is the replica count most recently predicted by the model, timestamped with the time the model
+	// ran.
+	LastPrediction *TimestampedReplicas `json:"lastPrediction,omitempty"`
+}
+
+// ScoredPrediction is a prediction made by a model paired with the replica count that was calculated at the time the
+// prediction was for.
+type ScoredPrediction struct {
+	// time is the time that the prediction was for.
+	Time *metav1.Time `json:"time"`
+	// predicted is the replica count the model predicted.
+	Predicted int32 `json:"predicted"`
+	// actual is the replica count calculated from the metrics at the time the prediction was for.
+	Actual int32 `json:"actual"`
+}
+
+// TimestampedMetrics is the raw values of the metrics in spec.metrics paired with the time that they were gathered at.
+type TimestampedMetrics struct {
+	// time is the time that the metric values were gathered at.
+	Time *metav1.Time `json:"time"`
+	// values is the raw value of each metric, in the same order as the metrics are defined in spec.metrics. Values are
+	// in milli-units, with resource and pods metrics being the total value across every pod.
+	Values []int64 `json:"values"`
+}
+
+// TimestampedValue is a single value paired with the time that the value was recorded at.
+type TimestampedValue struct {
+	// time is the time that the value was recorded at.
+	Time *metav1.Time `json:"time"`
+	// value is the value at the time.
+	Value float64 `json:"value"`
 }
 
 // PredictiveHorizontalPodAutoscalerSpec defines the desired state of PredictiveHorizontalPodAutoscaler
@@ -277,9 +709,42 @@ type PredictiveHorizontalPodAutoscalerSpec struct {
 	// which decisionTypes are available visit
 	// https://predictive-horizontal-pod-autoscaler.readthedocs.io/en/latest/reference/configuration/#decisiontype
 	// Default strategy is 'maximum'
-	// +kubebuilder:validation:Enum=maximum;minimum;mean;median
+	// +kubebuilder:validation:Enum=maximum;minimum;mean;median;quantile;weighted
 	// +optional
 	DecisionType *string `json:"decisionType"`
+
+	// decisionQuantile is the quantile of the models' predictions to scale to when using the 'quantile' decisionType,
+	// for example 0.9 scales to the upper bound of an interval that the actual replica count is expected to fall below
+	// 90% of the time. Models that do not support prediction intervals use their predicted value.
+	// Default value 0.9.
+	// +kubebuilder:validation:Minimum=0.5
+	// +kubebuilder:validation:Maximum=1
+	// +kubebuilder:validation:ExclusiveMaximum=true
+	// +optional
+	DecisionQuantile *float64 `json:"decisionQuantile"`
+
+	// calculatedWeight is the weight given to the replica count calculated from the current metrics when using the
+	// 'weighted' decisionType, setting this to 0 means only the models' predictions are used.
+	// Default value 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CalculatedWeight *float64 `json:"calculatedWeight"`
+
+	// mode is whether the PHPA scales its target, either 'Active' to scale the target or 'DryRun' to run the full
+	// calculation and record the decided replica count in the status, events and metrics without scaling the target.
+	// Default value is 'Active'
+	// +kubebuilder:validation:Enum=Active;DryRun
+	// +optional
+	Mode *string `json:"mode"`
+
+	// sharedHistorySize is how many of the most recent calculated replica counts (and metric values if any model uses
+	// the 'metrics' input) should be stored in the PHPA's shared history. The shared history is recorded independently
+	// of the models, and a model's history is derived from it when the model is added, renamed, has its type changed
+	// or is reconfigured to store more history.
+	// Default value is the length of the longest model replica history.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SharedHistorySize *int `json:"sharedHistorySize"`
 }
 
 // PredictiveHorizontalPodAutoscalerStatus defines the observed state of PredictiveHorizontalPodAutoscaler
@@ -323,10 +788,97 @@ type PredictiveHorizontalPodAutoscalerStatus struct {
 	// as last calculated by the autoscaler.
 	DesiredReplicas int32 `json:"desiredReplicas"`
 
+	// calculatedReplicas is the replica count last calculated from the metrics, the value the HPA would calculate.
+	// +optional
+	CalculatedReplicas int32 `json:"calculatedReplicas,omitempty"`
+
+	// decidedReplicas is the replica count last decided from the calculated replicas and the models' predictions
+	// using the decision type, before the scaling behavior and min and max replicas are applied.
+	// +optional
+	DecidedReplicas int32 `json:"decidedReplicas,omitempty"`
+
 	// currentMetrics is the last read state of the metrics used by this autoscaler.
 	// +listType=atomic
 	// +optional
 	CurrentMetrics []autoscalingv2.MetricStatus `json:"currentMetrics"`
+
+	// models is the status of each of the models in spec.models.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Models []ModelStatus `json:"models,omitempty"`
+
+	// conditions is the set of conditions required for this autoscaler to scale its target, and indicates whether or
+	// not those conditions are met.
+	// +listType=map
+	// +listMapKey=type
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// ModelStatus is the observed state of a single model.
+type ModelStatus struct {
+	// name is the name of the model.
+	Name string `json:"name"`
+
+	// accuracy is how accurate the model's recent predictions have been, compared to the replica counts calculated at
+	// the times the predictions were for. Not set if none of the model's predictions have been scored yet.
+	// +optional
+	Accuracy *ModelAccuracy `json:"accuracy,omitempty"`
+
+	// excluded is if the model is currently excluded from the decision by its fallback policy because of inaccurate
+	// predictions.
+	// +optional
+	Excluded bool `json:"excluded,omitempty"`
+
+	// tunedParameters are the smoothing parameters currently fit to the replica history for Holt-Winters models
+	// using autoTune.
+	// +optional
+	TunedParameters *HoltWintersTunedParameters `json:"tunedParameters,omitempty"`
+
+	// ran is if the model ran and made a prediction in the last sync period.
+	Ran bool `json:"ran"`
+
+	// reason is why the model did not run in the last sync period, one of 'StartInterval', 'PerSyncPeriod',
+	// 'InsufficientHistory' or 'Error'. A model that ran but then failed to be processed also has a reason of 'Error'.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// message is a human readable explanation of the reason, such as the error the model failed with.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// lastPredictedReplicas is the replica count most recently predicted by the model.
+	// +optional
+	LastPredictedReplicas *int32 `json:"lastPredictedReplicas,omitempty"`
+
+	// lastRunTime is the last time the model ran and made a prediction.
+	// +optional
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+
+	// historyLength is the number of replica counts stored in the model's history.
+	HistoryLength int32 `json:"historyLength"`
+}
+
+// ModelAccuracy is a summary of the error of a model's recent predictions.
+type ModelAccuracy struct {
+	// samples is the number of scored predictions the accuracy is calculated from.
+	Samples int32 `json:"samples"`
+
+	// meanAbsoluteError is the mean absolute difference between the predicted and actual replica counts.
+	MeanAbsoluteError float64 `json:"meanAbsoluteError"`
+
+	// meanAbsolutePercentageError is the mean absolute difference between the predicted and actual replica counts as
+	// a percentage of the actual replica count. Predictions with an actual replica count of 0 are excluded, if there
+	// are no other predictions this is not set.
+	// +optional
+	MeanAbsolutePercentageError *float64 `json:"meanAbsolutePercentageError,omitempty"`
+
+	// bias is the mean difference between the predicted and actual replica counts, a positive bias means the model
+	// tends to over-predict and a negative bias means the model tends to under-predict.
+	Bias float64 `json:"bias"`
 }
 
 // +kubebuilder:object:root=true
@@ -336,6 +888,7 @@ type PredictiveHorizontalPodAutoscalerStatus struct {
 // +kubebuilder:printcolumn:name="Min Pods",type="integer",JSONPath=`.spec.minReplicas`,description="The minimum number of replicas of pods that the resource being managed by the autoscaler can have"
 // +kubebuilder:printcolumn:name="Max Pods",type="integer",JSONPath=`.spec.maxReplicas`,description="The maximum number of replicas of pods that the resource being managed by the autoscaler can have"
 // +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=`.status.desiredReplicas`,description="The desired number of replicas of pods managed by this autoscaler as last calculated by the autoscaler"
+// +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=`.spec.mode`,priority=1,description="Whether the PredictiveHorizontalPodAutoscaler scales the resource, or only records the replica count it decides"
 // +kubebuilder:printcolumn:name="Last Scale Time",type="date",JSONPath=`.status.lastScaleTime`,description="The last time the PredictiveHorizontalPodAutoscaler scaled the number of pods"
 // PredictiveHorizontalPodAutoscaler is the Schema for the predictivehorizontalpodautoscalers API
 type PredictiveHorizontalPodAutoscaler struct {
@@ -23,10 +23,116 @@ package v1alpha1
 
 import (
 	"k8s.io/api/autoscaling/v2"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompactMetricHistory) DeepCopyInto(out *CompactMetricHistory) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	if in.Intervals != nil {
+		in, out := &in.Intervals, &out.Intervals
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([][]int64, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make([]int64, len(*in))
+				copy(*out, *in)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompactMetricHistory.
+func (in *CompactMetricHistory) DeepCopy() *CompactMetricHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(CompactMetricHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompactReplicaHistory) DeepCopyInto(out *CompactReplicaHistory) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	if in.Intervals != nil {
+		in, out := &in.Intervals, &out.Intervals
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompactReplicaHistory.
+func (in *CompactReplicaHistory) DeepCopy() *CompactReplicaHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(CompactReplicaHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Custom) DeepCopyInto(out *Custom) {
+	*out = *in
+	if in.Runtime != nil {
+		in, out := &in.Runtime, &out.Runtime
+		*out = new(string)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Custom.
+func (in *Custom) DeepCopy() *Custom {
+	if in == nil {
+		return nil
+	}
+	out := new(Custom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCHook) DeepCopyInto(out *GRPCHook) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCHook.
+func (in *GRPCHook) DeepCopy() *GRPCHook {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPHook) DeepCopyInto(out *HTTPHook) {
 	*out = *in
@@ -54,6 +160,26 @@ func (in *HTTPHook) DeepCopy() *HTTPHook {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HistoryBootstrap) DeepCopyInto(out *HistoryBootstrap) {
+	*out = *in
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusHistoryBootstrap)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HistoryBootstrap.
+func (in *HistoryBootstrap) DeepCopy() *HistoryBootstrap {
+	if in == nil {
+		return nil
+	}
+	out := new(HistoryBootstrap)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HoltWinters) DeepCopyInto(out *HoltWinters) {
 	*out = *in
@@ -77,6 +203,11 @@ func (in *HoltWinters) DeepCopyInto(out *HoltWinters) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DampingTrend != nil {
+		in, out := &in.DampingTrend, &out.DampingTrend
+		*out = new(float64)
+		**out = **in
+	}
 	if in.InitializationMethod != nil {
 		in, out := &in.InitializationMethod, &out.InitializationMethod
 		*out = new(string)
@@ -102,6 +233,16 @@ func (in *HoltWinters) DeepCopyInto(out *HoltWinters) {
 		*out = new(HookDefinition)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LookAhead != nil {
+		in, out := &in.LookAhead, &out.LookAhead
+		*out = new(HoltWintersLookAhead)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoTune != nil {
+		in, out := &in.AutoTune, &out.AutoTune
+		*out = new(HoltWintersAutoTune)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HoltWinters.
@@ -114,6 +255,65 @@ func (in *HoltWinters) DeepCopy() *HoltWinters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HoltWintersAutoTune) DeepCopyInto(out *HoltWintersAutoTune) {
+	*out = *in
+	if in.RefitInterval != nil {
+		in, out := &in.RefitInterval, &out.RefitInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HoltWintersAutoTune.
+func (in *HoltWintersAutoTune) DeepCopy() *HoltWintersAutoTune {
+	if in == nil {
+		return nil
+	}
+	out := new(HoltWintersAutoTune)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HoltWintersLookAhead) DeepCopyInto(out *HoltWintersLookAhead) {
+	*out = *in
+	if in.Aggregation != nil {
+		in, out := &in.Aggregation, &out.Aggregation
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HoltWintersLookAhead.
+func (in *HoltWintersLookAhead) DeepCopy() *HoltWintersLookAhead {
+	if in == nil {
+		return nil
+	}
+	out := new(HoltWintersLookAhead)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HoltWintersTunedParameters) DeepCopyInto(out *HoltWintersTunedParameters) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HoltWintersTunedParameters.
+func (in *HoltWintersTunedParameters) DeepCopy() *HoltWintersTunedParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(HoltWintersTunedParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HookDefinition) DeepCopyInto(out *HookDefinition) {
 	*out = *in
@@ -122,6 +322,11 @@ func (in *HookDefinition) DeepCopyInto(out *HookDefinition) {
 		*out = new(HTTPHook)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(GRPCHook)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookDefinition.
@@ -149,6 +354,26 @@ func (in *Linear) DeepCopy() *Linear {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MSTL) DeepCopyInto(out *MSTL) {
+	*out = *in
+	if in.SeasonalPeriods != nil {
+		in, out := &in.SeasonalPeriods, &out.SeasonalPeriods
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MSTL.
+func (in *MSTL) DeepCopy() *MSTL {
+	if in == nil {
+		return nil
+	}
+	out := new(MSTL)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Model) DeepCopyInto(out *Model) {
 	*out = *in
@@ -172,6 +397,31 @@ func (in *Model) DeepCopyInto(out *Model) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(string)
+		**out = **in
+	}
+	if in.Input != nil {
+		in, out := &in.Input, &out.Input
+		*out = new(string)
+		**out = **in
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Fallback != nil {
+		in, out := &in.Fallback, &out.Fallback
+		*out = new(ModelFallback)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HistoryBootstrap != nil {
+		in, out := &in.HistoryBootstrap, &out.HistoryBootstrap
+		*out = new(HistoryBootstrap)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Linear != nil {
 		in, out := &in.Linear, &out.Linear
 		*out = new(Linear)
@@ -182,6 +432,26 @@ func (in *Model) DeepCopyInto(out *Model) {
 		*out = new(HoltWinters)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(Custom)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remote != nil {
+		in, out := &in.Remote, &out.Remote
+		*out = new(Remote)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SARIMA != nil {
+		in, out := &in.SARIMA, &out.SARIMA
+		*out = new(SARIMA)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MSTL != nil {
+		in, out := &in.MSTL, &out.MSTL
+		*out = new(MSTL)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Model.
@@ -194,6 +464,56 @@ func (in *Model) DeepCopy() *Model {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelAccuracy) DeepCopyInto(out *ModelAccuracy) {
+	*out = *in
+	if in.MeanAbsolutePercentageError != nil {
+		in, out := &in.MeanAbsolutePercentageError, &out.MeanAbsolutePercentageError
+		*out = new(float64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelAccuracy.
+func (in *ModelAccuracy) DeepCopy() *ModelAccuracy {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelAccuracy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelFallback) DeepCopyInto(out *ModelFallback) {
+	*out = *in
+	if in.AccuracyMetric != nil {
+		in, out := &in.AccuracyMetric, &out.AccuracyMetric
+		*out = new(string)
+		**out = **in
+	}
+	if in.RecoveryThreshold != nil {
+		in, out := &in.RecoveryThreshold, &out.RecoveryThreshold
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MinimumSamples != nil {
+		in, out := &in.MinimumSamples, &out.MinimumSamples
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelFallback.
+func (in *ModelFallback) DeepCopy() *ModelFallback {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelFallback)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelHistory) DeepCopyInto(out *ModelHistory) {
 	*out = *in
@@ -208,6 +528,37 @@ func (in *ModelHistory) DeepCopyInto(out *ModelHistory) {
 		in, out := &in.StartTime, &out.StartTime
 		*out = (*in).DeepCopy()
 	}
+	if in.MetricHistory != nil {
+		in, out := &in.MetricHistory, &out.MetricHistory
+		*out = make([]TimestampedMetrics, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PendingPredictions != nil {
+		in, out := &in.PendingPredictions, &out.PendingPredictions
+		*out = make([]TimestampedReplicas, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScoredPredictions != nil {
+		in, out := &in.ScoredPredictions, &out.ScoredPredictions
+		*out = make([]ScoredPrediction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TunedParameters != nil {
+		in, out := &in.TunedParameters, &out.TunedParameters
+		*out = new(HoltWintersTunedParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastPrediction != nil {
+		in, out := &in.LastPrediction, &out.LastPrediction
+		*out = new(TimestampedReplicas)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelHistory.
@@ -220,6 +571,183 @@ func (in *ModelHistory) DeepCopy() *ModelHistory {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelState) DeepCopyInto(out *ModelState) {
+	*out = *in
+	if in.ReplicaHistory != nil {
+		in, out := &in.ReplicaHistory, &out.ReplicaHistory
+		*out = new(CompactReplicaHistory)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.MetricHistory != nil {
+		in, out := &in.MetricHistory, &out.MetricHistory
+		*out = new(CompactMetricHistory)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingPredictions != nil {
+		in, out := &in.PendingPredictions, &out.PendingPredictions
+		*out = make([]TimestampedReplicas, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScoredPredictions != nil {
+		in, out := &in.ScoredPredictions, &out.ScoredPredictions
+		*out = make([]ScoredPrediction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TunedParameters != nil {
+		in, out := &in.TunedParameters, &out.TunedParameters
+		*out = new(HoltWintersTunedParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastPrediction != nil {
+		in, out := &in.LastPrediction, &out.LastPrediction
+		*out = new(TimestampedReplicas)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelState.
+func (in *ModelState) DeepCopy() *ModelState {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelStatus) DeepCopyInto(out *ModelStatus) {
+	*out = *in
+	if in.Accuracy != nil {
+		in, out := &in.Accuracy, &out.Accuracy
+		*out = new(ModelAccuracy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TunedParameters != nil {
+		in, out := &in.TunedParameters, &out.TunedParameters
+		*out = new(HoltWintersTunedParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastPredictedReplicas != nil {
+		in, out := &in.LastPredictedReplicas, &out.LastPredictedReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LastRunTime != nil {
+		in, out := &in.LastRunTime, &out.LastRunTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelStatus.
+func (in *ModelStatus) DeepCopy() *ModelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PHPAModelState) DeepCopyInto(out *PHPAModelState) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PHPAModelState.
+func (in *PHPAModelState) DeepCopy() *PHPAModelState {
+	if in == nil {
+		return nil
+	}
+	out := new(PHPAModelState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PHPAModelState) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PHPAModelStateList) DeepCopyInto(out *PHPAModelStateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PHPAModelState, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PHPAModelStateList.
+func (in *PHPAModelStateList) DeepCopy() *PHPAModelStateList {
+	if in == nil {
+		return nil
+	}
+	out := new(PHPAModelStateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PHPAModelStateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PHPAModelStateSpec) DeepCopyInto(out *PHPAModelStateSpec) {
+	*out = *in
+	if in.ModelHistories != nil {
+		in, out := &in.ModelHistories, &out.ModelHistories
+		*out = make(map[string]ModelState, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ReplicaHistory != nil {
+		in, out := &in.ReplicaHistory, &out.ReplicaHistory
+		*out = new(CompactReplicaHistory)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricHistory != nil {
+		in, out := &in.MetricHistory, &out.MetricHistory
+		*out = new(CompactMetricHistory)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PHPAModelStateSpec.
+func (in *PHPAModelStateSpec) DeepCopy() *PHPAModelStateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PHPAModelStateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PredictiveHorizontalPodAutoscaler) DeepCopyInto(out *PredictiveHorizontalPodAutoscaler) {
 	*out = *in
@@ -257,6 +785,20 @@ func (in *PredictiveHorizontalPodAutoscalerData) DeepCopyInto(out *PredictiveHor
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.ReplicaHistory != nil {
+		in, out := &in.ReplicaHistory, &out.ReplicaHistory
+		*out = make([]TimestampedReplicas, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MetricHistory != nil {
+		in, out := &in.MetricHistory, &out.MetricHistory
+		*out = make([]TimestampedMetrics, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PredictiveHorizontalPodAutoscalerData.
@@ -354,6 +896,26 @@ func (in *PredictiveHorizontalPodAutoscalerSpec) DeepCopyInto(out *PredictiveHor
 		*out = new(string)
 		**out = **in
 	}
+	if in.DecisionQuantile != nil {
+		in, out := &in.DecisionQuantile, &out.DecisionQuantile
+		*out = new(float64)
+		**out = **in
+	}
+	if in.CalculatedWeight != nil {
+		in, out := &in.CalculatedWeight, &out.CalculatedWeight
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(string)
+		**out = **in
+	}
+	if in.SharedHistorySize != nil {
+		in, out := &in.SharedHistorySize, &out.SharedHistorySize
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PredictiveHorizontalPodAutoscalerSpec.
@@ -408,6 +970,20 @@ func (in *PredictiveHorizontalPodAutoscalerStatus) DeepCopyInto(out *PredictiveH
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Models != nil {
+		in, out := &in.Models, &out.Models
+		*out = make([]ModelStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PredictiveHorizontalPodAutoscalerStatus.
@@ -420,6 +996,132 @@ func (in *PredictiveHorizontalPodAutoscalerStatus) DeepCopy() *PredictiveHorizon
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusHistoryBootstrap) DeepCopyInto(out *PrometheusHistoryBootstrap) {
+	*out = *in
+	out.Lookback = in.Lookback
+	if in.Step != nil {
+		in, out := &in.Step, &out.Step
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusHistoryBootstrap.
+func (in *PrometheusHistoryBootstrap) DeepCopy() *PrometheusHistoryBootstrap {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusHistoryBootstrap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Remote) DeepCopyInto(out *Remote) {
+	*out = *in
+	in.PredictionHook.DeepCopyInto(&out.PredictionHook)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Remote.
+func (in *Remote) DeepCopy() *Remote {
+	if in == nil {
+		return nil
+	}
+	out := new(Remote)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SARIMA) DeepCopyInto(out *SARIMA) {
+	*out = *in
+	out.Order = in.Order
+	if in.SeasonalOrder != nil {
+		in, out := &in.SeasonalOrder, &out.SeasonalOrder
+		*out = new(SARIMAOrder)
+		**out = **in
+	}
+	if in.Trend != nil {
+		in, out := &in.Trend, &out.Trend
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SARIMA.
+func (in *SARIMA) DeepCopy() *SARIMA {
+	if in == nil {
+		return nil
+	}
+	out := new(SARIMA)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SARIMAOrder) DeepCopyInto(out *SARIMAOrder) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SARIMAOrder.
+func (in *SARIMAOrder) DeepCopy() *SARIMAOrder {
+	if in == nil {
+		return nil
+	}
+	out := new(SARIMAOrder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScoredPrediction) DeepCopyInto(out *ScoredPrediction) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScoredPrediction.
+func (in *ScoredPrediction) DeepCopy() *ScoredPrediction {
+	if in == nil {
+		return nil
+	}
+	out := new(ScoredPrediction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimestampedMetrics) DeepCopyInto(out *TimestampedMetrics) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimestampedMetrics.
+func (in *TimestampedMetrics) DeepCopy() *TimestampedMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(TimestampedMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TimestampedReplicas) DeepCopyInto(out *TimestampedReplicas) {
 	*out = *in
@@ -438,3 +1140,22 @@ func (in *TimestampedReplicas) DeepCopy() *TimestampedReplicas {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimestampedValue) DeepCopyInto(out *TimestampedValue) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimestampedValue.
+func (in *TimestampedValue) DeepCopy() *TimestampedValue {
+	if in == nil {
+		return nil
+	}
+	out := new(TimestampedValue)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,354 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-phpa is a kubectl plugin for managing PredictiveHorizontalPodAutoscalers. It provides the
+// 'history export' and 'history import' commands for moving a PHPA's model histories between PHPAs, for example
+// when moving a workload to a different cluster, without losing the history the models have gathered.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history/configmap"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history/modelstate"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history/transfer"
+)
+
+const usage = `Manage PredictiveHorizontalPodAutoscalers.
+
+Usage:
+  kubectl phpa history export NAME [flags]
+  kubectl phpa history import NAME -f FILE [flags]
+
+Run 'kubectl phpa history export -h' or 'kubectl phpa history import -h' for the flags of each command.
+`
+
+// connection holds the flags used to connect to the cluster and find the PHPA's model histories
+type connection struct {
+	kubeconfig   string
+	context      string
+	namespace    string
+	historyStore string
+}
+
+func (c *connection) bindFlags(flags *flag.FlagSet) {
+	flags.StringVar(&c.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file to use.")
+	flags.StringVar(&c.context, "context", "", "The name of the kubeconfig context to use.")
+	flags.StringVar(&c.namespace, "namespace", "", "The namespace of the PHPA. Defaults to the context's namespace.")
+	flags.StringVar(&c.namespace, "n", "", "Shorthand for -namespace.")
+	flags.StringVar(&c.historyStore, "history-store", history.TypeConfigMap,
+		"Where the operator stores model histories, either 'configmap' or 'modelstate'. Must match the operator's "+
+			"--history-store flag.")
+}
+
+// connect sets up a client for the cluster, returning it along with the namespace to use and the history store
+func (c *connection) connect() (client.Client, string, history.Store, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = c.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{
+		CurrentContext: c.context,
+		Context: clientcmdapi.Context{
+			Namespace: c.namespace,
+		},
+	})
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to get namespace: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(jamiethompsonmev1alpha1.AddToScheme(scheme))
+
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to set up client: %w", err)
+	}
+
+	var store history.Store
+	switch c.historyStore {
+	case history.TypeConfigMap:
+		store = &configmap.Store{
+			Client: k8sClient,
+		}
+	case history.TypeModelState:
+		store = &modelstate.Store{
+			Client: k8sClient,
+			// Events are discarded, the plugin reports any failures itself
+			Recorder: &record.FakeRecorder{},
+		}
+	default:
+		return nil, "", nil, fmt.Errorf("unknown history store '%s'", c.historyStore)
+	}
+
+	return k8sClient, namespace, store, nil
+}
+
+func main() {
+	err := run(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kubectl-phpa: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) < 2 || args[0] != "history" {
+		fmt.Fprint(os.Stderr, usage)
+		return errors.New("unknown command")
+	}
+
+	switch args[1] {
+	case "export":
+		return runExport(args[2:])
+	case "import":
+		return runImport(args[2:])
+	}
+
+	fmt.Fprint(os.Stderr, usage)
+	return fmt.Errorf("unknown history command '%s'", args[1])
+}
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("kubectl phpa history export", flag.ContinueOnError)
+	conn := &connection{}
+	conn.bindFlags(flags)
+	var format string
+	var output string
+	flags.StringVar(&format, "format", "",
+		"The format to export the history in, either 'json' or 'csv'. Defaults to the file extension of the output, "+
+			"or 'json' if writing to stdout.")
+	flags.StringVar(&output, "output", "", "Path of the file to write the history to. Defaults to stdout.")
+	flags.StringVar(&output, "o", "", "Shorthand for -output.")
+
+	name, err := parseName(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if format == "" {
+		format = formatFromPath(output)
+	}
+
+	if format != transfer.FormatJSON && format != transfer.FormatCSV {
+		return fmt.Errorf("unknown history format '%s'", format)
+	}
+
+	ctx := context.Background()
+
+	instance, store, err := getPHPA(ctx, conn, name)
+	if err != nil {
+		return err
+	}
+
+	data, err := store.Read(ctx, instance)
+	if err != nil {
+		return fmt.Errorf("failed to get the model history: %w", err)
+	}
+
+	var writer io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create output: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	return transfer.Write(writer, data, format)
+}
+
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("kubectl phpa history import", flag.ContinueOnError)
+	conn := &connection{}
+	conn.bindFlags(flags)
+	var format string
+	var filename string
+	var overwrite bool
+	var dryRun bool
+	flags.StringVar(&format, "format", "",
+		"The format of the history to import, either 'json' or 'csv'. Defaults to the file extension of the file, "+
+			"or 'json' if reading from stdin.")
+	flags.StringVar(&filename, "filename", "", "Path of the file to read the history from, '-' reads from stdin.")
+	flags.StringVar(&filename, "f", "", "Shorthand for -filename.")
+	flags.BoolVar(&overwrite, "overwrite", false,
+		"Replace the history of models that already have a stored history, by default the import fails instead.")
+	flags.BoolVar(&dryRun, "dry-run", false, "Validate the history without storing it.")
+
+	name, err := parseName(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if filename == "" {
+		return errors.New("-filename must be provided")
+	}
+
+	var reader io.Reader = os.Stdin
+	if filename != "-" {
+		file, err := os.Open(filename)
+		if err != nil {
+			return fmt.Errorf("failed to open history: %w", err)
+		}
+		defer file.Close()
+		reader = file
+
+		if format == "" {
+			format = formatFromPath(filename)
+		}
+	}
+
+	if format == "" {
+		format = transfer.FormatJSON
+	}
+
+	imported, err := transfer.Read(reader, format)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	instance, store, err := getPHPA(ctx, conn, name)
+	if err != nil {
+		return err
+	}
+
+	err = transfer.Validate(instance, imported)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		existing, err := store.Read(ctx, instance)
+		if err != nil {
+			return fmt.Errorf("failed to get the model history: %w", err)
+		}
+
+		_, err = transfer.Merge(existing, imported, overwrite)
+		if err != nil {
+			return fmt.Errorf("%w, use -overwrite to replace it", err)
+		}
+
+		fmt.Printf("History for %d models of PHPA '%s' is valid, not stored (dry run)\n", len(imported.ModelHistories),
+			name)
+		return nil
+	}
+
+	// The operator stores the model history every sync, so if it is changed between being loaded and stored the
+	// import is merged with the latest model history again rather than overwriting the operator's changes
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existing, version, err := store.Load(ctx, instance)
+		if err != nil {
+			return fmt.Errorf("failed to get the model history: %w", err)
+		}
+
+		merged, err := transfer.Merge(existing, imported, overwrite)
+		if err != nil {
+			return fmt.Errorf("%w, use -overwrite to replace it", err)
+		}
+
+		err = store.Save(ctx, instance, merged, version)
+		if err != nil {
+			return fmt.Errorf("failed to update the model history: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported history for %d models of PHPA '%s'\n", len(imported.ModelHistories), name)
+	return nil
+}
+
+// getPHPA connects to the cluster and gets the PHPA with the name provided, along with the history store to use
+func getPHPA(ctx context.Context, conn *connection,
+	name string) (*jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, history.Store, error) {
+	k8sClient, namespace, store, err := conn.connect()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	instance := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{}
+	err = k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, instance)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get PHPA: %w", err)
+	}
+
+	return instance, store, nil
+}
+
+// parseName parses the flags, allowing them to be provided before or after the PHPA name, and returns the PHPA name
+func parseName(flags *flag.FlagSet, args []string) (string, error) {
+	var positional []string
+	for {
+		err := flags.Parse(args)
+		if err != nil {
+			return "", err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) != 1 {
+		return "", errors.New("exactly one PHPA name must be provided")
+	}
+
+	return positional[0], nil
+}
+
+// formatFromPath returns the format matching the file extension of the path, defaulting to JSON
+func formatFromPath(path string) string {
+	extension := strings.TrimPrefix(filepath.Ext(path), ".")
+	if extension == "" {
+		return transfer.FormatJSON
+	}
+	return extension
+}
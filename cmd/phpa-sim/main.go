@@ -0,0 +1,259 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// phpa-sim replays a recorded time series of calculated replica counts or metric values against a PHPA spec offline,
+// using a virtual clock to run the PHPA's models, decision type and scaling behavior. It outputs the resulting replica
+// timeline, how well the target would have been provisioned and the accuracy of each model, allowing model and
+// behavior configuration to be tuned without deploying to a cluster.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jthomperoo/k8shorizmetrics/v2"
+	"k8s.io/client-go/tools/record"
+	testingclock "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/algorithm"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/controllers"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/hook"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/hook/grpc"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/hook/http"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/custom"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/holtwinters"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/linear"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/mstl"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/remote"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/sarima"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/simulate"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/validation"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+func main() {
+	var phpaPath string
+	var seriesPath string
+	var format string
+	var output string
+	var initialReplicas int
+	var start string
+	var customAlgorithmDirectory string
+	flag.StringVar(&phpaPath, "phpa", "", "Path to the PredictiveHorizontalPodAutoscaler YAML to simulate.")
+	flag.StringVar(&seriesPath, "series", "", "Path to the CSV or JSON time series of replica counts or metric values.")
+	flag.StringVar(&format, "format", "",
+		"The format of the time series, either 'csv' or 'json'. Defaults to the file extension of the series.")
+	flag.StringVar(&output, "output", outputText, "The output format, either 'text' or 'json'.")
+	flag.IntVar(&initialReplicas, "initial-replicas", 0,
+		"The replica count of the target at the start of the simulation. Defaults to the PHPA's minReplicas.")
+	flag.StringVar(&start, "start", "",
+		"The RFC 3339 time the simulation starts at if the series does not include times. Defaults to now.")
+	flag.StringVar(&customAlgorithmDirectory, "custom-algorithm-dir", "",
+		"The directory Custom model algorithms must be inside, any algorithm outside of it is rejected. "+
+			"If not set Custom models cannot be used.")
+	opts := zap.Options{
+		Development: true,
+	}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+
+	err := run(phpaPath, seriesPath, format, output, int32(initialReplicas), start, customAlgorithmDirectory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "phpa-sim: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(phpaPath string, seriesPath string, format string, output string, initialReplicas int32,
+	start string, customAlgorithmDirectory string) error {
+	if phpaPath == "" || seriesPath == "" {
+		return fmt.Errorf("both -phpa and -series must be provided")
+	}
+
+	if output != outputText && output != outputJSON {
+		return fmt.Errorf("unknown output format '%s'", output)
+	}
+
+	phpaData, err := os.ReadFile(phpaPath)
+	if err != nil {
+		return fmt.Errorf("failed to read PHPA: %w", err)
+	}
+
+	instance := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{}
+	err = yaml.UnmarshalStrict(phpaData, instance)
+	if err != nil {
+		return fmt.Errorf("failed to parse PHPA: %w", err)
+	}
+
+	err = validation.Validate(instance, customAlgorithmDirectory)
+	if err != nil {
+		return fmt.Errorf("invalid PHPA: %w", err)
+	}
+
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(seriesPath), ".")
+	}
+
+	seriesFile, err := os.Open(seriesPath)
+	if err != nil {
+		return fmt.Errorf("failed to open series: %w", err)
+	}
+	defer seriesFile.Close()
+
+	series, err := simulate.ReadSeries(seriesFile, format)
+	if err != nil {
+		return err
+	}
+
+	startTime := time.Now().UTC().Truncate(time.Second)
+	if start != "" {
+		startTime, err = time.Parse(time.RFC3339, start)
+		if err != nil {
+			return fmt.Errorf("invalid start time: %w", err)
+		}
+	}
+
+	if initialReplicas == 0 {
+		initialReplicas = 1
+		if instance.Spec.MinReplicas != nil {
+			initialReplicas = *instance.Spec.MinReplicas
+		}
+	}
+
+	// The simulator sets the clock to the time of each sync period, with the reconciler and predicters using it
+	// to make their calculations
+	simClock := testingclock.NewFakePassiveClock(startTime)
+
+	pyRunner := algorithm.NewAlgorithmPython()
+
+	grpcExec := &grpc.Execute{}
+	defer grpcExec.Close()
+	hookExec := &hook.CombinedExecute{
+		Executers: []hook.Executer{
+			&http.Execute{},
+			grpcExec,
+		},
+	}
+
+	simulator := &simulate.Simulator{
+		Clock: simClock,
+		Reconciler: &controllers.PredictiveHorizontalPodAutoscalerReconciler{
+			Evaluator: *k8shorizmetrics.NewEvaluator(0.1),
+			// Events are discarded, any failures are logged
+			Recorder: &record.FakeRecorder{},
+			// Calculate each model's accuracy across the entire simulation
+			AccuracyWindow: len(series),
+			Clock:          simClock,
+			Predicter: &prediction.ModelPredict{
+				Predicters: []prediction.Predicter{
+					&linear.Predict{
+						Runner: pyRunner,
+						Clock:  simClock,
+					},
+					&holtwinters.Predict{
+						HookExecute: hookExec,
+						Runner:      pyRunner,
+					},
+					&custom.Predict{
+						PythonRunner:     pyRunner,
+						ExecutableRunner: algorithm.NewAlgorithmExecutable(),
+						AllowedDirectory: customAlgorithmDirectory,
+					},
+					&remote.Predict{
+						HookExecute: hookExec,
+					},
+					&sarima.Predict{
+						Runner: pyRunner,
+					},
+					&mstl.Predict{
+						Runner: pyRunner,
+					},
+				},
+			},
+		},
+	}
+
+	result, err := simulator.Run(context.Background(), instance, series, startTime, initialReplicas)
+	if err != nil {
+		return err
+	}
+
+	if output == outputJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	printResult(result)
+	return nil
+}
+
+func printResult(result *simulate.Result) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "TIME\tCURRENT\tCALCULATED\tDECIDED\tTARGET")
+	for _, step := range result.Timeline {
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%d\t%d\n", step.Time.Format(time.RFC3339), step.CurrentReplicas,
+			step.CalculatedReplicas, step.DecidedReplicas, step.TargetReplicas)
+	}
+	writer.Flush()
+
+	provisioning := result.Provisioning
+	fmt.Println()
+	fmt.Printf("Sync periods: %d\n", provisioning.Syncs)
+	fmt.Printf("Under provisioned: %d sync periods, %d replicas missing in total\n",
+		provisioning.UnderProvisionedSyncs, provisioning.UnderProvisionedReplicas)
+	fmt.Printf("Over provisioned: %d sync periods, %d extra replicas in total\n",
+		provisioning.OverProvisionedSyncs, provisioning.OverProvisionedReplicas)
+
+	if len(result.Models) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Fprintln(writer, "MODEL\tSAMPLES\tMAE\tMAPE\tBIAS")
+	for _, model := range result.Models {
+		if model.Accuracy == nil {
+			fmt.Fprintf(writer, "%s\t0\t-\t-\t-\n", model.Name)
+			continue
+		}
+		mape := "-"
+		if model.Accuracy.MeanAbsolutePercentageError != nil {
+			mape = fmt.Sprintf("%.2f%%", *model.Accuracy.MeanAbsolutePercentageError)
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%.2f\t%s\t%.2f\n", model.Name, model.Accuracy.Samples,
+			model.Accuracy.MeanAbsoluteError, mape, model.Accuracy.Bias)
+	}
+	writer.Flush()
+}
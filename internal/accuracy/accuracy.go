@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package accuracy provides functionality for tracking how accurate a model's predictions are, recording each
+// prediction until the time it is for and then scoring it against the replica count calculated at that time, and for
+// deciding if a model should be excluded from the decision because of inaccurate predictions.
+package accuracy
+
+import (
+	"math"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+)
+
+const defaultMinimumSamples = 5
+
+// Score scores any pending predictions in the model history against the actual replica count, keeping only the most
+// recent scored predictions up to the window size. Each prediction is scored at the first sync period at or after the
+// time it is for, allowing half a sync period early to account for variation in when the sync period runs, so
+// predictions for times between sync periods (such as a look ahead shorter than the sync period) are still scored.
+// Pending predictions that should have been scored in an earlier sync period (for example if the PHPA has not been
+// running) are dropped without being scored since there is no replica count to compare them to.
+func Score(modelHistory *jamiethompsonmev1alpha1.ModelHistory, actual int32, now time.Time, syncPeriod time.Duration,
+	window int) {
+	// A prediction is due if this sync period is the closest one to it or after it
+	latest := now.Add(syncPeriod / 2)
+	// Any prediction before this would have been due in the previous sync period
+	earliest := now.Add(-syncPeriod - syncPeriod/2)
+
+	var pending []jamiethompsonmev1alpha1.TimestampedReplicas
+	for _, prediction := range modelHistory.PendingPredictions {
+		if prediction.Time.Time.After(latest) {
+			pending = append(pending, prediction)
+			continue
+		}
+
+		if prediction.Time.Time.Before(earliest) {
+			continue
+		}
+
+		modelHistory.ScoredPredictions = append(modelHistory.ScoredPredictions, jamiethompsonmev1alpha1.ScoredPrediction{
+			Time:      &metav1.Time{Time: prediction.Time.Time},
+			Predicted: prediction.Replicas,
+			Actual:    actual,
+		})
+	}
+	modelHistory.PendingPredictions = pending
+
+	if len(modelHistory.ScoredPredictions) > window {
+		modelHistory.ScoredPredictions = modelHistory.ScoredPredictions[len(modelHistory.ScoredPredictions)-window:]
+	}
+}
+
+// Calculate calculates the accuracy of a model from its scored predictions, returning nil if there are no scored
+// predictions
+func Calculate(scoredPredictions []jamiethompsonmev1alpha1.ScoredPrediction) *jamiethompsonmev1alpha1.ModelAccuracy {
+	if len(scoredPredictions) == 0 {
+		return nil
+	}
+
+	totalError := float64(0)
+	totalAbsoluteError := float64(0)
+	totalAbsolutePercentageError := float64(0)
+	percentageSamples := 0
+	for _, scoredPrediction := range scoredPredictions {
+		predictionError := float64(scoredPrediction.Predicted - scoredPrediction.Actual)
+		totalError += predictionError
+		totalAbsoluteError += math.Abs(predictionError)
+
+		// Percentage error is undefined if the actual value is 0
+		if scoredPrediction.Actual != 0 {
+			totalAbsolutePercentageError += math.Abs(predictionError/float64(scoredPrediction.Actual)) * 100
+			percentageSamples++
+		}
+	}
+
+	samples := float64(len(scoredPredictions))
+
+	accuracy := &jamiethompsonmev1alpha1.ModelAccuracy{
+		Samples:           int32(len(scoredPredictions)),
+		MeanAbsoluteError: totalAbsoluteError / samples,
+		Bias:              totalError / samples,
+	}
+
+	if percentageSamples > 0 {
+		meanAbsolutePercentageError := totalAbsolutePercentageError / float64(percentageSamples)
+		accuracy.MeanAbsolutePercentageError = &meanAbsolutePercentageError
+	}
+
+	return accuracy
+}
+
+// Exclude decides if a model should be excluded from the decision based on its fallback policy and accuracy, given if
+// the model is currently excluded. A model is excluded once its error is above the threshold, and is included again
+// once its error is at or below the recovery threshold. If there are not yet enough samples to judge the model's
+// accuracy the model stays in its current state.
+func Exclude(fallback *jamiethompsonmev1alpha1.ModelFallback, accuracy *jamiethompsonmev1alpha1.ModelAccuracy,
+	excluded bool) bool {
+	if fallback == nil {
+		return false
+	}
+
+	minimumSamples := int32(defaultMinimumSamples)
+	if fallback.MinimumSamples != nil {
+		minimumSamples = *fallback.MinimumSamples
+	}
+
+	if accuracy == nil || accuracy.Samples < minimumSamples {
+		return excluded
+	}
+
+	accuracyMetric := jamiethompsonmev1alpha1.AccuracyMeanAbsolutePercentageError
+	if fallback.AccuracyMetric != nil {
+		accuracyMetric = *fallback.AccuracyMetric
+	}
+
+	var modelError float64
+	switch accuracyMetric {
+	case jamiethompsonmev1alpha1.AccuracyMeanAbsoluteError:
+		modelError = accuracy.MeanAbsoluteError
+	case jamiethompsonmev1alpha1.AccuracyMeanAbsolutePercentageError:
+		if accuracy.MeanAbsolutePercentageError == nil {
+			// No percentage error if every actual replica count was 0, can't judge the model
+			return excluded
+		}
+		modelError = *accuracy.MeanAbsolutePercentageError
+	default:
+		return excluded
+	}
+
+	if excluded {
+		recoveryThreshold := fallback.Threshold
+		if fallback.RecoveryThreshold != nil {
+			recoveryThreshold = *fallback.RecoveryThreshold
+		}
+		return modelError > recoveryThreshold
+	}
+
+	return modelError > fallback.Threshold
+}
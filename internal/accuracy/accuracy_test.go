@@ -0,0 +1,402 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accuracy_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/accuracy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestScore(t *testing.T) {
+	now := time.Date(2020, time.February, 1, 0, 55, 30, 0, time.UTC)
+
+	var tests = []struct {
+		description  string
+		expected     jamiethompsonmev1alpha1.ModelHistory
+		modelHistory jamiethompsonmev1alpha1.ModelHistory
+		actual       int32
+		now          time.Time
+		syncPeriod   time.Duration
+		window       int
+	}{
+		{
+			description:  "No pending predictions",
+			expected:     jamiethompsonmev1alpha1.ModelHistory{},
+			modelHistory: jamiethompsonmev1alpha1.ModelHistory{},
+			actual:       5,
+			now:          now,
+			syncPeriod:   10 * time.Second,
+			window:       10,
+		},
+		{
+			description: "Score due predictions, keep future predictions, drop missed predictions",
+			expected: jamiethompsonmev1alpha1.ModelHistory{
+				PendingPredictions: []jamiethompsonmev1alpha1.TimestampedReplicas{
+					{
+						Time:     &metav1.Time{Time: now.Add(15 * time.Second)},
+						Replicas: 8,
+					},
+				},
+				ScoredPredictions: []jamiethompsonmev1alpha1.ScoredPrediction{
+					{
+						Time:      &metav1.Time{Time: now.Add(-30 * time.Second)},
+						Predicted: 3,
+						Actual:    4,
+					},
+					{
+						Time:      &metav1.Time{Time: now.Add(-2 * time.Second)},
+						Predicted: 6,
+						Actual:    5,
+					},
+					{
+						Time:      &metav1.Time{Time: now.Add(3 * time.Second)},
+						Predicted: 7,
+						Actual:    5,
+					},
+				},
+			},
+			modelHistory: jamiethompsonmev1alpha1.ModelHistory{
+				PendingPredictions: []jamiethompsonmev1alpha1.TimestampedReplicas{
+					{
+						Time:     &metav1.Time{Time: now.Add(-20 * time.Second)},
+						Replicas: 2,
+					},
+					{
+						Time:     &metav1.Time{Time: now.Add(-2 * time.Second)},
+						Replicas: 6,
+					},
+					{
+						Time:     &metav1.Time{Time: now.Add(3 * time.Second)},
+						Replicas: 7,
+					},
+					{
+						Time:     &metav1.Time{Time: now.Add(15 * time.Second)},
+						Replicas: 8,
+					},
+				},
+				ScoredPredictions: []jamiethompsonmev1alpha1.ScoredPrediction{
+					{
+						Time:      &metav1.Time{Time: now.Add(-30 * time.Second)},
+						Predicted: 3,
+						Actual:    4,
+					},
+				},
+			},
+			actual:     5,
+			now:        now,
+			syncPeriod: 10 * time.Second,
+			window:     10,
+		},
+		{
+			description: "Score prediction due between sync periods at the next sync period",
+			expected: jamiethompsonmev1alpha1.ModelHistory{
+				ScoredPredictions: []jamiethompsonmev1alpha1.ScoredPrediction{
+					{
+						Time:      &metav1.Time{Time: now.Add(-20 * time.Second)},
+						Predicted: 4,
+						Actual:    5,
+					},
+				},
+			},
+			modelHistory: jamiethompsonmev1alpha1.ModelHistory{
+				PendingPredictions: []jamiethompsonmev1alpha1.TimestampedReplicas{
+					{
+						Time:     &metav1.Time{Time: now.Add(-20 * time.Second)},
+						Replicas: 4,
+					},
+				},
+			},
+			actual:     5,
+			now:        now,
+			syncPeriod: 30 * time.Second,
+			window:     10,
+		},
+		{
+			description: "Score prediction, remove oldest scored predictions outside of window",
+			expected: jamiethompsonmev1alpha1.ModelHistory{
+				ScoredPredictions: []jamiethompsonmev1alpha1.ScoredPrediction{
+					{
+						Time:      &metav1.Time{Time: now.Add(-15 * time.Second)},
+						Predicted: 4,
+						Actual:    4,
+					},
+					{
+						Time:      &metav1.Time{Time: now},
+						Predicted: 6,
+						Actual:    5,
+					},
+				},
+			},
+			modelHistory: jamiethompsonmev1alpha1.ModelHistory{
+				PendingPredictions: []jamiethompsonmev1alpha1.TimestampedReplicas{
+					{
+						Time:     &metav1.Time{Time: now},
+						Replicas: 6,
+					},
+				},
+				ScoredPredictions: []jamiethompsonmev1alpha1.ScoredPrediction{
+					{
+						Time:      &metav1.Time{Time: now.Add(-30 * time.Second)},
+						Predicted: 3,
+						Actual:    4,
+					},
+					{
+						Time:      &metav1.Time{Time: now.Add(-15 * time.Second)},
+						Predicted: 4,
+						Actual:    4,
+					},
+				},
+			},
+			actual:     5,
+			now:        now,
+			syncPeriod: 10 * time.Second,
+			window:     2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			modelHistory := test.modelHistory
+			accuracy.Score(&modelHistory, test.actual, test.now, test.syncPeriod, test.window)
+			if !cmp.Equal(test.expected, modelHistory) {
+				t.Errorf("model history mismatch (-want +got):\n%s", cmp.Diff(test.expected, modelHistory))
+			}
+		})
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	var tests = []struct {
+		description       string
+		expected          *jamiethompsonmev1alpha1.ModelAccuracy
+		scoredPredictions []jamiethompsonmev1alpha1.ScoredPrediction
+	}{
+		{
+			description:       "No scored predictions",
+			expected:          nil,
+			scoredPredictions: []jamiethompsonmev1alpha1.ScoredPrediction{},
+		},
+		{
+			description: "Only predictions with actual replicas of 0, no percentage error",
+			expected: &jamiethompsonmev1alpha1.ModelAccuracy{
+				Samples:           2,
+				MeanAbsoluteError: 1.5,
+				Bias:              1.5,
+			},
+			scoredPredictions: []jamiethompsonmev1alpha1.ScoredPrediction{
+				{
+					Predicted: 1,
+					Actual:    0,
+				},
+				{
+					Predicted: 2,
+					Actual:    0,
+				},
+			},
+		},
+		{
+			description: "Over and under predictions",
+			expected: &jamiethompsonmev1alpha1.ModelAccuracy{
+				Samples:                     4,
+				MeanAbsoluteError:           2.5,
+				MeanAbsolutePercentageError: float64Ptr(50),
+				Bias:                        -1,
+			},
+			scoredPredictions: []jamiethompsonmev1alpha1.ScoredPrediction{
+				{
+					Predicted: 6,
+					Actual:    4,
+				},
+				{
+					Predicted: 2,
+					Actual:    4,
+				},
+				{
+					Predicted: 5,
+					Actual:    10,
+				},
+				{
+					Predicted: 1,
+					Actual:    0,
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result := accuracy.Calculate(test.scoredPredictions)
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("accuracy mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestExclude(t *testing.T) {
+	var tests = []struct {
+		description string
+		expected    bool
+		fallback    *jamiethompsonmev1alpha1.ModelFallback
+		accuracy    *jamiethompsonmev1alpha1.ModelAccuracy
+		excluded    bool
+	}{
+		{
+			description: "No fallback policy, include",
+			expected:    false,
+			fallback:    nil,
+			accuracy: &jamiethompsonmev1alpha1.ModelAccuracy{
+				Samples:                     20,
+				MeanAbsolutePercentageError: float64Ptr(500),
+			},
+			excluded: true,
+		},
+		{
+			description: "No accuracy, stay excluded",
+			expected:    true,
+			fallback: &jamiethompsonmev1alpha1.ModelFallback{
+				Threshold: 20,
+			},
+			accuracy: nil,
+			excluded: true,
+		},
+		{
+			description: "Not enough samples, stay included",
+			expected:    false,
+			fallback: &jamiethompsonmev1alpha1.ModelFallback{
+				Threshold: 20,
+			},
+			accuracy: &jamiethompsonmev1alpha1.ModelAccuracy{
+				Samples:                     4,
+				MeanAbsolutePercentageError: float64Ptr(50),
+			},
+			excluded: false,
+		},
+		{
+			description: "No percentage error, stay included",
+			expected:    false,
+			fallback: &jamiethompsonmev1alpha1.ModelFallback{
+				Threshold: 20,
+			},
+			accuracy: &jamiethompsonmev1alpha1.ModelAccuracy{
+				Samples:           10,
+				MeanAbsoluteError: 50,
+			},
+			excluded: false,
+		},
+		{
+			description: "Percentage error above threshold, exclude",
+			expected:    true,
+			fallback: &jamiethompsonmev1alpha1.ModelFallback{
+				Threshold: 20,
+			},
+			accuracy: &jamiethompsonmev1alpha1.ModelAccuracy{
+				Samples:                     5,
+				MeanAbsolutePercentageError: float64Ptr(25),
+			},
+			excluded: false,
+		},
+		{
+			description: "Percentage error at threshold, stay included",
+			expected:    false,
+			fallback: &jamiethompsonmev1alpha1.ModelFallback{
+				Threshold: 20,
+			},
+			accuracy: &jamiethompsonmev1alpha1.ModelAccuracy{
+				Samples:                     5,
+				MeanAbsolutePercentageError: float64Ptr(20),
+			},
+			excluded: false,
+		},
+		{
+			description: "Absolute error above threshold, custom minimum samples, exclude",
+			expected:    true,
+			fallback: &jamiethompsonmev1alpha1.ModelFallback{
+				AccuracyMetric: stringPtr(jamiethompsonmev1alpha1.AccuracyMeanAbsoluteError),
+				Threshold:      2,
+				MinimumSamples: int32Ptr(2),
+			},
+			accuracy: &jamiethompsonmev1alpha1.ModelAccuracy{
+				Samples:                     2,
+				MeanAbsoluteError:           2.5,
+				MeanAbsolutePercentageError: float64Ptr(1),
+			},
+			excluded: false,
+		},
+		{
+			description: "Excluded, error below threshold but above recovery threshold, stay excluded",
+			expected:    true,
+			fallback: &jamiethompsonmev1alpha1.ModelFallback{
+				Threshold:         20,
+				RecoveryThreshold: float64Ptr(10),
+			},
+			accuracy: &jamiethompsonmev1alpha1.ModelAccuracy{
+				Samples:                     20,
+				MeanAbsolutePercentageError: float64Ptr(15),
+			},
+			excluded: true,
+		},
+		{
+			description: "Excluded, error at recovery threshold, include",
+			expected:    false,
+			fallback: &jamiethompsonmev1alpha1.ModelFallback{
+				Threshold:         20,
+				RecoveryThreshold: float64Ptr(10),
+			},
+			accuracy: &jamiethompsonmev1alpha1.ModelAccuracy{
+				Samples:                     20,
+				MeanAbsolutePercentageError: float64Ptr(10),
+			},
+			excluded: true,
+		},
+		{
+			description: "Excluded, no recovery threshold, error at threshold, include",
+			expected:    false,
+			fallback: &jamiethompsonmev1alpha1.ModelFallback{
+				Threshold: 20,
+			},
+			accuracy: &jamiethompsonmev1alpha1.ModelAccuracy{
+				Samples:                     20,
+				MeanAbsolutePercentageError: float64Ptr(20),
+			},
+			excluded: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result := accuracy.Exclude(test.fallback, test.accuracy, test.excluded)
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("exclude mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package algorithm
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -29,8 +30,16 @@ const (
 	entrypoint = "python"
 )
 
+// ErrTimeout is wrapped by the error returned when an algorithm does not finish running within its timeout
+var ErrTimeout = errors.New("timed out")
+
 type command = func(name string, arg ...string) *exec.Cmd
 
+// Runner is an algorithm runner, running the algorithm at the path provided with a value and returning the output
+type Runner interface {
+	RunAlgorithmWithValue(algorithmPath string, value string, timeout int) (string, error)
+}
+
 func NewAlgorithmPython() *Python {
 	return &Python{
 		Command: exec.Command,
@@ -46,14 +55,59 @@ type Python struct {
 
 // RunAlgorithmWithValue runs an algorithm at the path provided, passing through the value provided
 func (r *Python) RunAlgorithmWithValue(algorithmPath string, value string, timeout int) (string, error) {
+	resolvedPath, err := resolvePath(r.Getwd, algorithmPath)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := r.Command(entrypoint, resolvedPath)
+
+	return run(cmd, value, timeout, fmt.Sprintf("entrypoint '%s', command '%s'", entrypoint, algorithmPath))
+}
+
+func NewAlgorithmExecutable() *Executable {
+	return &Executable{
+		Command: exec.Command,
+		Getwd:   os.Getwd,
+	}
+}
 
-	wd, err := r.Getwd()
+// Executable is an implementation of an algorithm runner that runs algorithms by executing them directly, allowing
+// algorithms to be written in any language
+type Executable struct {
+	Command command
+	Getwd   func() (dir string, err error)
+}
+
+// RunAlgorithmWithValue runs the executable algorithm at the path provided, passing through the value provided
+func (r *Executable) RunAlgorithmWithValue(algorithmPath string, value string, timeout int) (string, error) {
+	resolvedPath, err := resolvePath(r.Getwd, algorithmPath)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := r.Command(resolvedPath)
+
+	return run(cmd, value, timeout, fmt.Sprintf("command '%s'", algorithmPath))
+}
+
+// resolvePath resolves the algorithm path provided, relative paths are relative to the working directory
+func resolvePath(getwd func() (dir string, err error), algorithmPath string) (string, error) {
+	if path.IsAbs(algorithmPath) {
+		return algorithmPath, nil
+	}
+
+	wd, err := getwd()
 	if err != nil {
 		return "", err
 	}
 
-	cmd := r.Command(entrypoint, path.Join(wd, algorithmPath))
+	return path.Join(wd, algorithmPath), nil
+}
 
+// run runs the command provided, piping the value provided in through stdin and returning the output from stdout.
+// The description is used to describe the command if it times out
+func run(cmd *exec.Cmd, value string, timeout int, description string) (string, error) {
 	// Set up byte buffer to write values to stdin
 	inb := bytes.Buffer{}
 	// No need to catch error, doesn't produce error, instead it panics if buffer too large
@@ -66,7 +120,7 @@ func (r *Python) RunAlgorithmWithValue(algorithmPath string, value string, timeo
 	cmd.Stderr = &errb
 
 	// Start command
-	err = cmd.Start()
+	err := cmd.Start()
 	if err != nil {
 		return "", err
 	}
@@ -81,7 +135,7 @@ func (r *Python) RunAlgorithmWithValue(algorithmPath string, value string, timeo
 	select {
 	case <-timeoutListener:
 		cmd.Process.Kill()
-		return "", fmt.Errorf("entrypoint '%s', command '%s' timed out", entrypoint, algorithmPath)
+		return "", fmt.Errorf("%s %w", description, ErrTimeout)
 	case err = <-done:
 		if err != nil {
 			return "", fmt.Errorf("%v: %s", err, errb.String())
@@ -111,7 +111,12 @@ var processes map[string]process
 
 func TestMain(m *testing.M) {
 	wd, _ := os.Getwd()
-	processes = map[string]process{}
+	processes = map[string]process{
+		// Processes that are used by tests outside of TestMain must be registered here so they can be found by the
+		// process started for the test
+		"worker":     fakeWorker,
+		"executable": fakeExecutable,
+	}
 	tests = []test{
 		{
 			description:   "Successful python command",
@@ -158,6 +163,31 @@ func TestMain(m *testing.M) {
 				Getwd: os.Getwd,
 			},
 		},
+		{
+			description:   "Successful python command, absolute path",
+			expectedErr:   nil,
+			expected:      "test std out",
+			algorithmPath: "/algorithms/test-algorithm.py",
+			pipeValue:     "pipe value",
+			timeout:       100,
+			python: &algorithm.Python{
+				Command: fakeExecCommand("absolute path", func(t *testing.T) {
+					algorithmPath := strings.TrimSpace(strings.Join(os.Args[5:len(os.Args)], " "))
+
+					// Check command is correct, absolute paths should not be joined with the working directory
+					if !cmp.Equal(algorithmPath, "/algorithms/test-algorithm.py") {
+						fmt.Fprintf(os.Stderr, "algorithmPath mismatch (-want +got):\n%s", cmp.Diff("/algorithms/test-algorithm.py", algorithmPath))
+						os.Exit(1)
+					}
+
+					fmt.Fprint(os.Stdout, "test std out")
+					os.Exit(0)
+				}),
+				Getwd: func() (dir string, err error) {
+					return "", errors.New("working directory should not be used for absolute paths")
+				},
+			},
+		},
 		{
 			description:   "Failed python command",
 			expectedErr:   errors.New("exit status 1: shell command failed"),
@@ -249,3 +279,120 @@ func TestPython_RunAlgorithmWithValue(t *testing.T) {
 		})
 	}
 }
+
+// fakeExecutable acts as an executable algorithm, checking it was started with the expected path and stdin
+func fakeExecutable(t *testing.T) {
+	wd, _ := os.Getwd()
+
+	stdinb, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprint(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	stdin := string(stdinb)
+	algorithmPath := strings.TrimSpace(strings.Join(os.Args[4:], " "))
+
+	expectedAlgorithmPath := path.Join(wd, "test-algorithm")
+
+	// Check command is correct
+	if !cmp.Equal(algorithmPath, expectedAlgorithmPath) {
+		fmt.Fprintf(os.Stderr, "algorithmPath mismatch (-want +got):\n%s", cmp.Diff(expectedAlgorithmPath, algorithmPath))
+		os.Exit(1)
+	}
+
+	switch stdin {
+	case "fail":
+		fmt.Fprint(os.Stderr, "executable failed")
+		os.Exit(1)
+	case "sleep":
+		time.Sleep(10 * time.Second)
+	}
+
+	fmt.Fprintf(os.Stdout, "test std out: %s", stdin)
+	os.Exit(0)
+}
+
+func TestExecutable_RunAlgorithmWithValue(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description   string
+		expectedErr   error
+		expected      string
+		algorithmPath string
+		pipeValue     string
+		timeout       int
+		executable    *algorithm.Executable
+	}{
+		{
+			description:   "Successful executable",
+			expectedErr:   nil,
+			expected:      "test std out: pipe value",
+			algorithmPath: "test-algorithm",
+			pipeValue:     "pipe value",
+			timeout:       1000,
+			executable: &algorithm.Executable{
+				Command: fakeExecCommand("executable", fakeExecutable),
+				Getwd:   os.Getwd,
+			},
+		},
+		{
+			description:   "Failed executable",
+			expectedErr:   errors.New("exit status 1: executable failed"),
+			expected:      "",
+			algorithmPath: "test-algorithm",
+			pipeValue:     "fail",
+			timeout:       1000,
+			executable: &algorithm.Executable{
+				Command: fakeExecCommand("executable", fakeExecutable),
+				Getwd:   os.Getwd,
+			},
+		},
+		{
+			description:   "Failed executable timeout",
+			expectedErr:   errors.New("command 'test-algorithm' timed out"),
+			expected:      "",
+			algorithmPath: "test-algorithm",
+			pipeValue:     "sleep",
+			timeout:       50,
+			executable: &algorithm.Executable{
+				Command: fakeExecCommand("executable", fakeExecutable),
+				Getwd:   os.Getwd,
+			},
+		},
+		{
+			description:   "Fail to get working directory",
+			expectedErr:   errors.New("fail to get working directory"),
+			expected:      "",
+			algorithmPath: "test-algorithm",
+			pipeValue:     "pipe value",
+			timeout:       1000,
+			executable: &algorithm.Executable{
+				Command: fakeExecCommand("executable", fakeExecutable),
+				Getwd: func() (dir string, err error) {
+					return "", errors.New("fail to get working directory")
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.executable.RunAlgorithmWithValue(test.algorithmPath, test.pipeValue, test.timeout)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+
+			if !cmp.Equal(result, test.expected) {
+				t.Errorf("stdout mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
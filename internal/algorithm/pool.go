@@ -0,0 +1,327 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algorithm
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWorkerPath is the path to the Python worker script that the pool starts, relative to the working
+	// directory
+	DefaultWorkerPath = "algorithms/worker/worker.py"
+)
+
+// maxFrameSize is the largest frame that will be accepted from a worker, protecting against a corrupted stream
+// causing a huge allocation
+const maxFrameSize = 64 * 1024 * 1024
+
+func NewAlgorithmPool(size int) *Pool {
+	return &Pool{
+		Command:    exec.Command,
+		Getwd:      os.Getwd,
+		WorkerPath: DefaultWorkerPath,
+		Size:       size,
+	}
+}
+
+// Pool is an implementation of an algorithm runner that keeps a pool of long-lived Python worker processes, avoiding
+// the cost of starting Python and importing the algorithm dependencies for every algorithm run. Requests are sent to
+// the workers over stdin and responses read from stdout using length prefixed JSON frames. If every worker is busy
+// the caller will wait for one to become available, up to the timeout of the request. A worker that times out or
+// crashes is stopped, and a new worker process is started the next time it is needed.
+type Pool struct {
+	Command    command
+	Getwd      func() (dir string, err error)
+	WorkerPath string
+	Size       int
+
+	init    sync.Once
+	mu      sync.Mutex
+	closed  bool
+	workers chan *worker
+}
+
+// workerRequest is the frame sent to a worker asking it to run an algorithm
+type workerRequest struct {
+	AlgorithmPath string `json:"algorithmPath"`
+	Value         string `json:"value"`
+}
+
+// workerResponse is the frame a worker sends back after running an algorithm, the exit code is the code the algorithm
+// would have exited with if it had been run as a separate process
+type workerResponse struct {
+	ExitCode int    `json:"exitCode"`
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+}
+
+// worker is a single slot in the pool, holding a running worker process or nil if a process needs to be started
+type worker struct {
+	process *workerProcess
+}
+
+// workerProcess is a running worker process and the pipes used to communicate with it
+type workerProcess struct {
+	cmd    *exec.Cmd
+	stdin  *os.File
+	stdout *os.File
+	done   chan struct{}
+}
+
+// RunAlgorithmWithValue runs an algorithm at the path provided using a worker from the pool, passing through the value
+// provided
+func (p *Pool) RunAlgorithmWithValue(algorithmPath string, value string, timeout int) (string, error) {
+	p.setup()
+
+	resolvedPath, err := resolvePath(p.Getwd, algorithmPath)
+	if err != nil {
+		return "", err
+	}
+
+	// Set up a timeout, covering both waiting for an available worker and running the algorithm
+	timeoutListener := time.After(time.Duration(timeout) * time.Millisecond)
+
+	var w *worker
+	select {
+	case w = <-p.workers:
+	case <-timeoutListener:
+		return "", fmt.Errorf("entrypoint '%s', command '%s' %w waiting for an available worker", entrypoint,
+			algorithmPath, ErrTimeout)
+	}
+	defer p.release(w)
+
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return "", errors.New("algorithm worker pool is closed")
+	}
+
+	if w.process == nil {
+		w.process, err = p.start()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	request, err := json.Marshal(workerRequest{
+		AlgorithmPath: resolvedPath,
+		Value:         value,
+	})
+	if err != nil {
+		// Should not occur, marshalling two strings
+		panic(err)
+	}
+
+	// Set up channel to wait for the worker to respond
+	type result struct {
+		response *workerResponse
+		err      error
+	}
+	process := w.process
+	done := make(chan result, 1)
+	go func() {
+		response, err := process.request(request)
+		done <- result{response: response, err: err}
+	}()
+
+	select {
+	case <-timeoutListener:
+		w.stop()
+		return "", fmt.Errorf("entrypoint '%s', command '%s' %w", entrypoint, algorithmPath, ErrTimeout)
+	case res := <-done:
+		if res.err != nil {
+			w.stop()
+			return "", fmt.Errorf("worker failed running command '%s': %w", algorithmPath, res.err)
+		}
+		if res.response.ExitCode != 0 {
+			return "", fmt.Errorf("exit status %d: %s", res.response.ExitCode, res.response.Stderr)
+		}
+		return res.response.Stdout, nil
+	}
+}
+
+// Close stops all of the worker processes in the pool, waiting for any in progress algorithm runs to finish
+func (p *Pool) Close() {
+	p.setup()
+
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	// Take every worker out of the pool before releasing any, so that a released worker cannot be taken again in
+	// place of a worker that is still running an algorithm
+	workers := make([]*worker, p.Size)
+	for i := range workers {
+		workers[i] = <-p.workers
+		workers[i].stop()
+	}
+
+	// Release the stopped workers so any callers waiting for a worker find the pool is closed rather than timing out
+	for _, w := range workers {
+		p.release(w)
+	}
+}
+
+// setup fills the pool with empty worker slots the first time it is used
+func (p *Pool) setup() {
+	p.init.Do(func() {
+		p.workers = make(chan *worker, p.Size)
+		for i := 0; i < p.Size; i++ {
+			p.workers <- &worker{}
+		}
+	})
+}
+
+// release returns a worker to the pool
+func (p *Pool) release(w *worker) {
+	p.workers <- w
+}
+
+// start starts a new worker process
+func (p *Pool) start() (*workerProcess, error) {
+	workerPath, err := resolvePath(p.Getwd, p.WorkerPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := p.Command(entrypoint, workerPath)
+
+	// Set up the pipes manually rather than using cmd.StdinPipe and cmd.StdoutPipe, these are closed when the process
+	// exits which would race with reading a response, whereas these will give an EOF once the process has exited
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		stdinReader.Close()
+		stdinWriter.Close()
+		return nil, err
+	}
+
+	cmd.Stdin = stdinReader
+	cmd.Stdout = stdoutWriter
+
+	// Algorithm output to stderr is returned in the response, anything else written to stderr by the worker process
+	// itself is passed through to help explain why a worker crashed
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Start()
+
+	// The child process has its own copies of these, so close them in this process
+	stdinReader.Close()
+	stdoutWriter.Close()
+
+	if err != nil {
+		stdinWriter.Close()
+		stdoutReader.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+
+	return &workerProcess{
+		cmd:    cmd,
+		stdin:  stdinWriter,
+		stdout: stdoutReader,
+		done:   done,
+	}, nil
+}
+
+// stop kills the worker process if it is running and clears the slot so a new process will be started the next time
+// it is used
+func (w *worker) stop() {
+	if w.process == nil {
+		return
+	}
+	process := w.process
+	w.process = nil
+
+	process.cmd.Process.Kill()
+	<-process.done
+	process.stdin.Close()
+	process.stdout.Close()
+}
+
+// request sends a single request frame to the worker process and reads the response frame
+func (p *workerProcess) request(request []byte) (*workerResponse, error) {
+	err := writeFrame(p.stdin, request)
+	if err != nil {
+		return nil, err
+	}
+
+	frame, err := readFrame(p.stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &workerResponse{}
+	err = json.Unmarshal(frame, response)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response from worker: %w", err)
+	}
+
+	return response, nil
+}
+
+// writeFrame writes a frame made up of a 4 byte big endian length followed by the payload
+func writeFrame(writer io.Writer, payload []byte) error {
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint32(header, uint32(len(payload)))
+	_, err := writer.Write(append(header, payload...))
+	return err
+}
+
+// readFrame reads a frame made up of a 4 byte big endian length followed by the payload
+func readFrame(reader io.Reader) ([]byte, error) {
+	header := make([]byte, 4)
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("worker process exited unexpectedly")
+		}
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header)
+	if size > maxFrameSize {
+		return nil, fmt.Errorf("worker response of %d bytes exceeds maximum frame size of %d bytes", size, maxFrameSize)
+	}
+
+	payload := make([]byte, size)
+	_, err = io.ReadFull(reader, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
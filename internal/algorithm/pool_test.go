@@ -0,0 +1,330 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algorithm_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/algorithm"
+)
+
+type workerRequest struct {
+	AlgorithmPath string `json:"algorithmPath"`
+	Value         string `json:"value"`
+}
+
+type workerResponse struct {
+	ExitCode int    `json:"exitCode"`
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+}
+
+// fakeWorker acts as a worker process, responding to each request with the value provided and the number of requests
+// this process has handled, allowing tests to tell if a worker process has been reused or restarted
+func fakeWorker(t *testing.T) {
+	wd, _ := os.Getwd()
+
+	entrypoint := strings.TrimSpace(os.Args[4])
+	workerPath := strings.TrimSpace(strings.Join(os.Args[5:], " "))
+
+	respond := func(response workerResponse) {
+		payload, _ := json.Marshal(response)
+		header := make([]byte, 4)
+		binary.BigEndian.PutUint32(header, uint32(len(payload)))
+		os.Stdout.Write(append(header, payload...))
+	}
+
+	handled := 0
+	for {
+		header := make([]byte, 4)
+		_, err := io.ReadFull(os.Stdin, header)
+		if err != nil {
+			os.Exit(0)
+		}
+
+		payload := make([]byte, binary.BigEndian.Uint32(header))
+		_, err = io.ReadFull(os.Stdin, payload)
+		if err != nil {
+			os.Exit(1)
+		}
+
+		request := workerRequest{}
+		err = json.Unmarshal(payload, &request)
+		if err != nil {
+			respond(workerResponse{ExitCode: 1, Stderr: err.Error()})
+			continue
+		}
+
+		handled++
+
+		expectedWorkerPath := path.Join(wd, algorithm.DefaultWorkerPath)
+		expectedAlgorithmPath := path.Join(wd, "test-algorithm.py")
+
+		// Check entrypoint is correct
+		if !cmp.Equal(entrypoint, "python") {
+			respond(workerResponse{ExitCode: 1, Stderr: fmt.Sprintf("entrypoint mismatch (-want +got):\n%s",
+				cmp.Diff("python", entrypoint))})
+			continue
+		}
+
+		// Check worker path is correct
+		if !cmp.Equal(workerPath, expectedWorkerPath) {
+			respond(workerResponse{ExitCode: 1, Stderr: fmt.Sprintf("workerPath mismatch (-want +got):\n%s",
+				cmp.Diff(expectedWorkerPath, workerPath))})
+			continue
+		}
+
+		// Check algorithm path is correct
+		if !cmp.Equal(request.AlgorithmPath, expectedAlgorithmPath) {
+			respond(workerResponse{ExitCode: 1, Stderr: fmt.Sprintf("algorithmPath mismatch (-want +got):\n%s",
+				cmp.Diff(expectedAlgorithmPath, request.AlgorithmPath))})
+			continue
+		}
+
+		switch request.Value {
+		case "crash":
+			os.Exit(1)
+		case "sleep":
+			time.Sleep(10 * time.Second)
+		case "fail":
+			respond(workerResponse{ExitCode: 1, Stderr: "algorithm failed"})
+		default:
+			respond(workerResponse{Stdout: fmt.Sprintf("%s %d", request.Value, handled)})
+		}
+	}
+}
+
+type poolRun struct {
+	value       string
+	timeout     int
+	expected    string
+	expectedErr error
+}
+
+func TestPool_RunAlgorithmWithValue(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description string
+		runs        []poolRun
+		pool        *algorithm.Pool
+	}{
+		{
+			description: "Successful runs, worker reused",
+			runs: []poolRun{
+				{value: "first", timeout: 5000, expected: "first 1"},
+				{value: "second", timeout: 5000, expected: "second 2"},
+				{value: "third", timeout: 5000, expected: "third 3"},
+			},
+			pool: &algorithm.Pool{
+				Command:    fakeExecCommand("worker", fakeWorker),
+				Getwd:      os.Getwd,
+				WorkerPath: algorithm.DefaultWorkerPath,
+				Size:       1,
+			},
+		},
+		{
+			description: "Algorithm fails, worker reused",
+			runs: []poolRun{
+				{value: "fail", timeout: 5000, expectedErr: errors.New("exit status 1: algorithm failed")},
+				{value: "second", timeout: 5000, expected: "second 2"},
+			},
+			pool: &algorithm.Pool{
+				Command:    fakeExecCommand("worker", fakeWorker),
+				Getwd:      os.Getwd,
+				WorkerPath: algorithm.DefaultWorkerPath,
+				Size:       1,
+			},
+		},
+		{
+			description: "Algorithm times out, worker restarted",
+			runs: []poolRun{
+				{value: "first", timeout: 5000, expected: "first 1"},
+				{value: "sleep", timeout: 100, expectedErr: errors.New("entrypoint 'python', command 'test-algorithm.py' timed out")},
+				{value: "third", timeout: 5000, expected: "third 1"},
+			},
+			pool: &algorithm.Pool{
+				Command:    fakeExecCommand("worker", fakeWorker),
+				Getwd:      os.Getwd,
+				WorkerPath: algorithm.DefaultWorkerPath,
+				Size:       1,
+			},
+		},
+		{
+			description: "Worker crashes, worker restarted",
+			runs: []poolRun{
+				{value: "first", timeout: 5000, expected: "first 1"},
+				{value: "crash", timeout: 5000, expectedErr: errors.New("worker failed running command 'test-algorithm.py': worker process exited unexpectedly")},
+				{value: "third", timeout: 5000, expected: "third 1"},
+			},
+			pool: &algorithm.Pool{
+				Command:    fakeExecCommand("worker", fakeWorker),
+				Getwd:      os.Getwd,
+				WorkerPath: algorithm.DefaultWorkerPath,
+				Size:       1,
+			},
+		},
+		{
+			description: "Fail to start worker",
+			runs: []poolRun{
+				{value: "first", timeout: 5000, expectedErr: errors.New("exec: already started")},
+			},
+			pool: &algorithm.Pool{
+				Command:    fakeExecCommandAndStart("worker", fakeWorker),
+				Getwd:      os.Getwd,
+				WorkerPath: algorithm.DefaultWorkerPath,
+				Size:       1,
+			},
+		},
+		{
+			description: "Fail to get working directory",
+			runs: []poolRun{
+				{value: "first", timeout: 5000, expectedErr: errors.New("fail to get working directory")},
+			},
+			pool: &algorithm.Pool{
+				Command: fakeExecCommand("worker", fakeWorker),
+				Getwd: func() (dir string, err error) {
+					return "", errors.New("fail to get working directory")
+				},
+				WorkerPath: algorithm.DefaultWorkerPath,
+				Size:       1,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer test.pool.Close()
+			for i, run := range test.runs {
+				result, err := test.pool.RunAlgorithmWithValue("test-algorithm.py", run.value, run.timeout)
+				if !cmp.Equal(&err, &run.expectedErr, equateErrorMessage) {
+					t.Errorf("run %d error mismatch (-want +got):\n%s", i, cmp.Diff(run.expectedErr, err, equateErrorMessage))
+					return
+				}
+
+				if !cmp.Equal(result, run.expected) {
+					t.Errorf("run %d stdout mismatch (-want +got):\n%s", i, cmp.Diff(run.expected, result))
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestPool_RunAlgorithmWithValue_AllWorkersBusy(t *testing.T) {
+	pool := &algorithm.Pool{
+		Command:    fakeExecCommand("worker", fakeWorker),
+		Getwd:      os.Getwd,
+		WorkerPath: algorithm.DefaultWorkerPath,
+		Size:       1,
+	}
+	defer pool.Close()
+
+	busy := make(chan struct{})
+	go func() {
+		pool.RunAlgorithmWithValue("test-algorithm.py", "sleep", 500)
+		close(busy)
+	}()
+
+	// Give the first run time to take the only worker
+	time.Sleep(50 * time.Millisecond)
+
+	expectedErr := "entrypoint 'python', command 'test-algorithm.py' timed out waiting for an available worker"
+	_, err := pool.RunAlgorithmWithValue("test-algorithm.py", "second", 50)
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(expectedErr, fmt.Sprint(err)))
+	}
+	if !errors.Is(err, algorithm.ErrTimeout) {
+		t.Errorf("expected error to wrap algorithm.ErrTimeout, got %v", err)
+	}
+
+	<-busy
+
+	// Once the worker has been freed up it should be available again
+	result, err := pool.RunAlgorithmWithValue("test-algorithm.py", "third", 5000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !cmp.Equal(result, "third 1") {
+		t.Errorf("stdout mismatch (-want +got):\n%s", cmp.Diff("third 1", result))
+	}
+}
+
+func TestPool_Close(t *testing.T) {
+	pool := &algorithm.Pool{
+		Command:    fakeExecCommand("worker", fakeWorker),
+		Getwd:      os.Getwd,
+		WorkerPath: algorithm.DefaultWorkerPath,
+		Size:       2,
+	}
+
+	_, err := pool.RunAlgorithmWithValue("test-algorithm.py", "first", 5000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pool.Close()
+
+	expectedErr := "algorithm worker pool is closed"
+	_, err = pool.RunAlgorithmWithValue("test-algorithm.py", "second", 5000)
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(expectedErr, fmt.Sprint(err)))
+	}
+}
+
+func TestPool_Close_WaitsForRunningAlgorithms(t *testing.T) {
+	pool := &algorithm.Pool{
+		Command:    fakeExecCommand("worker", fakeWorker),
+		Getwd:      os.Getwd,
+		WorkerPath: algorithm.DefaultWorkerPath,
+		Size:       2,
+	}
+
+	running := make(chan struct{})
+	go func() {
+		pool.RunAlgorithmWithValue("test-algorithm.py", "sleep", 500)
+		close(running)
+	}()
+
+	// Give the run time to take a worker
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	pool.Close()
+
+	// The run only releases its worker once it has timed out, so close should not return until then
+	if time.Since(start) < 400*time.Millisecond {
+		t.Errorf("expected close to wait for the running algorithm to finish, returned after %s", time.Since(start))
+	}
+	<-running
+}
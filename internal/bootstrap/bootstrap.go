@@ -0,0 +1,58 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bootstrap provides a standardised way to fill an empty model replica history from an external source of
+// historical data
+package bootstrap
+
+import (
+	"fmt"
+	"time"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+)
+
+// Bootstrapper interface provides methods for getting a replica history from an external source, the replica history
+// returned should end before the current time and be in chronological order. The sync period is provided for
+// bootstrappers to use as the default time between replica counts
+type Bootstrapper interface {
+	GetReplicaHistory(definition *jamiethompsonmev1alpha1.HistoryBootstrap, now time.Time, syncPeriod time.Duration) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error)
+	GetType() string
+}
+
+// CombinedType is the type of the CombinedBootstrap, it is not a valid bootstrap type itself
+const CombinedType = "combined"
+
+// CombinedBootstrap is used to route a history bootstrap to the appropriate bootstrapper based on the bootstrap type
+// Should be initialised with available bootstrappers for it to use
+type CombinedBootstrap struct {
+	Bootstrappers []Bootstrapper
+}
+
+// GetReplicaHistory gets the replica history with any bootstrapper that the CombinedBootstrap has been set up to use
+func (b *CombinedBootstrap) GetReplicaHistory(definition *jamiethompsonmev1alpha1.HistoryBootstrap, now time.Time, syncPeriod time.Duration) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+	for _, bootstrapper := range b.Bootstrappers {
+		if bootstrapper.GetType() == definition.Type {
+			return bootstrapper.GetReplicaHistory(definition, now, syncPeriod)
+		}
+	}
+	return nil, fmt.Errorf("unknown history bootstrap type '%s'", definition.Type)
+}
+
+// GetType returns the type of the CombinedBootstrap, "combined"
+func (b *CombinedBootstrap) GetType() string {
+	return CombinedType
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/bootstrap"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCombinedBootstrap_GetReplicaHistory(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		description  string
+		expected     []jamiethompsonmev1alpha1.TimestampedReplicas
+		expectedErr  error
+		definition   *jamiethompsonmev1alpha1.HistoryBootstrap
+		bootstrapper *bootstrap.CombinedBootstrap
+	}{
+		{
+			description: "Fail, no bootstrappers",
+			expected:    nil,
+			expectedErr: errors.New("unknown history bootstrap type 'Prometheus'"),
+			definition: &jamiethompsonmev1alpha1.HistoryBootstrap{
+				Type: "Prometheus",
+			},
+			bootstrapper: &bootstrap.CombinedBootstrap{
+				Bootstrappers: []bootstrap.Bootstrapper{},
+			},
+		},
+		{
+			description: "Fail, unknown bootstrap type",
+			expected:    nil,
+			expectedErr: errors.New("unknown history bootstrap type 'unknown'"),
+			definition: &jamiethompsonmev1alpha1.HistoryBootstrap{
+				Type: "unknown",
+			},
+			bootstrapper: &bootstrap.CombinedBootstrap{
+				Bootstrappers: []bootstrap.Bootstrapper{
+					&fake.Bootstrap{
+						GetTypeReactor: func() string {
+							return "Prometheus"
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "Fail, bootstrapper fails",
+			expected:    nil,
+			expectedErr: errors.New("bootstrap error"),
+			definition: &jamiethompsonmev1alpha1.HistoryBootstrap{
+				Type: "Prometheus",
+			},
+			bootstrapper: &bootstrap.CombinedBootstrap{
+				Bootstrappers: []bootstrap.Bootstrapper{
+					&fake.Bootstrap{
+						GetTypeReactor: func() string {
+							return "Prometheus"
+						},
+						GetReplicaHistoryReactor: func(definition *jamiethompsonmev1alpha1.HistoryBootstrap, now time.Time, syncPeriod time.Duration) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+							return nil, errors.New("bootstrap error")
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "Success, routed to the matching bootstrapper",
+			expected: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Time:     &metav1.Time{Time: now.Add(-time.Minute)},
+					Replicas: 3,
+				},
+			},
+			expectedErr: nil,
+			definition: &jamiethompsonmev1alpha1.HistoryBootstrap{
+				Type: "Prometheus",
+			},
+			bootstrapper: &bootstrap.CombinedBootstrap{
+				Bootstrappers: []bootstrap.Bootstrapper{
+					&fake.Bootstrap{
+						GetTypeReactor: func() string {
+							return "other"
+						},
+						GetReplicaHistoryReactor: func(definition *jamiethompsonmev1alpha1.HistoryBootstrap, now time.Time, syncPeriod time.Duration) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+							return nil, errors.New("wrong bootstrapper")
+						},
+					},
+					&fake.Bootstrap{
+						GetTypeReactor: func() string {
+							return "Prometheus"
+						},
+						GetReplicaHistoryReactor: func(definition *jamiethompsonmev1alpha1.HistoryBootstrap, now time.Time, syncPeriod time.Duration) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+							return []jamiethompsonmev1alpha1.TimestampedReplicas{
+								{
+									Time:     &metav1.Time{Time: now.Add(-syncPeriod)},
+									Replicas: 3,
+								},
+							}, nil
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.bootstrapper.GetReplicaHistory(test.definition, now, time.Minute)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestCombinedBootstrap_GetType(t *testing.T) {
+	bootstrapper := &bootstrap.CombinedBootstrap{}
+	result := bootstrapper.GetType()
+	if !cmp.Equal("combined", result) {
+		t.Errorf("type mismatch (-want +got):\n%s", cmp.Diff("combined", result))
+	}
+}
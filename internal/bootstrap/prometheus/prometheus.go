@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package prometheus provides a history bootstrapper that gets a replica history by running a PromQL range query
+// against Prometheus
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	gohttp "net/http"
+	"time"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/prometheus/client_golang/api"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Type Prometheus represents a history bootstrap from a Prometheus range query
+const Type = jamiethompsonmev1alpha1.HistoryBootstrapPrometheus
+
+const defaultTimeout = 30000
+
+// Bootstrap gets a replica history by running a PromQL range query, the query must evaluate to a single series of
+// replica counts
+type Bootstrap struct {
+	// RoundTripper is used to make the HTTP requests to Prometheus, if not set the Prometheus client default is used
+	RoundTripper gohttp.RoundTripper
+}
+
+// GetReplicaHistory runs the PromQL range query from the lookback up until one step before the current time, using
+// each value in the resulting series as a replica count rounded up to the nearest whole replica
+func (b *Bootstrap) GetReplicaHistory(definition *jamiethompsonmev1alpha1.HistoryBootstrap, now time.Time, syncPeriod time.Duration) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+	if definition.Prometheus == nil {
+		return nil, fmt.Errorf("missing required 'prometheus' configuration on history bootstrap")
+	}
+
+	step := syncPeriod
+	if definition.Prometheus.Step != nil {
+		step = definition.Prometheus.Step.Duration
+	}
+
+	if step <= 0 {
+		return nil, fmt.Errorf("invalid step '%s', must be greater than 0", step)
+	}
+
+	timeout := defaultTimeout
+	if definition.Prometheus.Timeout != nil {
+		timeout = *definition.Prometheus.Timeout
+	}
+
+	roundTripper := b.RoundTripper
+	if roundTripper == nil {
+		roundTripper = api.DefaultRoundTripper
+	}
+
+	client, err := api.NewClient(api.Config{
+		Address:      definition.Prometheus.Address,
+		RoundTripper: roundTripper,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up prometheus client: %w", err)
+	}
+
+	// The replica count for the current time is calculated by the PHPA, so the history stops one step before it
+	queryRange := prometheusv1.Range{
+		Start: now.Add(-definition.Prometheus.Lookback.Duration),
+		End:   now.Add(-step),
+		Step:  step,
+	}
+
+	if !queryRange.Start.Before(queryRange.End) {
+		return []jamiethompsonmev1alpha1.TimestampedReplicas{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Millisecond)
+	defer cancel()
+
+	value, _, err := prometheusv1.NewAPI(client).QueryRange(ctx, definition.Prometheus.Query, queryRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run prometheus query: %w", err)
+	}
+
+	matrix, ok := value.(model.Matrix)
+	if !ok {
+		return nil, fmt.Errorf("invalid prometheus query result type '%s', must be a range vector", value.Type())
+	}
+
+	if len(matrix) != 1 {
+		return nil, fmt.Errorf("invalid prometheus query result, must return exactly 1 series, returned %d",
+			len(matrix))
+	}
+
+	replicaHistory := []jamiethompsonmev1alpha1.TimestampedReplicas{}
+	for _, sample := range matrix[0].Values {
+		value := float64(sample.Value)
+		if math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+			// Skip any samples that can't be used as a replica count
+			continue
+		}
+
+		replicaHistory = append(replicaHistory, jamiethompsonmev1alpha1.TimestampedReplicas{
+			Time:     &metav1.Time{Time: sample.Timestamp.Time().UTC()},
+			Replicas: int32(math.Ceil(value)),
+		})
+	}
+
+	return replicaHistory, nil
+}
+
+// GetType returns the Prometheus bootstrapper type
+func (b *Bootstrap) GetType() string {
+	return Type
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus_test
+
+import (
+	"errors"
+	"fmt"
+	gohttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/bootstrap/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestBootstrap_GetReplicaHistory(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	// 2023-01-01T12:00:00Z
+	now := time.Unix(1672574400, 0).UTC()
+
+	var tests = []struct {
+		description    string
+		expected       []jamiethompsonmev1alpha1.TimestampedReplicas
+		expectedErr    error
+		expectedParams map[string]string
+		statusCode     int
+		response       string
+		definition     *jamiethompsonmev1alpha1.HistoryBootstrap
+		syncPeriod     time.Duration
+	}{
+		{
+			description: "Fail, missing prometheus configuration",
+			expected:    nil,
+			expectedErr: errors.New("missing required 'prometheus' configuration on history bootstrap"),
+			definition: &jamiethompsonmev1alpha1.HistoryBootstrap{
+				Type: "Prometheus",
+			},
+			syncPeriod: time.Minute,
+		},
+		{
+			description: "Fail, invalid step",
+			expected:    nil,
+			expectedErr: errors.New("invalid step '0s', must be greater than 0"),
+			definition: &jamiethompsonmev1alpha1.HistoryBootstrap{
+				Type: "Prometheus",
+				Prometheus: &jamiethompsonmev1alpha1.PrometheusHistoryBootstrap{
+					Query:    "test",
+					Lookback: metav1.Duration{Duration: 2 * time.Hour},
+					Step:     &metav1.Duration{Duration: 0},
+				},
+			},
+			syncPeriod: time.Minute,
+		},
+		{
+			description: "Fail, query error",
+			expected:    nil,
+			expectedErr: errors.New("failed to run prometheus query: bad_data: 1:5: parse error: unexpected end of input"),
+			statusCode:  gohttp.StatusBadRequest,
+			response:    `{"status":"error","errorType":"bad_data","error":"1:5: parse error: unexpected end of input"}`,
+			definition: &jamiethompsonmev1alpha1.HistoryBootstrap{
+				Type: "Prometheus",
+				Prometheus: &jamiethompsonmev1alpha1.PrometheusHistoryBootstrap{
+					Query:    "sum(",
+					Lookback: metav1.Duration{Duration: 2 * time.Hour},
+				},
+			},
+			syncPeriod: time.Minute,
+		},
+		{
+			description: "Fail, query result is not a range vector",
+			expected:    nil,
+			expectedErr: errors.New("invalid prometheus query result type 'scalar', must be a range vector"),
+			statusCode:  gohttp.StatusOK,
+			response:    `{"status":"success","data":{"resultType":"scalar","result":[1672574400,"1"]}}`,
+			definition: &jamiethompsonmev1alpha1.HistoryBootstrap{
+				Type: "Prometheus",
+				Prometheus: &jamiethompsonmev1alpha1.PrometheusHistoryBootstrap{
+					Query:    "test",
+					Lookback: metav1.Duration{Duration: 2 * time.Hour},
+				},
+			},
+			syncPeriod: time.Minute,
+		},
+		{
+			description: "Fail, query returns no series",
+			expected:    nil,
+			expectedErr: errors.New("invalid prometheus query result, must return exactly 1 series, returned 0"),
+			statusCode:  gohttp.StatusOK,
+			response:    `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+			definition: &jamiethompsonmev1alpha1.HistoryBootstrap{
+				Type: "Prometheus",
+				Prometheus: &jamiethompsonmev1alpha1.PrometheusHistoryBootstrap{
+					Query:    "test",
+					Lookback: metav1.Duration{Duration: 2 * time.Hour},
+				},
+			},
+			syncPeriod: time.Minute,
+		},
+		{
+			description: "Fail, query returns multiple series",
+			expected:    nil,
+			expectedErr: errors.New("invalid prometheus query result, must return exactly 1 series, returned 2"),
+			statusCode:  gohttp.StatusOK,
+			response: `{"status":"success","data":{"resultType":"matrix","result":[` +
+				`{"metric":{"pod":"a"},"values":[[1672574340,"1"]]},` +
+				`{"metric":{"pod":"b"},"values":[[1672574340,"1"]]}]}}`,
+			definition: &jamiethompsonmev1alpha1.HistoryBootstrap{
+				Type: "Prometheus",
+				Prometheus: &jamiethompsonmev1alpha1.PrometheusHistoryBootstrap{
+					Query:    "test",
+					Lookback: metav1.Duration{Duration: 2 * time.Hour},
+				},
+			},
+			syncPeriod: time.Minute,
+		},
+		{
+			description: "Success, lookback no longer than a step, no history",
+			expected:    []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			expectedErr: nil,
+			definition: &jamiethompsonmev1alpha1.HistoryBootstrap{
+				Type: "Prometheus",
+				Prometheus: &jamiethompsonmev1alpha1.PrometheusHistoryBootstrap{
+					Query:    "test",
+					Lookback: metav1.Duration{Duration: time.Minute},
+				},
+			},
+			syncPeriod: time.Minute,
+		},
+		{
+			description: "Success, default step of the sync period, values rounded up and invalid values skipped",
+			expected: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Time:     &metav1.Time{Time: now.Add(-3 * time.Minute)},
+					Replicas: 2,
+				},
+				{
+					Time:     &metav1.Time{Time: now.Add(-2 * time.Minute)},
+					Replicas: 3,
+				},
+			},
+			expectedErr: nil,
+			expectedParams: map[string]string{
+				"query": "sum(kube_deployment_status_replicas)",
+				"start": "1672574220",
+				"end":   "1672574340",
+				"step":  "60",
+			},
+			statusCode: gohttp.StatusOK,
+			response: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[` +
+				`[1672574220,"2"],[1672574280,"2.2"],[1672574340,"NaN"]]}]}}`,
+			definition: &jamiethompsonmev1alpha1.HistoryBootstrap{
+				Type: "Prometheus",
+				Prometheus: &jamiethompsonmev1alpha1.PrometheusHistoryBootstrap{
+					Query:    "sum(kube_deployment_status_replicas)",
+					Lookback: metav1.Duration{Duration: 3 * time.Minute},
+				},
+			},
+			syncPeriod: time.Minute,
+		},
+		{
+			description: "Success, custom step and timeout",
+			expected: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Time:     &metav1.Time{Time: now.Add(-2 * time.Hour)},
+					Replicas: 5,
+				},
+				{
+					Time:     &metav1.Time{Time: now.Add(-time.Hour)},
+					Replicas: 7,
+				},
+			},
+			expectedErr: nil,
+			expectedParams: map[string]string{
+				"query": "test",
+				"start": "1672567200",
+				"end":   "1672570800",
+				"step":  "3600",
+			},
+			statusCode: gohttp.StatusOK,
+			response: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[` +
+				`[1672567200,"5"],[1672570800,"7"]]}]}}`,
+			definition: &jamiethompsonmev1alpha1.HistoryBootstrap{
+				Type: "Prometheus",
+				Prometheus: &jamiethompsonmev1alpha1.PrometheusHistoryBootstrap{
+					Query:    "test",
+					Lookback: metav1.Duration{Duration: 2 * time.Hour},
+					Step:     &metav1.Duration{Duration: time.Hour},
+					Timeout:  intPtr(1000),
+				},
+			},
+			syncPeriod: time.Minute,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			server := httptest.NewServer(gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+				if r.URL.Path != "/api/v1/query_range" {
+					w.WriteHeader(gohttp.StatusNotFound)
+					return
+				}
+
+				err := r.ParseForm()
+				if err != nil {
+					w.WriteHeader(gohttp.StatusBadRequest)
+					return
+				}
+
+				for key, expected := range test.expectedParams {
+					if actual := r.Form.Get(key); actual != expected {
+						w.WriteHeader(gohttp.StatusBadRequest)
+						fmt.Fprintf(w, `{"status":"error","errorType":"bad_data","error":"%s mismatch, want %s, got %s"}`,
+							key, expected, actual)
+						return
+					}
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(test.statusCode)
+				fmt.Fprint(w, test.response)
+			}))
+			defer server.Close()
+
+			if test.definition.Prometheus != nil {
+				test.definition.Prometheus.Address = server.URL
+			}
+
+			bootstrapper := &prometheus.Bootstrap{}
+			result, err := bootstrapper.GetReplicaHistory(test.definition, now, test.syncPeriod)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestBootstrap_GetType(t *testing.T) {
+	bootstrapper := &prometheus.Bootstrap{}
+	result := bootstrapper.GetType()
+	if !cmp.Equal("Prometheus", result) {
+		t.Errorf("type mismatch (-want +got):\n%s", cmp.Diff("Prometheus", result))
+	}
+}
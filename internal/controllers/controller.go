@@ -18,28 +18,37 @@ package controllers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/scale"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/jthomperoo/k8shorizmetrics/v2"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics"
 	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/accuracy"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/bootstrap"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/metricinput"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/monitoring"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/scalebehavior"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/validation"
@@ -59,12 +68,30 @@ const (
 	defaultPerSyncPeriod           = 1
 )
 
+// Model accuracy constants
+const (
+	// defaultAccuracyWindow is the number of a model's most recent scored predictions used to calculate its accuracy
+	defaultAccuracyWindow = 20
+)
+
+// Model tuning constants
+const (
+	defaultRefitInterval = time.Hour
+)
+
 // PHPA scale constraints
 const (
-	defaultDecisionType = jamiethompsonmev1alpha1.DecisionMaximum
-	defaultMinReplicas  = 1
+	defaultDecisionType     = jamiethompsonmev1alpha1.DecisionMaximum
+	defaultDecisionQuantile = 0.9
+	defaultWeight           = 1
+	defaultMinReplicas      = 1
+	defaultMode             = jamiethompsonmev1alpha1.ModeActive
 )
 
+// calculatedPredictionName is the name given to the replica count calculated from the current metrics when it is
+// considered alongside the models' predictions, validation rejects models with this name
+const calculatedPredictionName = validation.CalculatedModelName
+
 // Downscale constants
 const (
 	defaultDownscaleStabilization                 = int32(300)
@@ -81,10 +108,6 @@ const (
 	defaultUpscalePodsPolicyValue               = int32(4)
 )
 
-const (
-	configMapDataKey = "data"
-)
-
 // PredictiveHorizontalPodAutoscalerReconciler reconciles a PredictiveHorizontalPodAutoscaler object
 type PredictiveHorizontalPodAutoscalerReconciler struct {
 	client.Client
@@ -93,14 +116,30 @@ type PredictiveHorizontalPodAutoscalerReconciler struct {
 	Gatherer    k8shorizmetrics.Gatherer
 	Evaluator   k8shorizmetrics.Evaluator
 	Predicter   prediction.Predicter
+	Recorder    record.EventRecorder
+	// HistoryStore stores the model histories of each PHPA between sync periods
+	HistoryStore history.Store
+	// Bootstrapper fills empty model replica histories for models with a history bootstrap, if not set model
+	// histories are not bootstrapped
+	Bootstrapper bootstrap.Bootstrapper
+	// AccuracyWindow is the number of a model's most recent scored predictions used to calculate its accuracy, if not
+	// set a default of 20 is used
+	AccuracyWindow int
+	// Clock provides the current time for each sync period, if not set the system clock is used
+	Clock clock.PassiveClock
+	// CustomAlgorithmDirectory is the only directory Custom model algorithms can be run from, if not set Custom
+	// models are invalid
+	CustomAlgorithmDirectory string
 }
 
 //+kubebuilder:rbac:groups=jamiethompson.me,resources=predictivehorizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=jamiethompson.me,resources=predictivehorizontalpodautoscalers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=jamiethompson.me,resources=predictivehorizontalpodautoscalers/finalizers,verbs=update
+//+kubebuilder:rbac:groups=jamiethompson.me,resources=phpamodelstates,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list
 //+kubebuilder:rbac:groups=core,resources=replicationcontrollers/scale,verbs=get;update;patch
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=apps,resources=deployments/scale;replicaset/scale;statefulset/scale,verbs=get;update;patch
 //+kubebuilder:rbac:groups=metrics.k8s.io,resources=*,verbs=get;list
 //+kubebuilder:rbac:groups=custom.metrics.k8s.io,resources=*,verbs=get;list
@@ -116,6 +155,7 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) Reconcile(ctx context.Cont
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			monitoring.DeletePHPA(req.Namespace, req.Name)
 			return reconcile.Result{}, nil
 		}
 
@@ -123,14 +163,19 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) Reconcile(ctx context.Cont
 		return reconcile.Result{RequeueAfter: defaultErrorRetryPeriod}, err
 	}
 
-	err = validation.Validate(instance)
+	err = validation.Validate(instance, r.CustomAlgorithmDirectory)
 	if err != nil {
 		logger.Error(err, "invalid PredictiveHorizontalPodAutoscaler, disabling PHPA until changed to be valid")
+		r.recordFailure(ctx, instance, jamiethompsonmev1alpha1.ConditionValid, "InvalidSpec",
+			"the PHPA spec is invalid, the PHPA is disabled until it is changed to be valid: %v", err)
 		// We stop processing here without requeueing since the PHPA is invalid, if changes are made to the spec that
 		// make it valid it will be reconciled again and the validation checked
 		return reconcile.Result{}, nil
 	}
 
+	setCondition(instance, jamiethompsonmev1alpha1.ConditionValid, metav1.ConditionTrue, "ValidSpec",
+		"the PHPA spec is valid")
+
 	scaleTargetRef := instance.Spec.ScaleTargetRef
 
 	err = r.preScaleStatusCheck(ctx, instance)
@@ -139,74 +184,17 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) Reconcile(ctx context.Cont
 		return reconcile.Result{RequeueAfter: defaultErrorRetryPeriod}, err
 	}
 
-	configMapName := fmt.Sprintf("predictive-horizontal-pod-autoscaler-%s-data", instance.Name)
-	phpaData := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{}
-	configMap := &corev1.ConfigMap{}
-
-	// Check if configmap exists, if not create a blank one
-	err = r.Client.Get(context.Background(),
-		types.NamespacedName{
-			Name:      configMapName,
-			Namespace: instance.Namespace,
-		},
-		configMap)
+	phpaData, historyVersion, err := r.HistoryStore.Load(ctx, instance)
 	if err != nil {
-		if k8serrors.IsNotFound(err) {
-			logger.V(1).Info("No configmap found for PHPA, creating a new one",
-				"scaleTargetRef", scaleTargetRef)
-
-			configMap := &corev1.ConfigMap{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      fmt.Sprintf("predictive-horizontal-pod-autoscaler-%s-data", instance.Name),
-					Namespace: instance.Namespace,
-				},
-			}
-
-			configMap.SetOwnerReferences([]metav1.OwnerReference{{
-				APIVersion: instance.APIVersion,
-				Kind:       instance.Kind,
-				Name:       instance.Name,
-				UID:        instance.UID,
-			}})
-
-			phpaData.ModelHistories = map[string]jamiethompsonmev1alpha1.ModelHistory{}
-
-			data, err := json.Marshal(phpaData)
-			if err != nil {
-				// Should not occur, panic
-				panic(err)
-			}
-
-			configMap.Data = map[string]string{
-				configMapDataKey: string(data),
-			}
-
-			err = r.Client.Create(ctx, configMap)
-			if err != nil {
-				logger.Error(err, "failed to create PHPA configmap", "scaleTargetRef", scaleTargetRef)
-				return reconcile.Result{RequeueAfter: defaultErrorRetryPeriod}, err
-			}
-
-			// After creating the config map lets wait until the owner references are set up, then the reconcile
-			// loop will kick in again
-			return reconcile.Result{}, nil
-		}
-		logger.Error(err, "failed to get PHPA config map and data", "scaleTargetRef", scaleTargetRef)
+		logger.Error(err, "failed to load PHPA model history", "scaleTargetRef", scaleTargetRef)
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "FailedGetModelHistory",
+			"failed to get the model history: %v", err)
 		return reconcile.Result{RequeueAfter: defaultErrorRetryPeriod}, err
 	}
 
-	err = json.Unmarshal([]byte(configMap.Data[configMapDataKey]), phpaData)
-	if err != nil {
-		logger.Error(err, "failed to parse PHPA data", "scaleTargetRef", scaleTargetRef)
-		return reconcile.Result{RequeueAfter: defaultErrorRetryPeriod}, err
-	}
-
-	syncPeriod := defaultSyncPeriod
-	if instance.Spec.SyncPeriod != nil {
-		syncPeriod = time.Duration(*instance.Spec.SyncPeriod) * time.Millisecond
-	}
+	syncPeriod := SyncPeriod(instance)
 
-	now := time.Now().UTC()
+	now := r.now()
 
 	// Check the last scale of the PHPA, make sure we're not scaling too early
 	lastScaleTime := instance.Status.LastScaleTime
@@ -223,6 +211,8 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) Reconcile(ctx context.Cont
 	resourceGV, err := schema.ParseGroupVersion(scaleTargetRef.APIVersion)
 	if err != nil {
 		logger.Error(err, "failed to parse group version of target resource", "scaleTargetRef", scaleTargetRef)
+		r.recordFailure(ctx, instance, jamiethompsonmev1alpha1.ConditionAbleToScale, "FailedGetScale",
+			"the PHPA controller was unable to get the target's current scale: %v", err)
 		return reconcile.Result{RequeueAfter: defaultErrorRetryPeriod}, err
 	}
 
@@ -234,41 +224,147 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) Reconcile(ctx context.Cont
 	scale, err := r.ScaleClient.Scales(instance.Namespace).Get(ctx, targetGR, scaleTargetRef.Name, metav1.GetOptions{})
 	if err != nil {
 		logger.Error(err, "failed to get scale subresource", "scaleTargetRef", scaleTargetRef)
+		r.recordFailure(ctx, instance, jamiethompsonmev1alpha1.ConditionAbleToScale, "FailedGetScale",
+			"the PHPA controller was unable to get the target's current scale: %v", err)
 		return reconcile.Result{RequeueAfter: defaultErrorRetryPeriod}, err
 	}
 
-	calculatedReplicas, err := r.calculateReplicas(instance, scale)
+	calculatedReplicas, gatheredMetrics, err := r.calculateReplicas(instance, scale)
 	if err != nil {
 		logger.Error(err, "failed to calculate replicas based on metrics",
 			"scaleTargetRef", scaleTargetRef,
 			"currentReplicas", scale.Spec.Replicas)
+		r.recordFailure(ctx, instance, jamiethompsonmev1alpha1.ConditionScalingActive, "FailedComputeMetricsReplicas",
+			"the PHPA was unable to compute the replica count: %v", err)
 		return reconcile.Result{RequeueAfter: defaultErrorRetryPeriod}, err
 	}
 
+	setCondition(instance, jamiethompsonmev1alpha1.ConditionScalingActive, metav1.ConditionTrue, "ValidMetricFound",
+		"the PHPA was able to successfully calculate a replica count from metrics")
+
+	monitoring.RecordCalculatedReplicas(instance.Namespace, instance.Name, calculatedReplicas)
+
+	currentReplicas := scale.Spec.Replicas
+
 	// This function doesn't return any errors, since if it fails to process a model it will skip and continue
 	// processing without that model's results
-	predictedReplicas, phpaData := r.processModels(ctx, instance, phpaData, now, scale.Spec.Replicas,
-		calculatedReplicas)
-
-	err = r.updateConfigMapData(ctx, configMap, phpaData)
+	decision, phpaData := r.decide(ctx, instance, phpaData, now, syncPeriod, currentReplicas, calculatedReplicas,
+		gatheredMetrics)
+	targetReplicas := decision.targetReplicas
+
+	err = r.HistoryStore.Save(ctx, instance, phpaData, historyVersion)
+	if k8serrors.IsConflict(err) {
+		// The model history has been changed since it was loaded, for example by a model history import, so rather
+		// than overwrite the change the sync is retried using the latest model history
+		logger.V(0).Info("Model history changed since it was loaded, retrying sync", "scaleTargetRef", scaleTargetRef)
+		return reconcile.Result{Requeue: true}, nil
+	}
 	if err != nil {
-		logger.Error(err, "failed to update PHPA configmap",
+		monitoring.RecordModelHistoryWriteError(instance.Namespace, instance.Name)
+		logger.Error(err, "failed to save PHPA model history",
 			"scaleTargetRef", scaleTargetRef)
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "FailedUpdateModelHistory",
+			"failed to update the model history: %v", err)
 		return reconcile.Result{RequeueAfter: defaultErrorRetryPeriod}, err
 	}
 
+	mode := defaultMode
+	if instance.Spec.Mode != nil {
+		mode = *instance.Spec.Mode
+	}
+
+	// Only scale if the current replicas is different than the target
+	if currentReplicas != targetReplicas && mode == jamiethompsonmev1alpha1.ModeDryRun {
+		// In dry run mode the target is never scaled, the replica count is only recorded. No scale events are recorded
+		// since the target's replica count has not changed, so scaling policies are not limited by decisions that were
+		// never applied
+		logger.V(0).Info("Dry run, not scaling resource",
+			"scaleTargetRef", scaleTargetRef,
+			"currentReplicas", currentReplicas,
+			"targetReplicas", targetReplicas)
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "DryRunRescale",
+			"Dry run, would rescale to new size: %d; current size: %d", targetReplicas, currentReplicas)
+		setCondition(instance, jamiethompsonmev1alpha1.ConditionAbleToScale, metav1.ConditionTrue, "DryRun",
+			"the PHPA is in dry run mode, the target scale was not updated to %d", targetReplicas)
+	} else if currentReplicas != targetReplicas {
+		scale.Spec.Replicas = targetReplicas
+		_, err := r.ScaleClient.Scales(instance.Namespace).Update(ctx, targetGR, scale, metav1.UpdateOptions{})
+		if err != nil {
+			logger.Error(err, "failed to update scale resource",
+				"scaleTargetRef", scaleTargetRef,
+				"currentReplicas", scale.Spec.Replicas,
+				"targetReplicas", targetReplicas)
+			r.recordFailure(ctx, instance, jamiethompsonmev1alpha1.ConditionAbleToScale, "FailedUpdateScale",
+				"the PHPA controller was unable to update the target scale: %v", err)
+			return reconcile.Result{RequeueAfter: defaultErrorRetryPeriod}, err
+		}
+
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "SuccessfulRescale", "New size: %d; previous size: %d",
+			targetReplicas, currentReplicas)
+		setCondition(instance, jamiethompsonmev1alpha1.ConditionAbleToScale, metav1.ConditionTrue, "SucceededRescale",
+			"the PHPA controller was able to update the target scale to %d", targetReplicas)
+
+		recordScaleEvent(instance, currentReplicas, targetReplicas, r.now())
+	} else {
+		setCondition(instance, jamiethompsonmev1alpha1.ConditionAbleToScale, metav1.ConditionTrue, "ReadyForNewScale",
+			"recommended size matches current size")
+	}
+
+	setDecisionStatus(instance, phpaData, decision, now, scale.Spec.Replicas, calculatedReplicas)
+	err = r.Client.Status().Update(ctx, instance)
+	if err != nil {
+		logger.Error(err, "failed to update status of resource",
+			"scaleTargetRef", scaleTargetRef,
+			"currentReplicas", scale.Spec.Replicas,
+			"targetReplicas", targetReplicas,
+			"scaleTime", now)
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "FailedUpdateStatus",
+			"failed to update the PHPA status: %v", err)
+		return reconcile.Result{RequeueAfter: defaultErrorRetryPeriod}, err
+	}
+
+	monitoring.RecordModelAccuracy(instance.Namespace, instance.Name, instance.Status.Models)
+
+	logger.V(0).Info("Scaled resource",
+		"scaleTargetRef", scaleTargetRef,
+		"currentReplicas", scale.Spec.Replicas,
+		"targetReplicas", targetReplicas)
+
+	return reconcile.Result{RequeueAfter: syncPeriod}, nil
+
+}
+
+// decision is the result of deciding the target replica count for a sync period
+type decision struct {
+	modelResults            map[string]modelResult
+	decidedReplicas         int32
+	targetReplicas          int32
+	scaleUpReplicaHistory   []jamiethompsonmev1alpha1.TimestampedReplicas
+	scaleDownReplicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+}
+
+// decide processes the models and decides the target replica count for the sync period, choosing between the
+// calculated replicas and the models' predictions using the decision type and then applying the scaling behavior and
+// min and max replicas
+func (r *PredictiveHorizontalPodAutoscalerReconciler) decide(ctx context.Context,
+	instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler,
+	phpaData *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, now time.Time, syncPeriod time.Duration,
+	currentReplicas int32, calculatedReplicas int32, gatheredMetrics []*metrics.Metric) (*decision, *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData) {
+	predictedReplicas, modelResults, phpaData := r.processModels(ctx, instance, phpaData, now, syncPeriod,
+		currentReplicas, calculatedReplicas, gatheredMetrics)
+
+	setModelsHealthyCondition(instance, phpaData, modelResults)
+
 	decisionType := defaultDecisionType
 	if instance.Spec.DecisionType != nil {
 		decisionType = *instance.Spec.DecisionType
 	}
 
-	targetReplicas := scalebehavior.DecideTargetReplicasByScalingStrategy(decisionType, predictedReplicas)
-
-	currentReplicas := scale.Spec.Replicas
+	decidedReplicas := scalebehavior.DecideTargetReplicasByScalingStrategy(decisionType, predictedReplicas)
 
 	timestampedReplicaValue := jamiethompsonmev1alpha1.TimestampedReplicas{
 		Time:     &metav1.Time{Time: now},
-		Replicas: targetReplicas,
+		Replicas: decidedReplicas,
 	}
 
 	behavior := fillBehaviorDefaults(instance.Spec.Behavior)
@@ -296,107 +392,131 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) Reconcile(ctx context.Cont
 		instance.Status.ScaleDownReplicaHistory, *behavior.ScaleDown.StabilizationWindowSeconds, now)
 	scaleDownReplicaHistory = append(scaleDownReplicaHistory, timestampedReplicaValue)
 
-	targetReplicas = scalebehavior.DecideTargetReplicasByBehavior(behavior, currentReplicas, targetReplicas, minReplicas,
-		instance.Spec.MaxReplicas, scaleUpReplicaHistory, scaleDownReplicaHistory, scaleUpEventHistory,
+	targetReplicas := scalebehavior.DecideTargetReplicasByBehavior(behavior, currentReplicas, decidedReplicas,
+		minReplicas, instance.Spec.MaxReplicas, scaleUpReplicaHistory, scaleDownReplicaHistory, scaleUpEventHistory,
 		scaleDownEventHistory, now)
 
-	// Only scale if the current replicas is different than the target
-	if currentReplicas != targetReplicas {
-		scale.Spec.Replicas = targetReplicas
-		_, err := r.ScaleClient.Scales(instance.Namespace).Update(ctx, targetGR, scale, metav1.UpdateOptions{})
-		if err != nil {
-			logger.Error(err, "failed to update scale resource",
-				"scaleTargetRef", scaleTargetRef,
-				"currentReplicas", scale.Spec.Replicas,
-				"targetReplicas", targetReplicas)
-			return reconcile.Result{RequeueAfter: defaultErrorRetryPeriod}, err
-		}
-
-		scaleTime := time.Now().UTC()
-
-		if targetReplicas > currentReplicas {
-			// Scale up
-			scaleEvent := jamiethompsonmev1alpha1.TimestampedReplicas{
-				Time:     &metav1.Time{Time: scaleTime},
-				Replicas: targetReplicas - currentReplicas,
-			}
-			instance.Status.ScaleUpEventHistory = append(instance.Status.ScaleUpEventHistory, scaleEvent)
-			instance.Status.ScaleUpEventHistory = scalebehavior.PruneTimestampedReplicasToWindow(
-				instance.Status.ScaleUpEventHistory,
-				scaleUpLongestPolicyPeriod,
-				scaleTime)
-		} else {
-			// Scale down
-			scaleEvent := jamiethompsonmev1alpha1.TimestampedReplicas{
-				Time:     &metav1.Time{Time: scaleTime},
-				Replicas: currentReplicas - targetReplicas,
-			}
-			instance.Status.ScaleDownEventHistory = append(instance.Status.ScaleDownEventHistory, scaleEvent)
-			instance.Status.ScaleDownEventHistory = scalebehavior.PruneTimestampedReplicasToWindow(
-				instance.Status.ScaleDownEventHistory,
-				scaleDownLongestPolicyPeriod,
-				scaleTime)
-		}
-	}
-
-	instance.Status.LastScaleTime = &metav1.Time{Time: now}
-	instance.Status.DesiredReplicas = targetReplicas
-	instance.Status.CurrentReplicas = scale.Spec.Replicas
-	instance.Status.ScaleDownReplicaHistory = scaleDownReplicaHistory
-	instance.Status.ScaleUpReplicaHistory = scaleUpReplicaHistory
-	err = r.Client.Status().Update(ctx, instance)
-	if err != nil {
-		logger.Error(err, "failed to update status of resource",
-			"scaleTargetRef", scaleTargetRef,
-			"currentReplicas", scale.Spec.Replicas,
-			"targetReplicas", targetReplicas,
-			"scaleTime", now)
-		return reconcile.Result{RequeueAfter: defaultErrorRetryPeriod}, err
-	}
+	monitoring.RecordTargetReplicas(instance.Namespace, instance.Name, targetReplicas)
 
-	logger.V(0).Info("Scaled resource",
-		"scaleTargetRef", scaleTargetRef,
-		"currentReplicas", scale.Spec.Replicas,
-		"targetReplicas", targetReplicas)
-
-	return reconcile.Result{RequeueAfter: syncPeriod}, nil
+	setScalingLimitedCondition(instance, decidedReplicas, targetReplicas, minReplicas, instance.Spec.MaxReplicas)
 
+	return &decision{
+		modelResults:            modelResults,
+		decidedReplicas:         decidedReplicas,
+		targetReplicas:          targetReplicas,
+		scaleUpReplicaHistory:   scaleUpReplicaHistory,
+		scaleDownReplicaHistory: scaleDownReplicaHistory,
+	}, phpaData
 }
 
-// updateConfigMapData updates the PHPA's configmap and the data it holds
-func (r *PredictiveHorizontalPodAutoscalerReconciler) updateConfigMapData(ctx context.Context, configMap *corev1.ConfigMap,
-	phpaData *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData) error {
-	data, err := json.Marshal(phpaData)
-	if err != nil {
-		// Should not occur, panic
-		panic(err)
-	}
+// recordScaleEvent records the change in replicas when the target is scaled from the current replicas to the target
+// replicas in the PHPA's scale event history, used for applying scaling policies
+func recordScaleEvent(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, currentReplicas int32,
+	targetReplicas int32, scaleTime time.Time) {
+	behavior := fillBehaviorDefaults(instance.Spec.Behavior)
 
-	configMap.Data = map[string]string{
-		configMapDataKey: string(data),
+	if targetReplicas > currentReplicas {
+		// Scale up
+		scaleEvent := jamiethompsonmev1alpha1.TimestampedReplicas{
+			Time:     &metav1.Time{Time: scaleTime},
+			Replicas: targetReplicas - currentReplicas,
+		}
+		instance.Status.ScaleUpEventHistory = append(instance.Status.ScaleUpEventHistory, scaleEvent)
+		instance.Status.ScaleUpEventHistory = scalebehavior.PruneTimestampedReplicasToWindow(
+			instance.Status.ScaleUpEventHistory,
+			scalebehavior.GetLongestPolicyPeriod(behavior.ScaleUp),
+			scaleTime)
+	} else {
+		// Scale down
+		scaleEvent := jamiethompsonmev1alpha1.TimestampedReplicas{
+			Time:     &metav1.Time{Time: scaleTime},
+			Replicas: currentReplicas - targetReplicas,
+		}
+		instance.Status.ScaleDownEventHistory = append(instance.Status.ScaleDownEventHistory, scaleEvent)
+		instance.Status.ScaleDownEventHistory = scalebehavior.PruneTimestampedReplicasToWindow(
+			instance.Status.ScaleDownEventHistory,
+			scalebehavior.GetLongestPolicyPeriod(behavior.ScaleDown),
+			scaleTime)
 	}
+}
 
-	err = r.Client.Update(ctx, configMap)
-	if err != nil {
-		return fmt.Errorf("failed to update config map data: %w", err)
-	}
+// setDecisionStatus sets the PHPA status fields describing the decision made in the sync period
+func setDecisionStatus(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler,
+	phpaData *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, decision *decision, now time.Time,
+	currentReplicas int32, calculatedReplicas int32) {
+	instance.Status.LastScaleTime = &metav1.Time{Time: now}
+	instance.Status.DesiredReplicas = decision.targetReplicas
+	instance.Status.CurrentReplicas = currentReplicas
+	instance.Status.CalculatedReplicas = calculatedReplicas
+	instance.Status.DecidedReplicas = decision.decidedReplicas
+	instance.Status.ScaleDownReplicaHistory = decision.scaleDownReplicaHistory
+	instance.Status.ScaleUpReplicaHistory = decision.scaleUpReplicaHistory
+	instance.Status.Models = modelStatuses(instance, phpaData, decision.modelResults)
+}
 
-	return nil
+// modelResult is the outcome of processing a model for a sync period, whether it ran and if not the reason why
+type modelResult struct {
+	ran     bool
+	reason  string
+	message string
 }
 
 // processModels processes every model provided in the spec, it does not return any errors and will instead simply
-// log if a model has failed to be processed, allowing the other models/the HPA calculated replicas to be used instead
+// log and record an event if a model has failed to be processed, allowing the other models/the HPA calculated replicas
+// to be used instead. The result of processing each model is returned, keyed by model name
 func (r *PredictiveHorizontalPodAutoscalerReconciler) processModels(ctx context.Context,
 	instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler,
-	phpaData *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, now time.Time, currentReplicas int32,
-	calculatedReplicas int32) ([]int32, *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData) {
+	phpaData *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, now time.Time, syncPeriod time.Duration,
+	currentReplicas int32, calculatedReplicas int32, gatheredMetrics []*metrics.Metric) ([]scalebehavior.PredictedReplicas, map[string]modelResult, *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData) {
 
 	logger := log.FromContext(ctx)
 
 	scaleTargetRef := instance.Spec.ScaleTargetRef
 
+	// Raw metric values are only needed by models that use metrics as their input, any failure to convert the metrics
+	// is only reported if one of these models is processed
+	metricValues, metricValuesErr := metricinput.Values(gatheredMetrics)
+
+	// If the metrics in the spec have changed the shared metric values no longer line up, start again
+	if metricValuesErr == nil {
+		for _, timestampedMetrics := range phpaData.MetricHistory {
+			if len(timestampedMetrics.Values) != len(metricValues) {
+				logger.V(1).Info("Clearing shared metric history, metrics in spec have changed",
+					"scaleTargetRef", scaleTargetRef)
+				phpaData.MetricHistory = nil
+				break
+			}
+		}
+	}
+
+	calculatedWeight := float64(defaultWeight)
+	if instance.Spec.CalculatedWeight != nil {
+		calculatedWeight = *instance.Spec.CalculatedWeight
+	}
+
 	// Set up a slice with the calculated replicas as the first prediction
-	predictedReplicas := []int32{calculatedReplicas}
+	predictedReplicas := []scalebehavior.PredictedReplicas{
+		{
+			Name:     calculatedPredictionName,
+			Replicas: calculatedReplicas,
+			Weight:   calculatedWeight,
+		},
+	}
+
+	results := map[string]modelResult{}
+	modelFailed := func(modelName string, err error) {
+		result := results[modelName]
+		result.reason = jamiethompsonmev1alpha1.ModelReasonError
+		result.message = err.Error()
+		results[modelName] = result
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "FailedProcessModel", "failed to process model '%s': %v",
+			modelName, err)
+	}
+
+	accuracyWindow := defaultAccuracyWindow
+	if r.AccuracyWindow > 0 {
+		accuracyWindow = r.AccuracyWindow
+	}
 
 	// Add the calculated replicas to a list of past replicas
 	for _, model := range instance.Spec.Models {
@@ -411,7 +531,8 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) processModels(ctx context.
 
 		modelHistory, exists := phpaData.ModelHistories[model.Name]
 		if !exists || modelHistory.Type != model.Type {
-			// Create new if model doesn't exist or has a type mismatch
+			// Create new if model doesn't exist or has a type mismatch, its history is derived from the shared
+			// history below
 			modelHistory = jamiethompsonmev1alpha1.ModelHistory{
 				Type:              model.Type,
 				SyncPeriodsPassed: 1,
@@ -421,17 +542,28 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) processModels(ctx context.
 
 		if model.StartInterval != nil {
 			if modelHistory.StartTime == nil {
-				startTime := nextInterval(now, model.StartInterval.Duration)
+				// If there is shared history the model can use then calculate the start time from the start of it,
+				// allowing the model to start straight away using the history that has already been recorded
+				sharedHistory, _ := deriveHistory(phpaData, &model, nil, now)
+				startFrom := now
+				if len(sharedHistory) > 0 {
+					startFrom = sharedHistory[0].Time.Time
+				}
+
+				startTime := nextInterval(startFrom, model.StartInterval.Duration)
 				modelHistory.StartTime = &metav1.Time{Time: startTime}
 				phpaData.ModelHistories[model.Name] = modelHistory
 
-				logger.V(1).Info("Skipping model for this sync period, start interval with no start time calculated, new start time calculated",
-					"scaleTargetRef", scaleTargetRef,
-					"startInterval", model.StartInterval.Duration,
-					"startTime", modelHistory.StartTime,
-					"timeUntilStart", modelHistory.StartTime.Sub(now),
-					"model", model.Name)
-				continue
+				if len(sharedHistory) == 0 {
+					logger.V(1).Info("Skipping model for this sync period, start interval with no start time calculated, new start time calculated",
+						"scaleTargetRef", scaleTargetRef,
+						"startInterval", model.StartInterval.Duration,
+						"startTime", modelHistory.StartTime,
+						"timeUntilStart", modelHistory.StartTime.Sub(now),
+						"model", model.Name)
+					results[model.Name] = startIntervalResult(modelHistory.StartTime)
+					continue
+				}
 			}
 
 			if now.Before(modelHistory.StartTime.Time) {
@@ -441,6 +573,7 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) processModels(ctx context.
 					"startTime", modelHistory.StartTime,
 					"timeUntilStart", modelHistory.StartTime.Sub(now),
 					"model", model.Name)
+				results[model.Name] = startIntervalResult(modelHistory.StartTime)
 				continue
 			}
 		}
@@ -456,8 +589,10 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) processModels(ctx context.
 
 			durationSinceLastData := now.Sub(latest)
 			if durationSinceLastData > model.ResetDuration.Duration {
-				// Clear replica history
+				// Clear replica and metric history, along with any predictions made from that history
 				modelHistory.ReplicaHistory = []jamiethompsonmev1alpha1.TimestampedReplicas{}
+				modelHistory.MetricHistory = nil
+				modelHistory.PendingPredictions = nil
 
 				if model.StartInterval != nil {
 					// Recalculate start time
@@ -477,6 +612,7 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) processModels(ctx context.
 						"newStartTime", modelHistory.StartTime,
 						"timeUntilStart", modelHistory.StartTime.Sub(now),
 						"model", model.Name)
+					results[model.Name] = startIntervalResult(modelHistory.StartTime)
 					continue
 				}
 
@@ -492,6 +628,78 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) processModels(ctx context.
 
 		shouldRunOnThisSyncPeriod := modelHistory.SyncPeriodsPassed >= perSyncPeriod
 
+		input := jamiethompsonmev1alpha1.InputReplicas
+		if model.Input != nil {
+			input = *model.Input
+		}
+
+		// Derive the model's history from the shared history if the shared history holds more of it than the model
+		// has stored, for example if the model has been added, renamed, had its type changed or been reconfigured to
+		// store more history
+		derivedReplicaHistory, derivedMetricHistory := deriveHistory(phpaData, &model, modelHistory.StartTime, now)
+		if (input == jamiethompsonmev1alpha1.InputMetrics && len(derivedMetricHistory) > len(modelHistory.MetricHistory)) ||
+			(input != jamiethompsonmev1alpha1.InputMetrics && len(derivedReplicaHistory) > len(modelHistory.ReplicaHistory)) {
+			logger.V(1).Info("Deriving model history from the shared history",
+				"scaleTargetRef", scaleTargetRef,
+				"storedHistoryLength", len(modelHistory.ReplicaHistory),
+				"derivedHistoryLength", len(derivedReplicaHistory),
+				"model", model.Name)
+			modelHistory.ReplicaHistory = derivedReplicaHistory
+			modelHistory.MetricHistory = derivedMetricHistory
+		}
+
+		if input == jamiethompsonmev1alpha1.InputMetrics {
+			if metricValuesErr != nil {
+				// Skip this model, errored out
+				logger.Error(metricValuesErr, "failed to get raw metric values for model",
+					"scaleTargetRef", scaleTargetRef,
+					"model", model.Name)
+				modelFailed(model.Name, metricValuesErr)
+				continue
+			}
+
+			// If the metrics in the spec have changed the stored metric values no longer line up, start again
+			for _, timestampedMetrics := range modelHistory.MetricHistory {
+				if len(timestampedMetrics.Values) != len(metricValues) {
+					logger.V(1).Info("Clearing metric history, metrics in spec have changed",
+						"scaleTargetRef", scaleTargetRef,
+						"model", model.Name)
+					modelHistory.MetricHistory = nil
+					break
+				}
+			}
+
+			modelHistory.MetricHistory = append(modelHistory.MetricHistory, jamiethompsonmev1alpha1.TimestampedMetrics{
+				Time: &metav1.Time{
+					Time: now,
+				},
+				Values: metricValues,
+			})
+		} else {
+			modelHistory.MetricHistory = nil
+		}
+
+		// If the model has no history, for example it has just been created or has been reset, try to fill it from
+		// the history bootstrap. This is only attempted once, if it fails the model gathers history as normal
+		if len(modelHistory.ReplicaHistory) == 0 && model.HistoryBootstrap != nil && r.Bootstrapper != nil {
+			bootstrapHistory, err := r.Bootstrapper.GetReplicaHistory(model.HistoryBootstrap, now, syncPeriod)
+			if err != nil {
+				logger.Error(err, "failed to bootstrap model history",
+					"scaleTargetRef", scaleTargetRef,
+					"model", model.Name)
+				r.Recorder.Eventf(instance, corev1.EventTypeWarning, "FailedBootstrapModelHistory",
+					"failed to bootstrap the history of model '%s': %v", model.Name, err)
+			} else {
+				logger.V(0).Info("Bootstrapped model history",
+					"scaleTargetRef", scaleTargetRef,
+					"replicaCounts", len(bootstrapHistory),
+					"model", model.Name)
+				r.Recorder.Eventf(instance, corev1.EventTypeNormal, "BootstrappedModelHistory",
+					"bootstrapped the history of model '%s' with %d replica counts", model.Name, len(bootstrapHistory))
+				modelHistory.ReplicaHistory = bootstrapHistory
+			}
+		}
+
 		modelHistory.ReplicaHistory = append(modelHistory.ReplicaHistory, jamiethompsonmev1alpha1.TimestampedReplicas{
 			Time: &metav1.Time{
 				Time: now,
@@ -499,20 +707,155 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) processModels(ctx context.
 			Replicas: calculatedReplicas,
 		})
 
+		// Score any of the model's past predictions that are due by this sync period against the calculated replicas
+		accuracy.Score(&modelHistory, calculatedReplicas, now, syncPeriod, accuracyWindow)
+
+		excluded := accuracy.Exclude(model.Fallback, accuracy.Calculate(modelHistory.ScoredPredictions),
+			modelHistory.Excluded)
+		if excluded != modelHistory.Excluded {
+			logger.V(0).Info("Model fallback policy changed whether the model is excluded from the decision",
+				"scaleTargetRef", scaleTargetRef,
+				"excluded", excluded,
+				"model", model.Name)
+			if excluded {
+				r.Recorder.Eventf(instance, corev1.EventTypeWarning, "ModelExcluded",
+					"model '%s' excluded from the decision, its prediction error is above the fallback threshold",
+					model.Name)
+			} else {
+				r.Recorder.Eventf(instance, corev1.EventTypeNormal, "ModelIncluded",
+					"model '%s' included in the decision again, its prediction error has recovered", model.Name)
+			}
+		}
+		modelHistory.Excluded = excluded
+
+		historyLength := len(modelHistory.ReplicaHistory)
+		if input == jamiethompsonmev1alpha1.InputMetrics {
+			historyLength = len(modelHistory.MetricHistory)
+		}
+
+		// Models that need a minimum amount of history are not run until enough history has been stored
+		enoughHistory := true
 		if shouldRunOnThisSyncPeriod {
+			var err error
+			enoughHistory, err = r.hasEnoughHistory(&model, historyLength)
+			if err != nil {
+				// Skip this model, errored out
+				logger.Error(err, "failed to check if model has enough history",
+					"scaleTargetRef", scaleTargetRef,
+					"model", model.Name)
+				modelFailed(model.Name, err)
+				continue
+			}
+		}
+
+		if shouldRunOnThisSyncPeriod && enoughHistory {
 			logger.V(1).Info("Using model to calculate predicted target replicas",
 				"scaleTargetRef", scaleTargetRef,
 				"model", model.Name)
-			replicas, err := r.Predicter.GetPrediction(&model, modelHistory.ReplicaHistory)
+
+			// Time the whole run of the model, including any tuning, these durations are real time rather than the
+			// reconciler's clock
+			start := time.Now()
+
+			predictionModel := &model
+			if model.Type == jamiethompsonmev1alpha1.TypeHoltWinters && model.HoltWinters != nil &&
+				model.HoltWinters.AutoTune != nil {
+				tunedModel, err := r.tuneModel(&model, &modelHistory, now)
+				if err != nil {
+					recordModelRun(instance, &model, time.Since(start), err)
+					// Skip this model, errored out
+					logger.Error(err, "failed to tune model parameters",
+						"scaleTargetRef", scaleTargetRef,
+						"model", model.Name)
+					modelFailed(model.Name, err)
+					continue
+				}
+				predictionModel = tunedModel
+			}
+
+			// Metric input models fetch their runtime tuning values for each metric when they are forecast
+			if input != jamiethompsonmev1alpha1.InputMetrics {
+				fetchedModel, err := r.fetchTuning(instance, predictionModel, modelHistory.ReplicaHistory, nil)
+				if err != nil {
+					recordModelRun(instance, &model, time.Since(start), err)
+					// Skip this model, errored out
+					logger.Error(err, "failed to fetch model runtime tuning values",
+						"scaleTargetRef", scaleTargetRef,
+						"model", model.Name)
+					modelFailed(model.Name, err)
+					continue
+				}
+				predictionModel = fetchedModel
+			}
+
+			var replicas int32
+			var err error
+			if input == jamiethompsonmev1alpha1.InputMetrics {
+				replicas, err = r.predictFromMetrics(instance, predictionModel, modelHistory.MetricHistory,
+					gatheredMetrics, currentReplicas)
+			} else if instance.Spec.DecisionType != nil &&
+				*instance.Spec.DecisionType == jamiethompsonmev1alpha1.DecisionQuantile {
+				replicas, err = r.predictQuantile(instance, predictionModel, modelHistory.ReplicaHistory)
+			} else {
+				replicas, err = r.Predicter.GetPrediction(predictionModel, modelHistory.ReplicaHistory)
+			}
+			recordModelRun(instance, &model, time.Since(start), err)
 			if err != nil {
 				// Skip this model, errored out
 				logger.Error(err, "failed to get predicted replica count",
 					"scaleTargetRef", scaleTargetRef,
 					"currentReplicas", currentReplicas,
 					"targetReplicas", calculatedReplicas)
+				modelFailed(model.Name, err)
 				continue
 			}
-			predictedReplicas = append(predictedReplicas, replicas)
+			weight := float64(defaultWeight)
+			if model.Weight != nil {
+				weight = *model.Weight
+			}
+
+			modelHistory.PendingPredictions = append(modelHistory.PendingPredictions,
+				jamiethompsonmev1alpha1.TimestampedReplicas{
+					Time: &metav1.Time{
+						Time: now.Add(forecastHorizon(&model, syncPeriod)),
+					},
+					Replicas: replicas,
+				})
+
+			monitoring.RecordModelPrediction(instance.Namespace, instance.Name, model.Name, replicas)
+
+			modelHistory.LastPrediction = &jamiethompsonmev1alpha1.TimestampedReplicas{
+				Time:     &metav1.Time{Time: now},
+				Replicas: replicas,
+			}
+			results[model.Name] = modelResult{
+				ran: true,
+			}
+
+			// Excluded models keep predicting so their accuracy is still tracked, but their predictions are not used
+			if modelHistory.Excluded {
+				logger.V(1).Info("Excluding model prediction from decision, model is inaccurate",
+					"scaleTargetRef", scaleTargetRef,
+					"predictedReplicas", replicas,
+					"model", model.Name)
+			} else {
+				predictedReplicas = append(predictedReplicas, scalebehavior.PredictedReplicas{
+					Name:     model.Name,
+					Replicas: replicas,
+					Weight:   weight,
+				})
+			}
+			modelHistory.SyncPeriodsPassed = 1
+		} else if shouldRunOnThisSyncPeriod {
+			logger.V(1).Info("Skipping model for this sync period, not enough history to make a prediction",
+				"scaleTargetRef", scaleTargetRef,
+				"historyLength", historyLength,
+				"model", model.Name)
+			results[model.Name] = modelResult{
+				reason: jamiethompsonmev1alpha1.ModelReasonInsufficientHistory,
+				message: fmt.Sprintf("%d values stored in the model's history, not enough to make a prediction",
+					historyLength),
+			}
 			modelHistory.SyncPeriodsPassed = 1
 		} else {
 			logger.V(1).Info("Skipping model for this sync period, should not run on this sync period",
@@ -520,6 +863,11 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) processModels(ctx context.
 				"syncPeriodsPassed", modelHistory.SyncPeriodsPassed,
 				"perSyncPeriod", perSyncPeriod,
 				"model", model.Name)
+			results[model.Name] = modelResult{
+				reason: jamiethompsonmev1alpha1.ModelReasonPerSyncPeriod,
+				message: fmt.Sprintf("%d of %d sync periods passed since the model last ran",
+					modelHistory.SyncPeriodsPassed, perSyncPeriod),
+			}
 			modelHistory.SyncPeriodsPassed += 1
 		}
 
@@ -528,10 +876,12 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) processModels(ctx context.
 			// Skip this model, errored out
 			logger.Error(err, "failed to prune replica history",
 				"scaleTargetRef", scaleTargetRef)
+			modelFailed(model.Name, err)
 			continue
 		}
 
 		modelHistory.ReplicaHistory = prunedHistory
+		modelHistory.MetricHistory = pruneMetricHistory(modelHistory.MetricHistory, prunedHistory)
 		phpaData.ModelHistories[model.Name] = modelHistory
 	}
 
@@ -546,17 +896,300 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) processModels(ctx context.
 		}
 
 		if !exists {
+			// The model's history can still be derived from the shared history, for example if it has been renamed
 			delete(phpaData.ModelHistories, modelName)
+			monitoring.DeleteModel(instance.Namespace, instance.Name, modelName)
+		}
+	}
+
+	recordSharedHistory(instance, phpaData, now, calculatedReplicas, metricValues, metricValuesErr)
+
+	return predictedReplicas, results, phpaData
+}
+
+// recordSharedHistory adds the calculated replicas and the raw metric values to the shared history, which is recorded
+// independently of the models, and then prunes the shared history to the shared history size
+func recordSharedHistory(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler,
+	phpaData *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, now time.Time, calculatedReplicas int32,
+	metricValues []int64, metricValuesErr error) {
+
+	phpaData.ReplicaHistory = append(phpaData.ReplicaHistory, jamiethompsonmev1alpha1.TimestampedReplicas{
+		Time: &metav1.Time{
+			Time: now,
+		},
+		Replicas: calculatedReplicas,
+	})
+
+	// Metric values are only recorded if a model uses them
+	usesMetrics := false
+	for _, model := range instance.Spec.Models {
+		if model.Input != nil && *model.Input == jamiethompsonmev1alpha1.InputMetrics {
+			usesMetrics = true
+			break
+		}
+	}
+
+	if !usesMetrics {
+		phpaData.MetricHistory = nil
+	} else if metricValuesErr == nil {
+		phpaData.MetricHistory = append(phpaData.MetricHistory, jamiethompsonmev1alpha1.TimestampedMetrics{
+			Time: &metav1.Time{
+				Time: now,
+			},
+			Values: metricValues,
+		})
+	}
+
+	size := 0
+	if instance.Spec.SharedHistorySize != nil {
+		size = *instance.Spec.SharedHistorySize
+	} else {
+		for _, modelHistory := range phpaData.ModelHistories {
+			if len(modelHistory.ReplicaHistory) > size {
+				size = len(modelHistory.ReplicaHistory)
+			}
+		}
+	}
+
+	if len(phpaData.ReplicaHistory) > size {
+		phpaData.ReplicaHistory = phpaData.ReplicaHistory[len(phpaData.ReplicaHistory)-size:]
+	}
+
+	if len(phpaData.ReplicaHistory) == 0 {
+		phpaData.ReplicaHistory = nil
+		phpaData.MetricHistory = nil
+		return
+	}
+
+	// The shared metric values are recorded at the same times as the shared replica counts, so drop any that are
+	// older than the oldest replica count
+	oldest := phpaData.ReplicaHistory[0].Time
+	for len(phpaData.MetricHistory) > 0 && phpaData.MetricHistory[0].Time.Before(oldest) {
+		phpaData.MetricHistory = phpaData.MetricHistory[1:]
+	}
+	if len(phpaData.MetricHistory) == 0 {
+		phpaData.MetricHistory = nil
+	}
+}
+
+// deriveHistory returns the part of the shared history that can be used as the model's history, the most recent
+// replica counts and metric values recorded at or after the start time, stopping at any gap in the shared history that
+// is longer than the model's reset duration. The history returned is a copy that can be modified without changing the
+// shared history
+func deriveHistory(phpaData *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData,
+	model *jamiethompsonmev1alpha1.Model, startTime *metav1.Time,
+	now time.Time) ([]jamiethompsonmev1alpha1.TimestampedReplicas, []jamiethompsonmev1alpha1.TimestampedMetrics) {
+
+	start := len(phpaData.ReplicaHistory)
+	previous := now
+	for i := len(phpaData.ReplicaHistory) - 1; i >= 0; i-- {
+		recorded := phpaData.ReplicaHistory[i].Time
+		if recorded == nil || (startTime != nil && recorded.Before(startTime)) {
+			break
+		}
+		if model.ResetDuration != nil && previous.Sub(recorded.Time) > model.ResetDuration.Duration {
+			break
+		}
+		previous = recorded.Time
+		start = i
+	}
+
+	if start == len(phpaData.ReplicaHistory) {
+		return nil, nil
+	}
+
+	replicaHistory := append([]jamiethompsonmev1alpha1.TimestampedReplicas{}, phpaData.ReplicaHistory[start:]...)
+
+	var metricHistory []jamiethompsonmev1alpha1.TimestampedMetrics
+	oldest := replicaHistory[0].Time
+	for _, timestampedMetrics := range phpaData.MetricHistory {
+		if timestampedMetrics.Time != nil && !timestampedMetrics.Time.Before(oldest) {
+			metricHistory = append(metricHistory, timestampedMetrics)
+		}
+	}
+
+	return replicaHistory, metricHistory
+}
+
+// recordModelRun records the run of a model in the monitoring metrics, Remote models are recorded as a run of their
+// prediction hook and every other model type as a run of its algorithm
+func recordModelRun(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler,
+	model *jamiethompsonmev1alpha1.Model, duration time.Duration, err error) {
+	if model.Type == jamiethompsonmev1alpha1.TypeRemote && model.Remote != nil {
+		monitoring.RecordHookRun(instance.Namespace, instance.Name, model.Name, model.Remote.PredictionHook.Type,
+			duration, err)
+		return
+	}
+
+	monitoring.RecordAlgorithmRun(instance.Namespace, instance.Name, model.Name, model.Type, duration, err)
+}
+
+// hasEnoughHistory returns if the model has enough history stored to make a prediction, if the predicter does not
+// check history the model is assumed to have enough
+func (r *PredictiveHorizontalPodAutoscalerReconciler) hasEnoughHistory(model *jamiethompsonmev1alpha1.Model,
+	historyLength int) (bool, error) {
+	historyChecker, ok := r.Predicter.(prediction.HistoryChecker)
+	if !ok {
+		return true, nil
+	}
+
+	return historyChecker.HasEnoughHistory(model, historyLength)
+}
+
+// startIntervalResult returns the result of a model that was skipped because it is waiting for its start time
+func startIntervalResult(startTime *metav1.Time) modelResult {
+	return modelResult{
+		reason:  jamiethompsonmev1alpha1.ModelReasonStartInterval,
+		message: fmt.Sprintf("waiting for the start time %s", startTime.Format(time.RFC3339)),
+	}
+}
+
+// tuneModel returns a copy of the model with its smoothing parameters set to the values fit to the model's replica
+// history, fitting the parameters again if they have not been fit yet or if the refit interval has passed. The fitted
+// parameters are cached in the model history
+func (r *PredictiveHorizontalPodAutoscalerReconciler) tuneModel(model *jamiethompsonmev1alpha1.Model,
+	modelHistory *jamiethompsonmev1alpha1.ModelHistory, now time.Time) (*jamiethompsonmev1alpha1.Model, error) {
+	refitInterval := defaultRefitInterval
+	if model.HoltWinters.AutoTune.RefitInterval != nil {
+		refitInterval = model.HoltWinters.AutoTune.RefitInterval.Duration
+	}
+
+	tunedParameters := modelHistory.TunedParameters
+	if tunedParameters == nil || now.Sub(tunedParameters.Time.Time) >= refitInterval {
+		tuner, ok := r.Predicter.(prediction.Tuner)
+		if !ok {
+			return nil, errors.New("predicter does not support tuning")
+		}
+
+		fittedParameters, err := tuner.Tune(model, modelHistory.ReplicaHistory)
+		if err != nil {
+			return nil, err
+		}
+
+		// If there is not enough history to fit the parameters yet keep using any previously fitted parameters
+		if fittedParameters != nil {
+			fittedParameters.Time = &metav1.Time{Time: now}
+			modelHistory.TunedParameters = fittedParameters
+			tunedParameters = fittedParameters
+		}
+	}
+
+	if tunedParameters == nil {
+		return model, nil
+	}
+
+	tunedModel := model.DeepCopy()
+	tunedModel.HoltWinters.Alpha = &tunedParameters.Alpha
+	tunedModel.HoltWinters.Beta = &tunedParameters.Beta
+	tunedModel.HoltWinters.Gamma = &tunedParameters.Gamma
+
+	return tunedModel, nil
+}
+
+// fetchTuning returns a copy of the model with the tuning values fetched from its Holt-Winters runtime tuning fetch hook
+// set, recording the run of the hook in the monitoring metrics. Models without a runtime tuning fetch hook are returned
+// unchanged, as are models whose predicter does not support fetching tuning values separately, these predicters run
+// the hook as part of the prediction
+func (r *PredictiveHorizontalPodAutoscalerReconciler) fetchTuning(
+	instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, model *jamiethompsonmev1alpha1.Model,
+	replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas,
+	metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (*jamiethompsonmev1alpha1.Model, error) {
+	if model.Type != jamiethompsonmev1alpha1.TypeHoltWinters || model.HoltWinters == nil ||
+		model.HoltWinters.RuntimeTuningFetchHook == nil {
+		return model, nil
+	}
+
+	tuningFetcher, ok := r.Predicter.(prediction.TuningFetcher)
+	if !ok {
+		return model, nil
+	}
+
+	start := time.Now()
+	fetchedModel, err := tuningFetcher.FetchTuning(model, replicaHistory, metricHistory)
+	monitoring.RecordHookRun(instance.Namespace, instance.Name, model.Name,
+		model.HoltWinters.RuntimeTuningFetchHook.Type, time.Since(start), err)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchedModel, nil
+}
+
+// predictQuantile predicts the replica count at the PHPA's decision quantile, using the upper bound of the model's
+// prediction interval
+func (r *PredictiveHorizontalPodAutoscalerReconciler) predictQuantile(
+	instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, model *jamiethompsonmev1alpha1.Model,
+	replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
+	intervalPredicter, ok := r.Predicter.(prediction.IntervalPredicter)
+	if !ok {
+		return r.Predicter.GetPrediction(model, replicaHistory)
+	}
+
+	quantile := defaultDecisionQuantile
+	if instance.Spec.DecisionQuantile != nil {
+		quantile = *instance.Spec.DecisionQuantile
+	}
+
+	// The upper bound of a two sided interval with this confidence is the quantile
+	interval, err := intervalPredicter.GetPredictionInterval(model, replicaHistory, 2*quantile-1)
+	if err != nil {
+		return 0, err
+	}
+
+	return interval.Upper, nil
+}
+
+// predictFromMetrics forecasts each metric using the model, applies the forecast values to the gathered metrics and
+// then evaluates them to calculate the replica count the HPA would calculate if the forecast metric values were
+// gathered.
+func (r *PredictiveHorizontalPodAutoscalerReconciler) predictFromMetrics(
+	instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, model *jamiethompsonmev1alpha1.Model,
+	metricHistory []jamiethompsonmev1alpha1.TimestampedMetrics, gatheredMetrics []*metrics.Metric,
+	currentReplicas int32) (int32, error) {
+	metricPredicter, ok := r.Predicter.(prediction.MetricPredicter)
+	if !ok {
+		return 0, errors.New("predicter does not support metric input")
+	}
+
+	forecasts := make([]float64, len(gatheredMetrics))
+	for i := range gatheredMetrics {
+		history := make([]jamiethompsonmev1alpha1.TimestampedValue, len(metricHistory))
+		for j, timestampedMetrics := range metricHistory {
+			history[j] = jamiethompsonmev1alpha1.TimestampedValue{
+				Time:  timestampedMetrics.Time,
+				Value: float64(timestampedMetrics.Values[i]),
+			}
+		}
+
+		fetchedModel, err := r.fetchTuning(instance, model, nil, history)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch runtime tuning values for metric %d: %w", i, err)
 		}
+
+		forecast, err := metricPredicter.GetMetricPrediction(fetchedModel, history)
+		if err != nil {
+			return 0, fmt.Errorf("failed to forecast metric %d: %w", i, err)
+		}
+		forecasts[i] = forecast
 	}
 
-	return predictedReplicas, phpaData
+	forecastMetrics, err := metricinput.Scale(gatheredMetrics, forecasts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply forecast metric values: %w", err)
+	}
+
+	replicas, err := r.Evaluator.EvaluateWithOptions(forecastMetrics, currentReplicas, getTolerance(instance))
+	if err != nil {
+		return 0, fmt.Errorf("failed to evaluate forecast metrics and calculate target replica count: %w", err)
+	}
+
+	return replicas, nil
 }
 
 // calculateReplicas does the HPA processing part of the autoscaling based on the metrics provided in the spec,
-// returns the calculated value (the value the HPA would calculate based on these metrics).
+// returns the calculated value (the value the HPA would calculate based on these metrics) and the gathered metrics.
 func (r *PredictiveHorizontalPodAutoscalerReconciler) calculateReplicas(
-	instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, scale *autoscalingv1.Scale) (int32, error) {
+	instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, scale *autoscalingv1.Scale) (int32, []*metrics.Metric, error) {
 	cpuInitializationPeriod := defaultCPUInitializationPeriod
 	if instance.Spec.CPUInitializationPeriod != nil {
 		cpuInitializationPeriod = *instance.Spec.CPUInitializationPeriod
@@ -567,31 +1200,205 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) calculateReplicas(
 		initialReadinessDelay = *instance.Spec.InitialReadinessDelay
 	}
 
-	tolerance := defaultTolerance
-	if instance.Spec.Tolerance != nil {
-		tolerance = *instance.Spec.Tolerance
-	}
-
 	selector, err := labels.Parse(scale.Status.Selector)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse pod selector from scale subresource selector: %w", err)
+		return 0, nil, fmt.Errorf("failed to parse pod selector from scale subresource selector: %w", err)
 	}
 
 	// Gather K8s metrics using the spec
-	metrics, err := r.Gatherer.GatherWithOptions(instance.Spec.Metrics, scale.Namespace, selector,
+	gatheredMetrics, err := r.Gatherer.GatherWithOptions(instance.Spec.Metrics, scale.Namespace, selector,
 		time.Duration(cpuInitializationPeriod)*time.Second, time.Duration(initialReadinessDelay)*time.Second)
 	if err != nil {
-		return 0, fmt.Errorf("failed to gather metrics using provided metric specs: %w", err)
+		return 0, nil, fmt.Errorf("failed to gather metrics using provided metric specs: %w", err)
 	}
 
 	// Calculate the targetReplicas using these metrics
 	currentReplicas := scale.Spec.Replicas
-	calculatedReplicas, err := r.Evaluator.EvaluateWithOptions(metrics, currentReplicas, tolerance)
+	calculatedReplicas, err := r.Evaluator.EvaluateWithOptions(gatheredMetrics, currentReplicas, getTolerance(instance))
 	if err != nil {
-		return 0, fmt.Errorf("failed to evaluate metrics and calculate target replica count: %w", err)
+		return 0, nil, fmt.Errorf("failed to evaluate metrics and calculate target replica count: %w", err)
+	}
+
+	return calculatedReplicas, gatheredMetrics, nil
+}
+
+// forecastHorizon returns how far in the future a model's prediction is for, Linear regression models predict their
+// lookAhead into the future, Holt-Winters models predict their lookAhead steps (one step per sync period) and other
+// models predict the next sync period
+func forecastHorizon(model *jamiethompsonmev1alpha1.Model, syncPeriod time.Duration) time.Duration {
+	switch {
+	case model.Type == jamiethompsonmev1alpha1.TypeLinear && model.Linear != nil:
+		return time.Duration(model.Linear.LookAhead) * time.Millisecond
+	case model.Type == jamiethompsonmev1alpha1.TypeHoltWinters && model.HoltWinters != nil &&
+		model.HoltWinters.LookAhead != nil:
+		return time.Duration(model.HoltWinters.LookAhead.Steps) * syncPeriod
+	default:
+		return syncPeriod
+	}
+}
+
+// modelStatuses returns the status of every model in the spec, calculating each model's accuracy from its history
+// and including the result of processing the model in this sync period
+func modelStatuses(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler,
+	phpaData *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData,
+	results map[string]modelResult) []jamiethompsonmev1alpha1.ModelStatus {
+	var statuses []jamiethompsonmev1alpha1.ModelStatus
+	for _, model := range instance.Spec.Models {
+		result := results[model.Name]
+		status := jamiethompsonmev1alpha1.ModelStatus{
+			Name:    model.Name,
+			Ran:     result.ran,
+			Reason:  result.reason,
+			Message: result.message,
+		}
+
+		modelHistory, exists := phpaData.ModelHistories[model.Name]
+		if exists {
+			status.Accuracy = accuracy.Calculate(modelHistory.ScoredPredictions)
+			status.Excluded = modelHistory.Excluded
+			status.TunedParameters = modelHistory.TunedParameters
+			status.HistoryLength = int32(len(modelHistory.ReplicaHistory))
+			if modelHistory.LastPrediction != nil {
+				status.LastPredictedReplicas = &modelHistory.LastPrediction.Replicas
+				status.LastRunTime = modelHistory.LastPrediction.Time
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// setModelsHealthyCondition sets the models healthy condition, a PHPA's models are unhealthy if any of them failed to
+// be processed or are excluded from the decision by their fallback policy
+func setModelsHealthyCondition(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler,
+	phpaData *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, results map[string]modelResult) {
+	var failedModels []string
+	for _, model := range instance.Spec.Models {
+		if results[model.Name].reason == jamiethompsonmev1alpha1.ModelReasonError {
+			failedModels = append(failedModels, model.Name)
+		}
+	}
+
+	if len(failedModels) > 0 {
+		setCondition(instance, jamiethompsonmev1alpha1.ConditionModelsHealthy, metav1.ConditionFalse,
+			"FailedProcessModel", "failed to process models: %s", strings.Join(failedModels, ", "))
+		return
+	}
+
+	var excludedModels []string
+	for _, model := range instance.Spec.Models {
+		if phpaData.ModelHistories[model.Name].Excluded {
+			excludedModels = append(excludedModels, model.Name)
+		}
+	}
+
+	if len(excludedModels) > 0 {
+		setCondition(instance, jamiethompsonmev1alpha1.ConditionModelsHealthy, metav1.ConditionFalse, "ModelsExcluded",
+			"models excluded from the decision for inaccurate predictions: %s", strings.Join(excludedModels, ", "))
+		return
+	}
+
+	setCondition(instance, jamiethompsonmev1alpha1.ConditionModelsHealthy, metav1.ConditionTrue, "ModelsSucceeded",
+		"every model was processed successfully")
+}
+
+// setScalingLimitedCondition sets the scaling limited condition by comparing the replica count decided from the
+// predictions with the target replica count after the min and max replicas and scaling behavior are applied
+func setScalingLimitedCondition(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler,
+	desiredReplicas int32, targetReplicas int32, minReplicas int32, maxReplicas int32) {
+	switch {
+	case desiredReplicas > maxReplicas && targetReplicas == maxReplicas:
+		setCondition(instance, jamiethompsonmev1alpha1.ConditionScalingLimited, metav1.ConditionTrue,
+			"TooManyReplicas", "the desired replica count is more than the maximum replica count")
+	case desiredReplicas < minReplicas && targetReplicas == minReplicas:
+		setCondition(instance, jamiethompsonmev1alpha1.ConditionScalingLimited, metav1.ConditionTrue,
+			"TooFewReplicas", "the desired replica count is less than the minimum replica count")
+	case targetReplicas < desiredReplicas:
+		setCondition(instance, jamiethompsonmev1alpha1.ConditionScalingLimited, metav1.ConditionTrue, "ScaleUpLimit",
+			"the desired replica count is limited by the scale up behavior")
+	case targetReplicas > desiredReplicas:
+		setCondition(instance, jamiethompsonmev1alpha1.ConditionScalingLimited, metav1.ConditionTrue, "ScaleDownLimit",
+			"the desired replica count is limited by the scale down behavior")
+	default:
+		setCondition(instance, jamiethompsonmev1alpha1.ConditionScalingLimited, metav1.ConditionFalse,
+			"DesiredWithinRange", "the desired count is within the acceptable range")
+	}
+}
+
+// recordFailure records a warning event and sets the condition provided to false with the reason and message, then
+// updates the PHPA status so the failure is visible on the resource
+func (r *PredictiveHorizontalPodAutoscalerReconciler) recordFailure(ctx context.Context,
+	instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, conditionType string, reason string,
+	message string, args ...interface{}) {
+	r.Recorder.Eventf(instance, corev1.EventTypeWarning, reason, message, args...)
+	setCondition(instance, conditionType, metav1.ConditionFalse, reason, message, args...)
+
+	err := r.Client.Status().Update(ctx, instance)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to update status conditions of resource",
+			"conditionType", conditionType,
+			"reason", reason)
+	}
+}
+
+// setCondition sets the condition of the type provided on the PHPA status, the last transition time is only updated
+// if the status of the condition changes
+func setCondition(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, conditionType string,
+	status metav1.ConditionStatus, reason string, message string, args ...interface{}) {
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: instance.Generation,
+		Reason:             reason,
+		Message:            fmt.Sprintf(message, args...),
+	})
+}
+
+// now returns the current time from the reconciler's clock, falling back to the system clock if no clock is set
+func (r *PredictiveHorizontalPodAutoscalerReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now().UTC()
+	}
+	return r.Clock.Now().UTC()
+}
+
+// SyncPeriod returns the PHPA's sync period, the interval between each calculation
+func SyncPeriod(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler) time.Duration {
+	syncPeriod := defaultSyncPeriod
+	if instance.Spec.SyncPeriod != nil {
+		syncPeriod = time.Duration(*instance.Spec.SyncPeriod) * time.Millisecond
+	}
+	return syncPeriod
+}
+
+func getTolerance(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler) float64 {
+	tolerance := defaultTolerance
+	if instance.Spec.Tolerance != nil {
+		tolerance = *instance.Spec.Tolerance
+	}
+	return tolerance
+}
+
+// pruneMetricHistory removes any metric values that no longer have a corresponding entry in the replica history, so
+// the metric history is pruned in the same way as the model pruned the replica history
+func pruneMetricHistory(metricHistory []jamiethompsonmev1alpha1.TimestampedMetrics,
+	replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) []jamiethompsonmev1alpha1.TimestampedMetrics {
+	if len(metricHistory) == 0 {
+		return nil
+	}
+
+	pruned := []jamiethompsonmev1alpha1.TimestampedMetrics{}
+	for _, timestampedMetrics := range metricHistory {
+		for _, timestampedReplicas := range replicaHistory {
+			if timestampedMetrics.Time.Equal(timestampedReplicas.Time) {
+				pruned = append(pruned, timestampedMetrics)
+				break
+			}
+		}
 	}
 
-	return calculatedReplicas, nil
+	return pruned
 }
 
 // preScaleStatusCheck makes sure that the PHPAs status fields are correct before scaling, e.g. the reference field
@@ -707,5 +1514,6 @@ func (r *PredictiveHorizontalPodAutoscalerReconciler) SetupWithManager(mgr ctrl.
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&jamiethompsonmev1alpha1.PHPAModelState{}).
 		Complete(r)
 }
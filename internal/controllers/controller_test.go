@@ -0,0 +1,894 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/k8shorizmetrics/v2"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics"
+	externalmetrics "github.com/jthomperoo/k8shorizmetrics/v2/metrics/external"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics/value"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	scalefake "k8s.io/client-go/scale/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	testingclock "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/controllers"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history/configmap"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func TestPredictiveHorizontalPodAutoscalerReconciler_Reconcile(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	now := time.Date(2023, time.July, 1, 12, 0, 0, 0, time.UTC)
+
+	metricSpecs := []autoscalingv2.MetricSpec{
+		{
+			Type: autoscalingv2.ExternalMetricSourceType,
+			External: &autoscalingv2.ExternalMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{
+					Name: "queue_length",
+				},
+				Target: autoscalingv2.MetricTarget{
+					Type: autoscalingv2.ValueMetricType,
+				},
+			},
+		},
+	}
+
+	linear := func(name string) jamiethompsonmev1alpha1.Model {
+		return jamiethompsonmev1alpha1.Model{
+			Type: jamiethompsonmev1alpha1.TypeLinear,
+			Name: name,
+			Linear: &jamiethompsonmev1alpha1.Linear{
+				HistorySize: 5,
+			},
+		}
+	}
+	predictions := map[string]int32{
+		"linear":   7,
+		"excluded": 9,
+	}
+
+	var tests = []struct {
+		description          string
+		expectedErr          error
+		expectedEvents       []string
+		expectedStatus       jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerStatus
+		expectedScaleUpdates []int32
+		spec                 jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec
+		currentReplicas      int32
+		calculatedReplicas   int32
+		getScaleErr          error
+		updateScaleErr       error
+		phpaData             *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+	}{
+		{
+			description: "Invalid spec, PHPA disabled",
+			expectedErr: nil,
+			expectedEvents: []string{
+				"Warning InvalidSpec the PHPA spec is invalid, the PHPA is disabled until it is changed to be valid: " +
+					"spec.maxReplicas (1) cannot be less than spec.minReplicas (2)",
+			},
+			expectedStatus: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:   jamiethompsonmev1alpha1.ConditionValid,
+						Status: metav1.ConditionFalse,
+						Reason: "InvalidSpec",
+						Message: "the PHPA spec is invalid, the PHPA is disabled until it is changed to be valid: " +
+							"spec.maxReplicas (1) cannot be less than spec.minReplicas (2)",
+					},
+				},
+			},
+			expectedScaleUpdates: nil,
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MinReplicas: int32Ptr(2),
+				MaxReplicas: 1,
+				Metrics:     metricSpecs,
+			},
+			currentReplicas:    2,
+			calculatedReplicas: 5,
+		},
+		{
+			description: "Fail to get target scale",
+			expectedErr: errors.New("fail to get scale"),
+			expectedEvents: []string{
+				"Warning FailedGetScale the PHPA controller was unable to get the target's current scale: " +
+					"fail to get scale",
+			},
+			expectedStatus: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerStatus{
+				Reference: "Deployment/test",
+				Conditions: []metav1.Condition{
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionValid,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ValidSpec",
+						Message: "the PHPA spec is valid",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionAbleToScale,
+						Status:  metav1.ConditionFalse,
+						Reason:  "FailedGetScale",
+						Message: "the PHPA controller was unable to get the target's current scale: fail to get scale",
+					},
+				},
+			},
+			expectedScaleUpdates: nil,
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				Metrics:     metricSpecs,
+			},
+			currentReplicas:    2,
+			calculatedReplicas: 5,
+			getScaleErr:        errors.New("fail to get scale"),
+		},
+		{
+			description: "Fail to update target scale",
+			expectedErr: errors.New("fail to update scale"),
+			expectedEvents: []string{
+				"Warning FailedUpdateScale the PHPA controller was unable to update the target scale: " +
+					"fail to update scale",
+			},
+			expectedStatus: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerStatus{
+				Reference: "Deployment/test",
+				Conditions: []metav1.Condition{
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionValid,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ValidSpec",
+						Message: "the PHPA spec is valid",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionScalingActive,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ValidMetricFound",
+						Message: "the PHPA was able to successfully calculate a replica count from metrics",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionModelsHealthy,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ModelsSucceeded",
+						Message: "every model was processed successfully",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionScalingLimited,
+						Status:  metav1.ConditionFalse,
+						Reason:  "DesiredWithinRange",
+						Message: "the desired count is within the acceptable range",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionAbleToScale,
+						Status:  metav1.ConditionFalse,
+						Reason:  "FailedUpdateScale",
+						Message: "the PHPA controller was unable to update the target scale: fail to update scale",
+					},
+				},
+			},
+			expectedScaleUpdates: []int32{5},
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				Metrics:     metricSpecs,
+			},
+			currentReplicas:    2,
+			calculatedReplicas: 5,
+			updateScaleErr:     errors.New("fail to update scale"),
+		},
+		{
+			description: "Success, target scale updated",
+			expectedErr: nil,
+			expectedEvents: []string{
+				"Normal SuccessfulRescale New size: 5; previous size: 2",
+			},
+			expectedStatus: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerStatus{
+				LastScaleTime:      &metav1.Time{Time: now},
+				Reference:          "Deployment/test",
+				CurrentReplicas:    5,
+				DesiredReplicas:    5,
+				CalculatedReplicas: 5,
+				DecidedReplicas:    5,
+				Conditions: []metav1.Condition{
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionValid,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ValidSpec",
+						Message: "the PHPA spec is valid",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionScalingActive,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ValidMetricFound",
+						Message: "the PHPA was able to successfully calculate a replica count from metrics",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionModelsHealthy,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ModelsSucceeded",
+						Message: "every model was processed successfully",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionScalingLimited,
+						Status:  metav1.ConditionFalse,
+						Reason:  "DesiredWithinRange",
+						Message: "the desired count is within the acceptable range",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionAbleToScale,
+						Status:  metav1.ConditionTrue,
+						Reason:  "SucceededRescale",
+						Message: "the PHPA controller was able to update the target scale to 5",
+					},
+				},
+			},
+			expectedScaleUpdates: []int32{5},
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				Metrics:     metricSpecs,
+			},
+			currentReplicas:    2,
+			calculatedReplicas: 5,
+		},
+		{
+			description: "Success, decision and model statuses recorded",
+			expectedErr: nil,
+			expectedEvents: []string{
+				"Warning FailedProcessModel failed to process model 'errored': fail to predict",
+				"Normal SuccessfulRescale New size: 6; previous size: 2",
+			},
+			expectedStatus: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerStatus{
+				LastScaleTime:      &metav1.Time{Time: now},
+				Reference:          "Deployment/test",
+				CurrentReplicas:    6,
+				DesiredReplicas:    6,
+				CalculatedReplicas: 5,
+				DecidedReplicas:    7,
+				Conditions: []metav1.Condition{
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionValid,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ValidSpec",
+						Message: "the PHPA spec is valid",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionScalingActive,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ValidMetricFound",
+						Message: "the PHPA was able to successfully calculate a replica count from metrics",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionModelsHealthy,
+						Status:  metav1.ConditionFalse,
+						Reason:  "FailedProcessModel",
+						Message: "failed to process models: errored",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionScalingLimited,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ScaleUpLimit",
+						Message: "the desired replica count is limited by the scale up behavior",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionAbleToScale,
+						Status:  metav1.ConditionTrue,
+						Reason:  "SucceededRescale",
+						Message: "the PHPA controller was able to update the target scale to 6",
+					},
+				},
+				Models: []jamiethompsonmev1alpha1.ModelStatus{
+					{
+						Name:                  "linear",
+						Ran:                   true,
+						LastPredictedReplicas: int32Ptr(7),
+						LastRunTime:           &metav1.Time{Time: now},
+						HistoryLength:         1,
+					},
+					{
+						Name:                  "excluded",
+						Excluded:              true,
+						Ran:                   true,
+						LastPredictedReplicas: int32Ptr(9),
+						LastRunTime:           &metav1.Time{Time: now},
+						HistoryLength:         2,
+					},
+					{
+						Name:    "errored",
+						Ran:     false,
+						Reason:  jamiethompsonmev1alpha1.ModelReasonError,
+						Message: "fail to predict",
+					},
+					{
+						Name:          "skipped",
+						Ran:           false,
+						Reason:        jamiethompsonmev1alpha1.ModelReasonPerSyncPeriod,
+						Message:       "1 of 2 sync periods passed since the model last ran",
+						HistoryLength: 1,
+					},
+				},
+			},
+			expectedScaleUpdates: []int32{6},
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				Metrics:     metricSpecs,
+				Models: []jamiethompsonmev1alpha1.Model{
+					linear("linear"),
+					func() jamiethompsonmev1alpha1.Model {
+						model := linear("excluded")
+						model.Fallback = &jamiethompsonmev1alpha1.ModelFallback{
+							Threshold: 50,
+						}
+						return model
+					}(),
+					linear("errored"),
+					func() jamiethompsonmev1alpha1.Model {
+						model := linear("skipped")
+						model.PerSyncPeriod = intPtr(2)
+						return model
+					}(),
+				},
+			},
+			phpaData: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"excluded": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: &metav1.Time{Time: now.Add(-15 * time.Second)}, Replicas: 3},
+						},
+						Excluded: true,
+					},
+				},
+			},
+			currentReplicas:    2,
+			calculatedReplicas: 5,
+		},
+		{
+			description: "Dry run, target scale not updated",
+			expectedErr: nil,
+			expectedEvents: []string{
+				"Normal DryRunRescale Dry run, would rescale to new size: 5; current size: 2",
+			},
+			expectedStatus: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerStatus{
+				LastScaleTime:      &metav1.Time{Time: now},
+				Reference:          "Deployment/test",
+				CurrentReplicas:    2,
+				DesiredReplicas:    5,
+				CalculatedReplicas: 5,
+				DecidedReplicas:    5,
+				Conditions: []metav1.Condition{
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionValid,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ValidSpec",
+						Message: "the PHPA spec is valid",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionScalingActive,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ValidMetricFound",
+						Message: "the PHPA was able to successfully calculate a replica count from metrics",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionModelsHealthy,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ModelsSucceeded",
+						Message: "every model was processed successfully",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionScalingLimited,
+						Status:  metav1.ConditionFalse,
+						Reason:  "DesiredWithinRange",
+						Message: "the desired count is within the acceptable range",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionAbleToScale,
+						Status:  metav1.ConditionTrue,
+						Reason:  "DryRun",
+						Message: "the PHPA is in dry run mode, the target scale was not updated to 5",
+					},
+				},
+			},
+			expectedScaleUpdates: nil,
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				Metrics:     metricSpecs,
+				Mode:        strPtr(jamiethompsonmev1alpha1.ModeDryRun),
+			},
+			currentReplicas:    2,
+			calculatedReplicas: 5,
+		},
+		{
+			description:    "Dry run, decided replica count matches the current replica count",
+			expectedErr:    nil,
+			expectedEvents: nil,
+			expectedStatus: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerStatus{
+				LastScaleTime:      &metav1.Time{Time: now},
+				Reference:          "Deployment/test",
+				CurrentReplicas:    2,
+				DesiredReplicas:    2,
+				CalculatedReplicas: 2,
+				DecidedReplicas:    2,
+				Conditions: []metav1.Condition{
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionValid,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ValidSpec",
+						Message: "the PHPA spec is valid",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionScalingActive,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ValidMetricFound",
+						Message: "the PHPA was able to successfully calculate a replica count from metrics",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionModelsHealthy,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ModelsSucceeded",
+						Message: "every model was processed successfully",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionScalingLimited,
+						Status:  metav1.ConditionFalse,
+						Reason:  "DesiredWithinRange",
+						Message: "the desired count is within the acceptable range",
+					},
+					{
+						Type:    jamiethompsonmev1alpha1.ConditionAbleToScale,
+						Status:  metav1.ConditionTrue,
+						Reason:  "ReadyForNewScale",
+						Message: "recommended size matches current size",
+					},
+				},
+			},
+			expectedScaleUpdates: nil,
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				Metrics:     metricSpecs,
+				Mode:        strPtr(jamiethompsonmev1alpha1.ModeDryRun),
+			},
+			currentReplicas:    2,
+			calculatedReplicas: 2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			_ = jamiethompsonmev1alpha1.AddToScheme(scheme)
+
+			spec := test.spec
+			spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "test",
+			}
+
+			fakeClient := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "default",
+					},
+					Spec: spec,
+				},
+			).Build()
+
+			historyStore := &configmap.Store{
+				Client: fakeClient,
+			}
+			if test.phpaData != nil {
+				err := historyStore.Save(context.Background(), &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "default",
+					},
+				}, test.phpaData, "")
+				if err != nil {
+					t.Fatalf("failed to store model history: %v", err)
+				}
+			}
+
+			var scaleUpdates []int32
+			scaleClient := &scalefake.FakeScaleClient{}
+			scaleClient.AddReactor("get", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if test.getScaleErr != nil {
+					return true, nil, test.getScaleErr
+				}
+				return true, &autoscalingv1.Scale{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "default",
+					},
+					Spec: autoscalingv1.ScaleSpec{
+						Replicas: test.currentReplicas,
+					},
+					Status: autoscalingv1.ScaleStatus{
+						Replicas: test.currentReplicas,
+						Selector: "app=test",
+					},
+				}, nil
+			})
+			scaleClient.AddReactor("update", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				scale := action.(k8stesting.UpdateAction).GetObject().(*autoscalingv1.Scale)
+				scaleUpdates = append(scaleUpdates, scale.Spec.Replicas)
+				if test.updateScaleErr != nil {
+					return true, nil, test.updateScaleErr
+				}
+				return true, scale, nil
+			})
+
+			recorder := record.NewFakeRecorder(10)
+
+			reconciler := &controllers.PredictiveHorizontalPodAutoscalerReconciler{
+				Client:      fakeClient,
+				ScaleClient: scaleClient,
+				Scheme:      scheme,
+				Gatherer: k8shorizmetrics.Gatherer{
+					External: &fake.ExternalGatherer{
+						GatherReactor: func(metricName, namespace string, metricSelector *metav1.LabelSelector, podSelector labels.Selector) (*externalmetrics.Metric, error) {
+							current := int64(10)
+							return &externalmetrics.Metric{
+								Current: value.MetricValue{
+									Value: &current,
+								},
+							}, nil
+						},
+					},
+				},
+				Evaluator: k8shorizmetrics.Evaluator{
+					External: &fake.ExternalEvaluater{
+						EvaluateReactor: func(currentReplicas int32, gatheredMetric *metrics.Metric, tolerance float64) (int32, error) {
+							return test.calculatedReplicas, nil
+						},
+					},
+				},
+				Predicter: &fake.Predicter{
+					GetPredictionReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
+						replicas, exists := predictions[model.Name]
+						if !exists {
+							return 0, errors.New("fail to predict")
+						}
+						return replicas, nil
+					},
+					PruneHistoryReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+						return replicaHistory, nil
+					},
+				},
+				Recorder:     recorder,
+				HistoryStore: historyStore,
+				Clock:        testingclock.NewFakePassiveClock(now),
+			}
+
+			_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "test",
+					Namespace: "default",
+				},
+			})
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			if !cmp.Equal(test.expectedEvents, events) {
+				t.Errorf("events mismatch (-want +got):\n%s", cmp.Diff(test.expectedEvents, events))
+			}
+
+			if !cmp.Equal(test.expectedScaleUpdates, scaleUpdates) {
+				t.Errorf("scale updates mismatch (-want +got):\n%s", cmp.Diff(test.expectedScaleUpdates, scaleUpdates))
+			}
+
+			result := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{}
+			err = fakeClient.Get(context.Background(), types.NamespacedName{Name: "test", Namespace: "default"}, result)
+			if err != nil {
+				t.Errorf("failed to get PHPA: %v", err)
+				return
+			}
+
+			// Only compare the parts of the status set by the sync, the scaling histories are covered by the scaling
+			// behavior tests
+			status := jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerStatus{
+				LastScaleTime:      result.Status.LastScaleTime,
+				Reference:          result.Status.Reference,
+				CurrentReplicas:    result.Status.CurrentReplicas,
+				DesiredReplicas:    result.Status.DesiredReplicas,
+				CalculatedReplicas: result.Status.CalculatedReplicas,
+				DecidedReplicas:    result.Status.DecidedReplicas,
+				Conditions:         result.Status.Conditions,
+				Models:             result.Status.Models,
+			}
+			ignoreTransitionTime := cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")
+			if !cmp.Equal(test.expectedStatus, status, ignoreTransitionTime) {
+				t.Errorf("status mismatch (-want +got):\n%s", cmp.Diff(test.expectedStatus, status,
+					ignoreTransitionTime))
+			}
+		})
+	}
+}
+
+func TestPredictiveHorizontalPodAutoscalerReconciler_Reconcile_RuntimeTuningFetchHook(t *testing.T) {
+	now := time.Date(2023, time.July, 1, 12, 0, 0, 0, time.UTC)
+
+	// hookRuns returns the number of recorded runs and failures of the runtime tuning fetch hook of the model
+	hookRuns := func(t *testing.T, model string) (uint64, float64) {
+		families, err := crmetrics.Registry.Gather()
+		if err != nil {
+			t.Fatalf("failed to gather metrics: %v", err)
+		}
+
+		var runs uint64
+		var failures float64
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				labels := map[string]string{}
+				for _, pair := range metric.GetLabel() {
+					labels[pair.GetName()] = pair.GetValue()
+				}
+				if labels["namespace"] != "default" || labels["name"] != "test" || labels["model"] != model ||
+					labels["hook_type"] != jamiethompsonmev1alpha1.HookTypeHTTP {
+					continue
+				}
+
+				switch family.GetName() {
+				case "predictive_horizontal_pod_autoscaler_hook_duration_seconds":
+					runs = metric.GetHistogram().GetSampleCount()
+				case "predictive_horizontal_pod_autoscaler_hook_failures_total":
+					failures = metric.GetCounter().GetValue()
+				}
+			}
+		}
+		return runs, failures
+	}
+
+	var tests = []struct {
+		description      string
+		expectedModels   []jamiethompsonmev1alpha1.ModelStatus
+		expectedRuns     uint64
+		expectedFailures float64
+		model            string
+		fetchErr         error
+	}{
+		{
+			description: "Fail to fetch runtime tuning values, hook run and failure recorded",
+			expectedModels: []jamiethompsonmev1alpha1.ModelStatus{
+				{
+					Name:          "fetch-fail",
+					Ran:           false,
+					Reason:        jamiethompsonmev1alpha1.ModelReasonError,
+					Message:       "fail to fetch tuning",
+					HistoryLength: 0,
+				},
+			},
+			expectedRuns:     1,
+			expectedFailures: 1,
+			model:            "fetch-fail",
+			fetchErr:         errors.New("fail to fetch tuning"),
+		},
+		{
+			description: "Success, hook run recorded and prediction made with the fetched model",
+			expectedModels: []jamiethompsonmev1alpha1.ModelStatus{
+				{
+					Name:                  "fetch-success",
+					Ran:                   true,
+					LastPredictedReplicas: int32Ptr(6),
+					LastRunTime:           &metav1.Time{Time: now},
+					HistoryLength:         1,
+				},
+			},
+			expectedRuns:     1,
+			expectedFailures: 0,
+			model:            "fetch-success",
+			fetchErr:         nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			_ = jamiethompsonmev1alpha1.AddToScheme(scheme)
+
+			fakeClient := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "default",
+					},
+					Spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+						ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+							APIVersion: "apps/v1",
+							Kind:       "Deployment",
+							Name:       "test",
+						},
+						MinReplicas: int32Ptr(1),
+						MaxReplicas: 10,
+						Metrics: []autoscalingv2.MetricSpec{
+							{
+								Type: autoscalingv2.ExternalMetricSourceType,
+								External: &autoscalingv2.ExternalMetricSource{
+									Metric: autoscalingv2.MetricIdentifier{
+										Name: "queue_length",
+									},
+									Target: autoscalingv2.MetricTarget{
+										Type: autoscalingv2.ValueMetricType,
+									},
+								},
+							},
+						},
+						Models: []jamiethompsonmev1alpha1.Model{
+							{
+								Type: jamiethompsonmev1alpha1.TypeHoltWinters,
+								Name: test.model,
+								HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+									Trend:           "add",
+									Seasonal:        "add",
+									SeasonalPeriods: 2,
+									RuntimeTuningFetchHook: &jamiethompsonmev1alpha1.HookDefinition{
+										Type:    jamiethompsonmev1alpha1.HookTypeHTTP,
+										Timeout: 2500,
+										HTTP: &jamiethompsonmev1alpha1.HTTPHook{
+											Method:        "GET",
+											URL:           "http://tuning",
+											SuccessCodes:  []int{200},
+											ParameterMode: "query",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			).Build()
+
+			scaleClient := &scalefake.FakeScaleClient{}
+			scaleClient.AddReactor("get", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, &autoscalingv1.Scale{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "default",
+					},
+					Spec: autoscalingv1.ScaleSpec{
+						Replicas: 5,
+					},
+					Status: autoscalingv1.ScaleStatus{
+						Replicas: 5,
+						Selector: "app=test",
+					},
+				}, nil
+			})
+			scaleClient.AddReactor("update", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, action.(k8stesting.UpdateAction).GetObject(), nil
+			})
+
+			reconciler := &controllers.PredictiveHorizontalPodAutoscalerReconciler{
+				Client:      fakeClient,
+				ScaleClient: scaleClient,
+				Scheme:      scheme,
+				Gatherer: k8shorizmetrics.Gatherer{
+					External: &fake.ExternalGatherer{
+						GatherReactor: func(metricName, namespace string, metricSelector *metav1.LabelSelector, podSelector labels.Selector) (*externalmetrics.Metric, error) {
+							current := int64(10)
+							return &externalmetrics.Metric{
+								Current: value.MetricValue{
+									Value: &current,
+								},
+							}, nil
+						},
+					},
+				},
+				Evaluator: k8shorizmetrics.Evaluator{
+					External: &fake.ExternalEvaluater{
+						EvaluateReactor: func(currentReplicas int32, gatheredMetric *metrics.Metric, tolerance float64) (int32, error) {
+							return 5, nil
+						},
+					},
+				},
+				Predicter: &fake.TuningFetcher{
+					Predicter: fake.Predicter{
+						GetPredictionReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
+							if model.HoltWinters.RuntimeTuningFetchHook != nil || model.HoltWinters.Alpha == nil {
+								return 0, errors.New("prediction not made with the fetched model")
+							}
+							return 6, nil
+						},
+						PruneHistoryReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+							return replicaHistory, nil
+						},
+					},
+					FetchTuningReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (*jamiethompsonmev1alpha1.Model, error) {
+						if test.fetchErr != nil {
+							return nil, test.fetchErr
+						}
+						fetchedModel := model.DeepCopy()
+						fetchedModel.HoltWinters.RuntimeTuningFetchHook = nil
+						fetchedModel.HoltWinters.Alpha = float64Ptr(0.5)
+						return fetchedModel, nil
+					},
+				},
+				Recorder: record.NewFakeRecorder(10),
+				HistoryStore: &configmap.Store{
+					Client: fakeClient,
+				},
+				Clock: testingclock.NewFakePassiveClock(now),
+			}
+
+			_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "test",
+					Namespace: "default",
+				},
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			runs, failures := hookRuns(t, test.model)
+			if !cmp.Equal(test.expectedRuns, runs) {
+				t.Errorf("hook runs mismatch (-want +got):\n%s", cmp.Diff(test.expectedRuns, runs))
+			}
+			if !cmp.Equal(test.expectedFailures, failures) {
+				t.Errorf("hook failures mismatch (-want +got):\n%s", cmp.Diff(test.expectedFailures, failures))
+			}
+
+			result := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{}
+			err = fakeClient.Get(context.Background(), types.NamespacedName{Name: "test", Namespace: "default"}, result)
+			if err != nil {
+				t.Errorf("failed to get PHPA: %v", err)
+				return
+			}
+			if !cmp.Equal(test.expectedModels, result.Status.Models) {
+				t.Errorf("models mismatch (-want +got):\n%s", cmp.Diff(test.expectedModels, result.Status.Models))
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics"
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+)
+
+// SimulatedObservation is what is observed in a simulated sync period, either the replica count calculated from the
+// metrics, or the gathered metrics to evaluate to calculate the replica count
+type SimulatedObservation struct {
+	CalculatedReplicas *int32
+	GatheredMetrics    []*metrics.Metric
+}
+
+// SimulatedSync is the result of a simulated sync period
+type SimulatedSync struct {
+	CalculatedReplicas int32
+	DecidedReplicas    int32
+	TargetReplicas     int32
+}
+
+// SimulateSync simulates a single sync period of the PHPA at the current time of the reconciler's clock, processing
+// the models and deciding the target replica count in the same way as a reconcile, but using the observation provided
+// rather than gathering metrics and without any calls to the Kubernetes API. The target is treated as if it has been scaled to the target
+// replica count, with the PHPA status and data updated ready for the next simulated sync period
+func (r *PredictiveHorizontalPodAutoscalerReconciler) SimulateSync(ctx context.Context,
+	instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler,
+	phpaData *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, currentReplicas int32,
+	observation SimulatedObservation) (*SimulatedSync, error) {
+	now := r.now()
+
+	var calculatedReplicas int32
+	if observation.CalculatedReplicas != nil {
+		calculatedReplicas = *observation.CalculatedReplicas
+	} else {
+		var err error
+		calculatedReplicas, err = r.Evaluator.EvaluateWithOptions(observation.GatheredMetrics, currentReplicas,
+			getTolerance(instance))
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate metrics and calculate target replica count: %w", err)
+		}
+	}
+
+	if phpaData.ModelHistories == nil {
+		phpaData.ModelHistories = map[string]jamiethompsonmev1alpha1.ModelHistory{}
+	}
+
+	decision, phpaData := r.decide(ctx, instance, phpaData, now, SyncPeriod(instance), currentReplicas,
+		calculatedReplicas, observation.GatheredMetrics)
+
+	if decision.targetReplicas != currentReplicas {
+		recordScaleEvent(instance, currentReplicas, decision.targetReplicas, now)
+	}
+
+	setDecisionStatus(instance, phpaData, decision, now, decision.targetReplicas, calculatedReplicas)
+
+	return &SimulatedSync{
+		CalculatedReplicas: calculatedReplicas,
+		DecidedReplicas:    decision.decidedReplicas,
+		TargetReplicas:     decision.targetReplicas,
+	}, nil
+}
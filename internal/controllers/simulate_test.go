@@ -0,0 +1,402 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	testingclock "k8s.io/utils/clock/testing"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/controllers"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestPredictiveHorizontalPodAutoscalerReconciler_SimulateSync_SharedHistory(t *testing.T) {
+	now := time.Date(2023, time.July, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) *metav1.Time {
+		return &metav1.Time{Time: now.Add(time.Duration(seconds) * time.Second)}
+	}
+	history := func(seconds ...int) []jamiethompsonmev1alpha1.TimestampedReplicas {
+		replicaHistory := []jamiethompsonmev1alpha1.TimestampedReplicas{}
+		for _, s := range seconds {
+			replicaHistory = append(replicaHistory, jamiethompsonmev1alpha1.TimestampedReplicas{
+				Time:     at(s),
+				Replicas: 3,
+			})
+		}
+		return replicaHistory
+	}
+	linear := func(name string, historySize int) jamiethompsonmev1alpha1.Model {
+		return jamiethompsonmev1alpha1.Model{
+			Type: jamiethompsonmev1alpha1.TypeLinear,
+			Name: name,
+			Linear: &jamiethompsonmev1alpha1.Linear{
+				HistorySize: historySize,
+			},
+		}
+	}
+
+	var tests = []struct {
+		description           string
+		expectedModels        map[string][]jamiethompsonmev1alpha1.TimestampedReplicas
+		expectedSharedHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+		spec                  jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec
+		phpaData              *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+	}{
+		{
+			description: "New model, no shared history",
+			expectedModels: map[string][]jamiethompsonmev1alpha1.TimestampedReplicas{
+				"linear": history(0),
+			},
+			expectedSharedHistory: history(0),
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				Models: []jamiethompsonmev1alpha1.Model{
+					linear("linear", 5),
+				},
+			},
+			phpaData: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{},
+		},
+		{
+			description: "Renamed model, history derived from the shared history",
+			expectedModels: map[string][]jamiethompsonmev1alpha1.TimestampedReplicas{
+				"renamed": history(-30, -15, 0),
+			},
+			expectedSharedHistory: history(-30, -15, 0),
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				Models: []jamiethompsonmev1alpha1.Model{
+					linear("renamed", 5),
+				},
+			},
+			phpaData: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type:           jamiethompsonmev1alpha1.TypeLinear,
+						ReplicaHistory: history(-30, -15),
+					},
+				},
+				ReplicaHistory: history(-30, -15),
+			},
+		},
+		{
+			description: "Model type changed, history derived from the shared history",
+			expectedModels: map[string][]jamiethompsonmev1alpha1.TimestampedReplicas{
+				"linear": history(-30, -15, 0),
+			},
+			expectedSharedHistory: history(-30, -15, 0),
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				Models: []jamiethompsonmev1alpha1.Model{
+					linear("linear", 5),
+				},
+			},
+			phpaData: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type:           jamiethompsonmev1alpha1.TypeHoltWinters,
+						ReplicaHistory: history(-30, -15),
+					},
+				},
+				ReplicaHistory: history(-30, -15),
+			},
+		},
+		{
+			description: "History size increased, history derived from the shared history",
+			expectedModels: map[string][]jamiethompsonmev1alpha1.TimestampedReplicas{
+				"linear": history(-60, -45, -30, -15, 0),
+			},
+			expectedSharedHistory: history(-45, -30, -15, 0),
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas:       10,
+				SharedHistorySize: intPtr(4),
+				Models: []jamiethompsonmev1alpha1.Model{
+					linear("linear", 5),
+				},
+			},
+			phpaData: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type:           jamiethompsonmev1alpha1.TypeLinear,
+						ReplicaHistory: history(-15),
+					},
+				},
+				ReplicaHistory: history(-60, -45, -30, -15),
+			},
+		},
+		{
+			description: "Shared history pruned to the longest model history by default",
+			expectedModels: map[string][]jamiethompsonmev1alpha1.TimestampedReplicas{
+				"short": history(-15, 0),
+				"long":  history(-30, -15, 0),
+			},
+			expectedSharedHistory: history(-30, -15, 0),
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				Models: []jamiethompsonmev1alpha1.Model{
+					linear("short", 2),
+					linear("long", 3),
+				},
+			},
+			phpaData: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"short": {
+						Type:           jamiethompsonmev1alpha1.TypeLinear,
+						ReplicaHistory: history(-15),
+					},
+					"long": {
+						Type:           jamiethompsonmev1alpha1.TypeLinear,
+						ReplicaHistory: history(-30, -15),
+					},
+				},
+				ReplicaHistory: history(-60, -45, -30, -15),
+			},
+		},
+		{
+			description: "Shared history before a gap longer than the reset duration not used",
+			expectedModels: map[string][]jamiethompsonmev1alpha1.TimestampedReplicas{
+				"linear": history(-15, 0),
+			},
+			expectedSharedHistory: history(-15, 0),
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				Models: []jamiethompsonmev1alpha1.Model{
+					func() jamiethompsonmev1alpha1.Model {
+						model := linear("linear", 5)
+						model.ResetDuration = &metav1.Duration{Duration: time.Minute}
+						return model
+					}(),
+				},
+			},
+			phpaData: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ReplicaHistory: history(-600, -585, -15),
+			},
+		},
+		{
+			description: "New model with start interval, started from the shared history",
+			expectedModels: map[string][]jamiethompsonmev1alpha1.TimestampedReplicas{
+				"linear": history(-60, -45, -30, -15, 0),
+			},
+			expectedSharedHistory: history(-60, -45, -30, -15, 0),
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				Models: []jamiethompsonmev1alpha1.Model{
+					func() jamiethompsonmev1alpha1.Model {
+						model := linear("linear", 10)
+						model.StartInterval = &metav1.Duration{Duration: time.Minute}
+						return model
+					}(),
+				},
+			},
+			phpaData: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ReplicaHistory: history(-75, -60, -45, -30, -15),
+			},
+		},
+		{
+			description: "New model with start interval, no shared history, waits for the start time",
+			expectedModels: map[string][]jamiethompsonmev1alpha1.TimestampedReplicas{
+				"linear": history(),
+			},
+			expectedSharedHistory: nil,
+			spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+				MaxReplicas: 10,
+				Models: []jamiethompsonmev1alpha1.Model{
+					func() jamiethompsonmev1alpha1.Model {
+						model := linear("linear", 10)
+						model.StartInterval = &metav1.Duration{Duration: time.Minute}
+						return model
+					}(),
+				},
+			},
+			phpaData: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			reconciler := &controllers.PredictiveHorizontalPodAutoscalerReconciler{
+				Recorder: &record.FakeRecorder{},
+				Clock:    testingclock.NewFakePassiveClock(now),
+				Predicter: &fake.Predicter{
+					GetPredictionReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
+						return 3, nil
+					},
+					PruneHistoryReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+						if len(replicaHistory) > model.Linear.HistorySize {
+							return replicaHistory[len(replicaHistory)-model.Linear.HistorySize:], nil
+						}
+						return replicaHistory, nil
+					},
+					GetTypeReactor: func() string {
+						return jamiethompsonmev1alpha1.TypeLinear
+					},
+				},
+			}
+
+			instance := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+				Spec: test.spec,
+			}
+
+			_, err := reconciler.SimulateSync(context.Background(), instance, test.phpaData, 3,
+				controllers.SimulatedObservation{CalculatedReplicas: int32Ptr(3)})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			models := map[string][]jamiethompsonmev1alpha1.TimestampedReplicas{}
+			for name, modelHistory := range test.phpaData.ModelHistories {
+				models[name] = modelHistory.ReplicaHistory
+			}
+
+			if !cmp.Equal(test.expectedModels, models) {
+				t.Errorf("model histories mismatch (-want +got):\n%s", cmp.Diff(test.expectedModels, models))
+			}
+			if !cmp.Equal(test.expectedSharedHistory, test.phpaData.ReplicaHistory) {
+				t.Errorf("shared history mismatch (-want +got):\n%s",
+					cmp.Diff(test.expectedSharedHistory, test.phpaData.ReplicaHistory))
+			}
+		})
+	}
+}
+
+func TestPredictiveHorizontalPodAutoscalerReconciler_SimulateSync_Accuracy(t *testing.T) {
+	start := time.Date(2023, time.July, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) *metav1.Time {
+		return &metav1.Time{Time: start.Add(time.Duration(seconds) * time.Second)}
+	}
+	scored := func(seconds ...int) []jamiethompsonmev1alpha1.ScoredPrediction {
+		scoredPredictions := []jamiethompsonmev1alpha1.ScoredPrediction{}
+		for _, s := range seconds {
+			scoredPredictions = append(scoredPredictions, jamiethompsonmev1alpha1.ScoredPrediction{
+				Time:      at(s),
+				Predicted: 4,
+				Actual:    3,
+			})
+		}
+		return scoredPredictions
+	}
+
+	var tests = []struct {
+		description string
+		expected    []jamiethompsonmev1alpha1.ScoredPrediction
+		model       jamiethompsonmev1alpha1.Model
+		// syncs are the number of seconds after the start that each sync period runs at, varying to account for the
+		// variation in when each sync period runs
+		syncs      []int
+		syncPeriod int
+	}{
+		{
+			description: "Look ahead shorter than half the sync period, scored at the next sync period",
+			expected:    scored(5, 36, 64, 97),
+			model: jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeLinear,
+				Name: "linear",
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 10,
+					LookAhead:   5000,
+				},
+			},
+			syncs:      []int{0, 31, 59, 92, 120},
+			syncPeriod: 30000,
+		},
+		{
+			description: "Look ahead of half the sync period, scored at the next sync period",
+			expected:    scored(10, 31, 49, 72),
+			model: jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeLinear,
+				Name: "linear",
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 10,
+					LookAhead:   10000,
+				},
+			},
+			syncs:      []int{0, 21, 39, 62, 80},
+			syncPeriod: 20000,
+		},
+		{
+			description: "Per sync period of 2, scored at the sync period after each prediction",
+			expected:    scored(36, 97),
+			model: jamiethompsonmev1alpha1.Model{
+				Type:          jamiethompsonmev1alpha1.TypeLinear,
+				Name:          "linear",
+				PerSyncPeriod: intPtr(2),
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 10,
+					LookAhead:   5000,
+				},
+			},
+			syncs:      []int{0, 31, 59, 92, 120},
+			syncPeriod: 30000,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			clock := testingclock.NewFakePassiveClock(start)
+			reconciler := &controllers.PredictiveHorizontalPodAutoscalerReconciler{
+				Recorder: &record.FakeRecorder{},
+				Clock:    clock,
+				Predicter: &fake.Predicter{
+					GetPredictionReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
+						return 4, nil
+					},
+					PruneHistoryReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+						return replicaHistory, nil
+					},
+					GetTypeReactor: func() string {
+						return jamiethompsonmev1alpha1.TypeLinear
+					},
+				},
+			}
+
+			instance := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+				Spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+					MaxReplicas: 10,
+					SyncPeriod:  intPtr(test.syncPeriod),
+					Models:      []jamiethompsonmev1alpha1.Model{test.model},
+				},
+			}
+			phpaData := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{}
+
+			for _, sync := range test.syncs {
+				clock.SetTime(start.Add(time.Duration(sync) * time.Second))
+				_, err := reconciler.SimulateSync(context.Background(), instance, phpaData, 3,
+					controllers.SimulatedObservation{CalculatedReplicas: int32Ptr(3)})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			result := phpaData.ModelHistories[test.model.Name].ScoredPredictions
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("scored predictions mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"time"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+)
+
+// Bootstrap (fake) provides a way to insert functionality into a history bootstrapper
+type Bootstrap struct {
+	GetReplicaHistoryReactor func(definition *jamiethompsonmev1alpha1.HistoryBootstrap, now time.Time, syncPeriod time.Duration) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error)
+	GetTypeReactor           func() string
+}
+
+// GetReplicaHistory calls the fake Bootstrapper function
+func (b *Bootstrap) GetReplicaHistory(definition *jamiethompsonmev1alpha1.HistoryBootstrap, now time.Time, syncPeriod time.Duration) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+	return b.GetReplicaHistoryReactor(definition, now, syncPeriod)
+}
+
+// GetType calls the fake Bootstrapper function
+func (b *Bootstrap) GetType() string {
+	return b.GetTypeReactor()
+}
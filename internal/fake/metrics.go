@@ -0,0 +1,50 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics"
+	externalmetrics "github.com/jthomperoo/k8shorizmetrics/v2/metrics/external"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ExternalGatherer (fake) provides a way to insert functionality into an external metric gatherer
+type ExternalGatherer struct {
+	GatherReactor       func(metricName, namespace string, metricSelector *metav1.LabelSelector, podSelector labels.Selector) (*externalmetrics.Metric, error)
+	GatherPerPodReactor func(metricName, namespace string, metricSelector *metav1.LabelSelector) (*externalmetrics.Metric, error)
+}
+
+// Gather calls the fake ExternalGatherer function
+func (f *ExternalGatherer) Gather(metricName, namespace string, metricSelector *metav1.LabelSelector, podSelector labels.Selector) (*externalmetrics.Metric, error) {
+	return f.GatherReactor(metricName, namespace, metricSelector, podSelector)
+}
+
+// GatherPerPod calls the fake ExternalGatherer function
+func (f *ExternalGatherer) GatherPerPod(metricName, namespace string, metricSelector *metav1.LabelSelector) (*externalmetrics.Metric, error) {
+	return f.GatherPerPodReactor(metricName, namespace, metricSelector)
+}
+
+// ExternalEvaluater (fake) provides a way to insert functionality into an external metric evaluater
+type ExternalEvaluater struct {
+	EvaluateReactor func(currentReplicas int32, gatheredMetric *metrics.Metric, tolerance float64) (int32, error)
+}
+
+// Evaluate calls the fake ExternalEvaluater function
+func (f *ExternalEvaluater) Evaluate(currentReplicas int32, gatheredMetric *metrics.Metric, tolerance float64) (int32, error) {
+	return f.EvaluateReactor(currentReplicas, gatheredMetric, tolerance)
+}
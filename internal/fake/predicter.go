@@ -18,6 +18,7 @@ package fake
 
 import (
 	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
 )
 
 // Predicter (fake) provides a way to insert functionality into a Predicter
@@ -41,3 +42,60 @@ func (f *Predicter) GetPrediction(model *jamiethompsonmev1alpha1.Model, replicaH
 func (f *Predicter) GetType() string {
 	return f.GetTypeReactor()
 }
+
+// MetricPredicter (fake) provides a way to insert functionality into a Predicter that supports forecasting metrics
+type MetricPredicter struct {
+	Predicter
+	GetMetricPredictionReactor func(model *jamiethompsonmev1alpha1.Model, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (float64, error)
+}
+
+// GetMetricPrediction calls the fake MetricPredicter function
+func (f *MetricPredicter) GetMetricPrediction(model *jamiethompsonmev1alpha1.Model, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (float64, error) {
+	return f.GetMetricPredictionReactor(model, metricHistory)
+}
+
+// IntervalPredicter (fake) provides a way to insert functionality into a Predicter that supports prediction intervals
+type IntervalPredicter struct {
+	Predicter
+	GetPredictionIntervalReactor func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, confidence float64) (*prediction.Interval, error)
+}
+
+// GetPredictionInterval calls the fake IntervalPredicter function
+func (f *IntervalPredicter) GetPredictionInterval(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, confidence float64) (*prediction.Interval, error) {
+	return f.GetPredictionIntervalReactor(model, replicaHistory, confidence)
+}
+
+// Tuner (fake) provides a way to insert functionality into a Predicter that supports tuning model parameters
+type Tuner struct {
+	Predicter
+	TuneReactor func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (*jamiethompsonmev1alpha1.HoltWintersTunedParameters, error)
+}
+
+// Tune calls the fake Tuner function
+func (f *Tuner) Tune(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (*jamiethompsonmev1alpha1.HoltWintersTunedParameters, error) {
+	return f.TuneReactor(model, replicaHistory)
+}
+
+// TuningFetcher (fake) provides a way to insert functionality into a Predicter that supports fetching runtime tuning
+// values
+type TuningFetcher struct {
+	Predicter
+	FetchTuningReactor func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (*jamiethompsonmev1alpha1.Model, error)
+}
+
+// FetchTuning calls the fake TuningFetcher function
+func (f *TuningFetcher) FetchTuning(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (*jamiethompsonmev1alpha1.Model, error) {
+	return f.FetchTuningReactor(model, replicaHistory, metricHistory)
+}
+
+// HistoryChecker (fake) provides a way to insert functionality into a Predicter that checks if there is enough history
+// to make a prediction
+type HistoryChecker struct {
+	Predicter
+	HasEnoughHistoryReactor func(model *jamiethompsonmev1alpha1.Model, historyLength int) (bool, error)
+}
+
+// HasEnoughHistory calls the fake HistoryChecker function
+func (f *HistoryChecker) HasEnoughHistory(model *jamiethompsonmev1alpha1.Model, historyLength int) (bool, error) {
+	return f.HasEnoughHistoryReactor(model, historyLength)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configmap provides a history store that stores the model histories of a PHPA as JSON in a config map.
+package configmap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+)
+
+const (
+	nameFormat = "predictive-horizontal-pod-autoscaler-%s-data"
+	dataKey    = "data"
+)
+
+// Store stores the model histories of a PHPA in a config map named after the PHPA and owned by it
+type Store struct {
+	Client client.Client
+}
+
+// Name returns the name of the config map that the model histories of the PHPA are stored in
+func Name(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler) string {
+	return fmt.Sprintf(nameFormat, instance.Name)
+}
+
+// Decode parses the model histories stored in a config map
+func Decode(configMap *corev1.ConfigMap) (*jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, error) {
+	data := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{}
+	err := json.Unmarshal([]byte(configMap.Data[dataKey]), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config map data: %w", err)
+	}
+
+	if data.ModelHistories == nil {
+		data.ModelHistories = map[string]jamiethompsonmev1alpha1.ModelHistory{}
+	}
+
+	return data, nil
+}
+
+// Load gets the model histories stored in the PHPA's config map, the version is the config map's resource version
+func (s *Store) Load(ctx context.Context, instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler) (*jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, string, error) {
+	configMap := &corev1.ConfigMap{}
+	err := s.Client.Get(ctx, types.NamespacedName{
+		Name:      Name(instance),
+		Namespace: instance.Namespace,
+	}, configMap)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+			}, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to get config map: %w", err)
+	}
+
+	data, err := Decode(configMap)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, configMap.ResourceVersion, nil
+}
+
+// Read gets the model histories stored in the PHPA's config map
+func (s *Store) Read(ctx context.Context, instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler) (*jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, error) {
+	data, _, err := s.Load(ctx, instance)
+	return data, err
+}
+
+// Save stores the model histories in the PHPA's config map, creating the config map if no version is provided. If the
+// config map's resource version does not match the version provided a conflict error is returned
+func (s *Store) Save(ctx context.Context, instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, data *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, version string) error {
+	if version == "" {
+		return s.create(ctx, instance, data)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := s.Client.Get(ctx, types.NamespacedName{
+		Name:      Name(instance),
+		Namespace: instance.Namespace,
+	}, configMap)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return conflictError(instance)
+		}
+		return fmt.Errorf("failed to get config map: %w", err)
+	}
+
+	if configMap.ResourceVersion != version {
+		return conflictError(instance)
+	}
+
+	configMap.Data = map[string]string{
+		dataKey: encode(data),
+	}
+
+	// The update uses the resource version of the config map that was fetched, so it is rejected with a conflict if
+	// the config map is changed after it was fetched
+	err = s.Client.Update(ctx, configMap)
+	if err != nil {
+		return fmt.Errorf("failed to update config map data: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) create(ctx context.Context, instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, data *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(instance),
+			Namespace: instance.Namespace,
+		},
+		Data: map[string]string{
+			dataKey: encode(data),
+		},
+	}
+
+	configMap.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: instance.APIVersion,
+		Kind:       instance.Kind,
+		Name:       instance.Name,
+		UID:        instance.UID,
+	}})
+
+	err := s.Client.Create(ctx, configMap)
+	if err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return conflictError(instance)
+		}
+		return fmt.Errorf("failed to create config map: %w", err)
+	}
+
+	return nil
+}
+
+func encode(data *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData) string {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+	return string(encoded)
+}
+
+// conflictError is returned when the PHPA's config map has been changed since the model histories were loaded
+func conflictError(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler) error {
+	return k8serrors.NewConflict(corev1.Resource("configmaps"), Name(instance),
+		errors.New("the model history has been changed since it was loaded"))
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configmap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history/configmap"
+)
+
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = jamiethompsonmev1alpha1.AddToScheme(scheme)
+	return scheme
+}
+
+var testInstance = &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+	TypeMeta: metav1.TypeMeta{
+		APIVersion: "jamiethompson.me/v1alpha1",
+		Kind:       "PredictiveHorizontalPodAutoscaler",
+	},
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "test",
+		Namespace: "default",
+		UID:       "test-uid",
+	},
+}
+
+var testTime = &metav1.Time{Time: time.Date(2023, time.July, 1, 12, 0, 0, 0, time.UTC)}
+
+func TestStore_Load(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description     string
+		expected        *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+		expectedVersion string
+		expectedErr     error
+		objects         []client.Object
+	}{
+		{
+			description:     "Fail, invalid config map data",
+			expected:        nil,
+			expectedVersion: "",
+			expectedErr:     errors.New("failed to parse config map data: invalid character 'i' looking for beginning of value"),
+			objects: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "predictive-horizontal-pod-autoscaler-test-data",
+						Namespace: "default",
+					},
+					Data: map[string]string{
+						"data": "invalid",
+					},
+				},
+			},
+		},
+		{
+			description: "Success, no config map",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+			},
+			expectedVersion: "",
+			expectedErr:     nil,
+			objects:         []client.Object{},
+		},
+		{
+			description: "Success, config map with no model histories",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+			},
+			expectedVersion: "999",
+			expectedErr:     nil,
+			objects: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "predictive-horizontal-pod-autoscaler-test-data",
+						Namespace: "default",
+					},
+					Data: map[string]string{
+						"data": `{}`,
+					},
+				},
+			},
+		},
+		{
+			description: "Success, config map with model histories",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: testTime, Replicas: 3},
+						},
+					},
+				},
+			},
+			expectedVersion: "999",
+			expectedErr:     nil,
+			objects: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "predictive-horizontal-pod-autoscaler-test-data",
+						Namespace: "default",
+					},
+					Data: map[string]string{
+						"data": `{"modelHistories":{"linear":{"type":"Linear","syncPeriodsPassed":1,"replicaHistory":[{"time":"2023-07-01T12:00:00Z","replicas":3}]}}}`,
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			store := &configmap.Store{
+				Client: fake.NewClientBuilder().WithScheme(newScheme()).WithObjects(test.objects...).Build(),
+			}
+			result, version, err := store.Load(context.Background(), testInstance)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("model history mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+			if !cmp.Equal(test.expectedVersion, version) {
+				t.Errorf("version mismatch (-want +got):\n%s", cmp.Diff(test.expectedVersion, version))
+			}
+		})
+	}
+}
+
+func TestStore_Save(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	existingConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "predictive-horizontal-pod-autoscaler-test-data",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			"data": `{"modelHistories":{"linear":{"type":"Linear","syncPeriodsPassed":1,"replicaHistory":[]}}}`,
+		},
+	}
+
+	var tests = []struct {
+		description string
+		expected    *corev1.ConfigMap
+		expectedErr error
+		objects     []client.Object
+		data        *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+		version     string
+	}{
+		{
+			description: "Fail, config map created since the model histories were loaded",
+			expected:    existingConfigMap,
+			expectedErr: errors.New(`Operation cannot be fulfilled on configmaps "predictive-horizontal-pod-autoscaler-test-data": ` +
+				`the model history has been changed since it was loaded`),
+			objects: []client.Object{existingConfigMap},
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+			},
+			version: "",
+		},
+		{
+			description: "Fail, config map deleted since the model histories were loaded",
+			expected:    nil,
+			expectedErr: errors.New(`Operation cannot be fulfilled on configmaps "predictive-horizontal-pod-autoscaler-test-data": ` +
+				`the model history has been changed since it was loaded`),
+			objects: []client.Object{},
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+			},
+			version: "999",
+		},
+		{
+			description: "Fail, config map updated since the model histories were loaded",
+			expected:    existingConfigMap,
+			expectedErr: errors.New(`Operation cannot be fulfilled on configmaps "predictive-horizontal-pod-autoscaler-test-data": ` +
+				`the model history has been changed since it was loaded`),
+			objects: []client.Object{existingConfigMap},
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+			},
+			version: "998",
+		},
+		{
+			description: "Create config map",
+			expected: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "predictive-horizontal-pod-autoscaler-test-data",
+					Namespace: "default",
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "jamiethompson.me/v1alpha1",
+						Kind:       "PredictiveHorizontalPodAutoscaler",
+						Name:       "test",
+						UID:        "test-uid",
+					}},
+				},
+				Data: map[string]string{
+					"data": `{"modelHistories":{"linear":{"type":"Linear","syncPeriodsPassed":1,"replicaHistory":[{"time":"2023-07-01T12:00:00Z","replicas":3}],"startTime":null}}}`,
+				},
+			},
+			expectedErr: nil,
+			objects:     []client.Object{},
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: testTime, Replicas: 3},
+						},
+					},
+				},
+			},
+			version: "",
+		},
+		{
+			description: "Update existing config map",
+			expected: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "predictive-horizontal-pod-autoscaler-test-data",
+					Namespace: "default",
+				},
+				Data: map[string]string{
+					"data": `{"modelHistories":{}}`,
+				},
+			},
+			expectedErr: nil,
+			objects:     []client.Object{existingConfigMap},
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+			},
+			version: "999",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(newScheme()).WithObjects(test.objects...).Build()
+			store := &configmap.Store{
+				Client: fakeClient,
+			}
+			err := store.Save(context.Background(), testInstance, test.data, test.version)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+
+			result := &corev1.ConfigMap{}
+			err = fakeClient.Get(context.Background(), types.NamespacedName{
+				Name:      "predictive-horizontal-pod-autoscaler-test-data",
+				Namespace: "default",
+			}, result)
+			if err != nil && !k8serrors.IsNotFound(err) {
+				t.Errorf("failed to get config map: %v", err)
+				return
+			}
+
+			if test.expected == nil {
+				if err == nil {
+					t.Errorf("expected no config map, got %v", result)
+				}
+				return
+			}
+
+			if !cmp.Equal(test.expected.ObjectMeta.OwnerReferences, result.ObjectMeta.OwnerReferences) {
+				t.Errorf("owner references mismatch (-want +got):\n%s", cmp.Diff(test.expected.ObjectMeta.OwnerReferences,
+					result.ObjectMeta.OwnerReferences))
+			}
+			if !cmp.Equal(test.expected.Data, result.Data) {
+				t.Errorf("data mismatch (-want +got):\n%s", cmp.Diff(test.expected.Data, result.Data))
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package history provides storage of PHPA model histories.
+package history
+
+import (
+	"context"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+)
+
+const (
+	// TypeConfigMap stores the model histories of a PHPA as JSON in a config map
+	TypeConfigMap = "configmap"
+	// TypeModelState stores the model histories of a PHPA in a PHPAModelState, using a compact encoding
+	TypeModelState = "modelstate"
+)
+
+// Store provides a way to load and save the model histories of a PHPA
+type Store interface {
+	// Load gets the stored model histories of the PHPA along with the version of the stored model histories, if the
+	// PHPA has no stored model histories empty model histories and an empty version are returned
+	Load(ctx context.Context, instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler) (*jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, string, error)
+	// Read gets the stored model histories of the PHPA without changing how or where they are stored, if the PHPA has
+	// no stored model histories empty model histories are returned
+	Read(ctx context.Context, instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler) (*jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, error)
+	// Save stores the model histories of the PHPA, replacing the model histories loaded at the version provided. If
+	// the stored model histories have been changed since they were loaded nothing is stored and a conflict error is
+	// returned, allowing the model histories to be loaded and saved again
+	Save(ctx context.Context, instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, data *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, version string) error
+}
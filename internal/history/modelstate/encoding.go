@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelstate
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+)
+
+// Encode converts a PHPA's model histories into the compact encoding stored in a PHPAModelState. Times are stored to
+// the second, the same precision as they are serialised at, and any missing times are treated as the same as the
+// previous time
+func Encode(data *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData) jamiethompsonmev1alpha1.PHPAModelStateSpec {
+	spec := jamiethompsonmev1alpha1.PHPAModelStateSpec{
+		ModelHistories: make(map[string]jamiethompsonmev1alpha1.ModelState, len(data.ModelHistories)),
+		ReplicaHistory: encodeReplicaHistory(data.ReplicaHistory),
+		MetricHistory:  encodeMetricHistory(data.MetricHistory),
+	}
+
+	for name, history := range data.ModelHistories {
+		spec.ModelHistories[name] = jamiethompsonmev1alpha1.ModelState{
+			Type:               history.Type,
+			SyncPeriodsPassed:  history.SyncPeriodsPassed,
+			ReplicaHistory:     encodeReplicaHistory(history.ReplicaHistory),
+			StartTime:          history.StartTime,
+			MetricHistory:      encodeMetricHistory(history.MetricHistory),
+			PendingPredictions: history.PendingPredictions,
+			ScoredPredictions:  history.ScoredPredictions,
+			Excluded:           history.Excluded,
+			TunedParameters:    history.TunedParameters,
+			LastPrediction:     history.LastPrediction,
+		}
+	}
+
+	return spec
+}
+
+// Decode converts the compact encoding stored in a PHPAModelState into a PHPA's model histories
+func Decode(spec jamiethompsonmev1alpha1.PHPAModelStateSpec) *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData {
+	data := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+		ModelHistories: make(map[string]jamiethompsonmev1alpha1.ModelHistory, len(spec.ModelHistories)),
+		MetricHistory:  decodeMetricHistory(spec.MetricHistory),
+	}
+
+	// Unlike model replica histories the shared replica history is left unset if there is none
+	if spec.ReplicaHistory != nil {
+		data.ReplicaHistory = decodeReplicaHistory(spec.ReplicaHistory)
+	}
+
+	for name, state := range spec.ModelHistories {
+		data.ModelHistories[name] = jamiethompsonmev1alpha1.ModelHistory{
+			Type:               state.Type,
+			SyncPeriodsPassed:  state.SyncPeriodsPassed,
+			ReplicaHistory:     decodeReplicaHistory(state.ReplicaHistory),
+			StartTime:          state.StartTime,
+			MetricHistory:      decodeMetricHistory(state.MetricHistory),
+			PendingPredictions: state.PendingPredictions,
+			ScoredPredictions:  state.ScoredPredictions,
+			Excluded:           state.Excluded,
+			TunedParameters:    state.TunedParameters,
+			LastPrediction:     state.LastPrediction,
+		}
+	}
+
+	return data
+}
+
+func encodeReplicaHistory(replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) *jamiethompsonmev1alpha1.CompactReplicaHistory {
+	if len(replicaHistory) == 0 {
+		return nil
+	}
+
+	times := make([]*metav1.Time, len(replicaHistory))
+	replicas := make([]int32, len(replicaHistory))
+	for i, timestampedReplicas := range replicaHistory {
+		times[i] = timestampedReplicas.Time
+		replicas[i] = timestampedReplicas.Replicas
+	}
+
+	start, intervals := encodeTimes(times)
+
+	return &jamiethompsonmev1alpha1.CompactReplicaHistory{
+		Start:     start,
+		Intervals: intervals,
+		Replicas:  replicas,
+	}
+}
+
+func decodeReplicaHistory(compact *jamiethompsonmev1alpha1.CompactReplicaHistory) []jamiethompsonmev1alpha1.TimestampedReplicas {
+	if compact == nil {
+		return []jamiethompsonmev1alpha1.TimestampedReplicas{}
+	}
+
+	times := decodeTimes(compact.Start, compact.Intervals)
+
+	// Mismatched columns can only occur if the model state has been modified by hand, only decode the entries that
+	// have both a time and a replica count
+	length := len(times)
+	if len(compact.Replicas) < length {
+		length = len(compact.Replicas)
+	}
+
+	replicaHistory := make([]jamiethompsonmev1alpha1.TimestampedReplicas, length)
+	for i := 0; i < length; i++ {
+		replicaHistory[i] = jamiethompsonmev1alpha1.TimestampedReplicas{
+			Time:     times[i],
+			Replicas: compact.Replicas[i],
+		}
+	}
+
+	return replicaHistory
+}
+
+func encodeMetricHistory(metricHistory []jamiethompsonmev1alpha1.TimestampedMetrics) *jamiethompsonmev1alpha1.CompactMetricHistory {
+	if len(metricHistory) == 0 {
+		return nil
+	}
+
+	times := make([]*metav1.Time, len(metricHistory))
+	values := make([][]int64, len(metricHistory))
+	for i, timestampedMetrics := range metricHistory {
+		times[i] = timestampedMetrics.Time
+		values[i] = timestampedMetrics.Values
+	}
+
+	start, intervals := encodeTimes(times)
+
+	return &jamiethompsonmev1alpha1.CompactMetricHistory{
+		Start:     start,
+		Intervals: intervals,
+		Values:    values,
+	}
+}
+
+func decodeMetricHistory(compact *jamiethompsonmev1alpha1.CompactMetricHistory) []jamiethompsonmev1alpha1.TimestampedMetrics {
+	if compact == nil {
+		return nil
+	}
+
+	times := decodeTimes(compact.Start, compact.Intervals)
+
+	length := len(times)
+	if len(compact.Values) < length {
+		length = len(compact.Values)
+	}
+
+	metricHistory := make([]jamiethompsonmev1alpha1.TimestampedMetrics, length)
+	for i := 0; i < length; i++ {
+		metricHistory[i] = jamiethompsonmev1alpha1.TimestampedMetrics{
+			Time:   times[i],
+			Values: compact.Values[i],
+		}
+	}
+
+	return metricHistory
+}
+
+// encodeTimes converts a list of times into a start time and the number of seconds between each time and the
+// previous time, with the start time being the first time provided
+func encodeTimes(times []*metav1.Time) (metav1.Time, []int64) {
+	var start int64
+	for _, t := range times {
+		if t != nil {
+			start = t.Unix()
+			break
+		}
+	}
+
+	intervals := make([]int64, len(times))
+	previous := start
+	for i, t := range times {
+		if t == nil {
+			continue
+		}
+		intervals[i] = t.Unix() - previous
+		previous = t.Unix()
+	}
+
+	return metav1.NewTime(time.Unix(start, 0).UTC()), intervals
+}
+
+// decodeTimes converts a start time and the number of seconds between each time and the previous time into a list of
+// times
+func decodeTimes(start metav1.Time, intervals []int64) []*metav1.Time {
+	times := make([]*metav1.Time, len(intervals))
+	current := start.Unix()
+	for i, interval := range intervals {
+		current += interval
+		times[i] = &metav1.Time{Time: time.Unix(current, 0).UTC()}
+	}
+	return times
+}
@@ -0,0 +1,334 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelstate_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history/modelstate"
+)
+
+func timeAt(seconds int64) *metav1.Time {
+	start := time.Date(2023, time.July, 1, 12, 0, 0, 0, time.UTC)
+	return &metav1.Time{Time: start.Add(time.Duration(seconds) * time.Second)}
+}
+
+func TestEncode(t *testing.T) {
+	var tests = []struct {
+		description string
+		expected    jamiethompsonmev1alpha1.PHPAModelStateSpec
+		data        *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+	}{
+		{
+			description: "No model histories",
+			expected: jamiethompsonmev1alpha1.PHPAModelStateSpec{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{},
+			},
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{},
+		},
+		{
+			description: "Model with empty history",
+			expected: jamiethompsonmev1alpha1.PHPAModelStateSpec{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{
+					"test": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+					},
+				},
+			},
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"test": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory:    []jamiethompsonmev1alpha1.TimestampedReplicas{},
+					},
+				},
+			},
+		},
+		{
+			description: "Model with replica and metric history, sub-second times truncated",
+			expected: jamiethompsonmev1alpha1.PHPAModelStateSpec{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{
+					"test": {
+						Type:              jamiethompsonmev1alpha1.TypeHoltWinters,
+						SyncPeriodsPassed: 3,
+						StartTime:         timeAt(-60),
+						ReplicaHistory: &jamiethompsonmev1alpha1.CompactReplicaHistory{
+							Start:     *timeAt(0),
+							Intervals: []int64{0, 15, 30},
+							Replicas:  []int32{1, 3, 2},
+						},
+						MetricHistory: &jamiethompsonmev1alpha1.CompactMetricHistory{
+							Start:     *timeAt(15),
+							Intervals: []int64{0, 30},
+							Values:    [][]int64{{1000, 2000}, {3000, 4000}},
+						},
+						Excluded: true,
+						LastPrediction: &jamiethompsonmev1alpha1.TimestampedReplicas{
+							Time:     timeAt(45),
+							Replicas: 4,
+						},
+					},
+				},
+			},
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"test": {
+						Type:              jamiethompsonmev1alpha1.TypeHoltWinters,
+						SyncPeriodsPassed: 3,
+						StartTime:         timeAt(-60),
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timeAt(0), Replicas: 1},
+							{Time: &metav1.Time{Time: timeAt(15).Add(500 * time.Millisecond)}, Replicas: 3},
+							{Time: timeAt(45), Replicas: 2},
+						},
+						MetricHistory: []jamiethompsonmev1alpha1.TimestampedMetrics{
+							{Time: timeAt(15), Values: []int64{1000, 2000}},
+							{Time: timeAt(45), Values: []int64{3000, 4000}},
+						},
+						Excluded: true,
+						LastPrediction: &jamiethompsonmev1alpha1.TimestampedReplicas{
+							Time:     timeAt(45),
+							Replicas: 4,
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "Shared replica and metric history",
+			expected: jamiethompsonmev1alpha1.PHPAModelStateSpec{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{},
+				ReplicaHistory: &jamiethompsonmev1alpha1.CompactReplicaHistory{
+					Start:     *timeAt(0),
+					Intervals: []int64{0, 15},
+					Replicas:  []int32{2, 4},
+				},
+				MetricHistory: &jamiethompsonmev1alpha1.CompactMetricHistory{
+					Start:     *timeAt(0),
+					Intervals: []int64{0, 15},
+					Values:    [][]int64{{1000}, {2000}},
+				},
+			},
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+					{Time: timeAt(0), Replicas: 2},
+					{Time: timeAt(15), Replicas: 4},
+				},
+				MetricHistory: []jamiethompsonmev1alpha1.TimestampedMetrics{
+					{Time: timeAt(0), Values: []int64{1000}},
+					{Time: timeAt(15), Values: []int64{2000}},
+				},
+			},
+		},
+		{
+			description: "Missing times treated as the previous time",
+			expected: jamiethompsonmev1alpha1.PHPAModelStateSpec{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{
+					"test": {
+						Type: jamiethompsonmev1alpha1.TypeLinear,
+						ReplicaHistory: &jamiethompsonmev1alpha1.CompactReplicaHistory{
+							Start:     *timeAt(15),
+							Intervals: []int64{0, 0, 15},
+							Replicas:  []int32{1, 2, 3},
+						},
+					},
+				},
+			},
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"test": {
+						Type: jamiethompsonmev1alpha1.TypeLinear,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: nil, Replicas: 1},
+							{Time: timeAt(15), Replicas: 2},
+							{Time: timeAt(30), Replicas: 3},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result := modelstate.Encode(test.data)
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("model state mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var tests = []struct {
+		description string
+		expected    *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+		spec        jamiethompsonmev1alpha1.PHPAModelStateSpec
+	}{
+		{
+			description: "No model histories",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+			},
+			spec: jamiethompsonmev1alpha1.PHPAModelStateSpec{},
+		},
+		{
+			description: "Model with empty history",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"test": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory:    []jamiethompsonmev1alpha1.TimestampedReplicas{},
+					},
+				},
+			},
+			spec: jamiethompsonmev1alpha1.PHPAModelStateSpec{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{
+					"test": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+					},
+				},
+			},
+		},
+		{
+			description: "Model with replica and metric history",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"test": {
+						Type:              jamiethompsonmev1alpha1.TypeHoltWinters,
+						SyncPeriodsPassed: 3,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timeAt(0), Replicas: 1},
+							{Time: timeAt(15), Replicas: 3},
+							{Time: timeAt(45), Replicas: 2},
+						},
+						MetricHistory: []jamiethompsonmev1alpha1.TimestampedMetrics{
+							{Time: timeAt(15), Values: []int64{1000, 2000}},
+							{Time: timeAt(45), Values: []int64{3000, 4000}},
+						},
+						PendingPredictions: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timeAt(60), Replicas: 5},
+						},
+						ScoredPredictions: []jamiethompsonmev1alpha1.ScoredPrediction{
+							{Time: timeAt(45), Predicted: 3, Actual: 2},
+						},
+					},
+				},
+			},
+			spec: jamiethompsonmev1alpha1.PHPAModelStateSpec{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{
+					"test": {
+						Type:              jamiethompsonmev1alpha1.TypeHoltWinters,
+						SyncPeriodsPassed: 3,
+						ReplicaHistory: &jamiethompsonmev1alpha1.CompactReplicaHistory{
+							Start:     *timeAt(0),
+							Intervals: []int64{0, 15, 30},
+							Replicas:  []int32{1, 3, 2},
+						},
+						MetricHistory: &jamiethompsonmev1alpha1.CompactMetricHistory{
+							Start:     *timeAt(15),
+							Intervals: []int64{0, 30},
+							Values:    [][]int64{{1000, 2000}, {3000, 4000}},
+						},
+						PendingPredictions: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timeAt(60), Replicas: 5},
+						},
+						ScoredPredictions: []jamiethompsonmev1alpha1.ScoredPrediction{
+							{Time: timeAt(45), Predicted: 3, Actual: 2},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "Shared replica and metric history",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+				ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+					{Time: timeAt(0), Replicas: 2},
+					{Time: timeAt(15), Replicas: 4},
+				},
+				MetricHistory: []jamiethompsonmev1alpha1.TimestampedMetrics{
+					{Time: timeAt(0), Values: []int64{1000}},
+					{Time: timeAt(15), Values: []int64{2000}},
+				},
+			},
+			spec: jamiethompsonmev1alpha1.PHPAModelStateSpec{
+				ReplicaHistory: &jamiethompsonmev1alpha1.CompactReplicaHistory{
+					Start:     *timeAt(0),
+					Intervals: []int64{0, 15},
+					Replicas:  []int32{2, 4},
+				},
+				MetricHistory: &jamiethompsonmev1alpha1.CompactMetricHistory{
+					Start:     *timeAt(0),
+					Intervals: []int64{0, 15},
+					Values:    [][]int64{{1000}, {2000}},
+				},
+			},
+		},
+		{
+			description: "Mismatched columns, only entries with a time and value decoded",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"test": {
+						Type: jamiethompsonmev1alpha1.TypeLinear,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timeAt(0), Replicas: 1},
+							{Time: timeAt(15), Replicas: 3},
+						},
+						MetricHistory: []jamiethompsonmev1alpha1.TimestampedMetrics{
+							{Time: timeAt(0), Values: []int64{1000}},
+						},
+					},
+				},
+			},
+			spec: jamiethompsonmev1alpha1.PHPAModelStateSpec{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{
+					"test": {
+						Type: jamiethompsonmev1alpha1.TypeLinear,
+						ReplicaHistory: &jamiethompsonmev1alpha1.CompactReplicaHistory{
+							Start:     *timeAt(0),
+							Intervals: []int64{0, 15, 15},
+							Replicas:  []int32{1, 3},
+						},
+						MetricHistory: &jamiethompsonmev1alpha1.CompactMetricHistory{
+							Start:     *timeAt(0),
+							Intervals: []int64{0},
+							Values:    [][]int64{{1000}, {2000}},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result := modelstate.Decode(test.spec)
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("model history mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
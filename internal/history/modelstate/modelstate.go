@@ -0,0 +1,198 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package modelstate provides a history store that stores the model histories of a PHPA in a PHPAModelState, using a
+// compact encoding for the replica and metric histories.
+package modelstate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history/configmap"
+)
+
+// Store stores the model histories of a PHPA in a PHPAModelState with the same name as the PHPA and owned by it. If
+// the PHPA has no model state its model histories are migrated from the PHPA's config map, if it has one
+type Store struct {
+	Client   client.Client
+	Recorder record.EventRecorder
+}
+
+// Load gets the model histories stored in the PHPA's model state, if the PHPA does not have a model state the model
+// histories are migrated from the PHPA's config map. The version is the model state's resource version
+func (s *Store) Load(ctx context.Context, instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler) (*jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, string, error) {
+	modelState := &jamiethompsonmev1alpha1.PHPAModelState{}
+	err := s.Client.Get(ctx, types.NamespacedName{
+		Name:      instance.Name,
+		Namespace: instance.Namespace,
+	}, modelState)
+	if err == nil {
+		return Decode(modelState.Spec), modelState.ResourceVersion, nil
+	}
+
+	if !k8serrors.IsNotFound(err) {
+		return nil, "", fmt.Errorf("failed to get model state: %w", err)
+	}
+
+	return s.migrate(ctx, instance)
+}
+
+// Read gets the model histories stored in the PHPA's model state, if the PHPA does not have a model state the model
+// histories are read from the PHPA's config map without migrating them
+func (s *Store) Read(ctx context.Context, instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler) (*jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, error) {
+	modelState := &jamiethompsonmev1alpha1.PHPAModelState{}
+	err := s.Client.Get(ctx, types.NamespacedName{
+		Name:      instance.Name,
+		Namespace: instance.Namespace,
+	}, modelState)
+	if err == nil {
+		return Decode(modelState.Spec), nil
+	}
+
+	if !k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get model state: %w", err)
+	}
+
+	configMapStore := &configmap.Store{
+		Client: s.Client,
+	}
+	return configMapStore.Read(ctx, instance)
+}
+
+// Save stores the model histories in the PHPA's model state, creating the model state if no version is provided. If
+// the model state's resource version does not match the version provided a conflict error is returned
+func (s *Store) Save(ctx context.Context, instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, data *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, version string) error {
+	if version == "" {
+		_, err := s.create(ctx, instance, data)
+		return err
+	}
+
+	modelState := &jamiethompsonmev1alpha1.PHPAModelState{}
+	err := s.Client.Get(ctx, types.NamespacedName{
+		Name:      instance.Name,
+		Namespace: instance.Namespace,
+	}, modelState)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return conflictError(instance)
+		}
+		return fmt.Errorf("failed to get model state: %w", err)
+	}
+
+	if modelState.ResourceVersion != version {
+		return conflictError(instance)
+	}
+
+	modelState.Spec = Encode(data)
+
+	// The update uses the resource version of the model state that was fetched, so it is rejected with a conflict if
+	// the model state is changed after it was fetched
+	err = s.Client.Update(ctx, modelState)
+	if err != nil {
+		return fmt.Errorf("failed to update model state: %w", err)
+	}
+
+	return nil
+}
+
+// migrate moves the model histories stored in the PHPA's config map into a new model state, deleting the config map,
+// and returns them along with the new model state's resource version. If the PHPA has no config map empty model
+// histories and an empty version are returned
+func (s *Store) migrate(ctx context.Context, instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler) (*jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, string, error) {
+	logger := log.FromContext(ctx)
+
+	configMap := &corev1.ConfigMap{}
+	err := s.Client.Get(ctx, types.NamespacedName{
+		Name:      configmap.Name(instance),
+		Namespace: instance.Namespace,
+	}, configMap)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+			}, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to get config map to migrate: %w", err)
+	}
+
+	data, err := configmap.Decode(configMap)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to migrate config map: %w", err)
+	}
+
+	version, err := s.create(ctx, instance, data)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to migrate config map: %w", err)
+	}
+
+	s.Recorder.Eventf(instance, corev1.EventTypeNormal, "MigratedModelHistory",
+		"migrated the model history from config map %s to model state %s", configMap.Name, instance.Name)
+
+	// The model histories have been migrated, so remove the config map. If this fails the config map will still be
+	// garbage collected when the PHPA is deleted
+	err = s.Client.Delete(ctx, configMap)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		logger.Error(err, "failed to delete config map after migrating model history", "configMap", configMap.Name)
+	}
+
+	return data, version, nil
+}
+
+// create creates the PHPA's model state with the model histories provided, returning the model state's resource
+// version
+func (s *Store) create(ctx context.Context, instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, data *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData) (string, error) {
+	modelState := &jamiethompsonmev1alpha1.PHPAModelState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name,
+			Namespace: instance.Namespace,
+		},
+		Spec: Encode(data),
+	}
+
+	modelState.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: instance.APIVersion,
+		Kind:       instance.Kind,
+		Name:       instance.Name,
+		UID:        instance.UID,
+	}})
+
+	err := s.Client.Create(ctx, modelState)
+	if err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return "", conflictError(instance)
+		}
+		return "", fmt.Errorf("failed to create model state: %w", err)
+	}
+
+	return modelState.ResourceVersion, nil
+}
+
+// conflictError is returned when the PHPA's model state has been changed since the model histories were loaded
+func conflictError(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler) error {
+	return k8serrors.NewConflict(jamiethompsonmev1alpha1.GroupVersion.WithResource("phpamodelstates").GroupResource(),
+		instance.Name, errors.New("the model history has been changed since it was loaded"))
+}
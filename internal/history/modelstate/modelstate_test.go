@@ -0,0 +1,583 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelstate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history/modelstate"
+)
+
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = jamiethompsonmev1alpha1.AddToScheme(scheme)
+	return scheme
+}
+
+var testInstance = &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+	TypeMeta: metav1.TypeMeta{
+		APIVersion: "jamiethompson.me/v1alpha1",
+		Kind:       "PredictiveHorizontalPodAutoscaler",
+	},
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "test",
+		Namespace: "default",
+		UID:       "test-uid",
+	},
+}
+
+var testOwnerReferences = []metav1.OwnerReference{{
+	APIVersion: "jamiethompson.me/v1alpha1",
+	Kind:       "PredictiveHorizontalPodAutoscaler",
+	Name:       "test",
+	UID:        "test-uid",
+}}
+
+func TestStore_Load(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description        string
+		expected           *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+		expectedVersion    string
+		expectedErr        error
+		expectedModelState *jamiethompsonmev1alpha1.PHPAModelStateSpec
+		expectedConfigMap  bool
+		expectedEvents     []string
+		objects            []client.Object
+	}{
+		{
+			description:        "Fail, invalid config map to migrate",
+			expected:           nil,
+			expectedVersion:    "",
+			expectedErr:        errors.New("failed to migrate config map: failed to parse config map data: invalid character 'i' looking for beginning of value"),
+			expectedModelState: nil,
+			expectedConfigMap:  true,
+			expectedEvents:     nil,
+			objects: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "predictive-horizontal-pod-autoscaler-test-data",
+						Namespace: "default",
+					},
+					Data: map[string]string{
+						"data": "invalid",
+					},
+				},
+			},
+		},
+		{
+			description: "Success, no model state or config map",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+			},
+			expectedVersion:    "",
+			expectedErr:        nil,
+			expectedModelState: nil,
+			expectedConfigMap:  false,
+			expectedEvents:     nil,
+			objects:            []client.Object{},
+		},
+		{
+			description: "Success, existing model state",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timeAt(0), Replicas: 3},
+							{Time: timeAt(15), Replicas: 4},
+						},
+					},
+				},
+			},
+			expectedVersion: "999",
+			expectedErr:     nil,
+			expectedModelState: &jamiethompsonmev1alpha1.PHPAModelStateSpec{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{
+					"linear": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory: &jamiethompsonmev1alpha1.CompactReplicaHistory{
+							Start:     *timeAt(0),
+							Intervals: []int64{0, 15},
+							Replicas:  []int32{3, 4},
+						},
+					},
+				},
+			},
+			expectedConfigMap: false,
+			expectedEvents:    nil,
+			objects: []client.Object{
+				&jamiethompsonmev1alpha1.PHPAModelState{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "default",
+					},
+					Spec: jamiethompsonmev1alpha1.PHPAModelStateSpec{
+						ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{
+							"linear": {
+								Type:              jamiethompsonmev1alpha1.TypeLinear,
+								SyncPeriodsPassed: 1,
+								ReplicaHistory: &jamiethompsonmev1alpha1.CompactReplicaHistory{
+									Start:     *timeAt(0),
+									Intervals: []int64{0, 15},
+									Replicas:  []int32{3, 4},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "Success, migrate config map",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timeAt(0), Replicas: 3},
+							{Time: timeAt(15), Replicas: 4},
+						},
+					},
+				},
+			},
+			expectedVersion: "1",
+			expectedErr:     nil,
+			expectedModelState: &jamiethompsonmev1alpha1.PHPAModelStateSpec{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{
+					"linear": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory: &jamiethompsonmev1alpha1.CompactReplicaHistory{
+							Start:     *timeAt(0),
+							Intervals: []int64{0, 15},
+							Replicas:  []int32{3, 4},
+						},
+					},
+				},
+			},
+			expectedConfigMap: false,
+			expectedEvents: []string{
+				"Normal MigratedModelHistory migrated the model history from config map predictive-horizontal-pod-autoscaler-test-data to model state test",
+			},
+			objects: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "predictive-horizontal-pod-autoscaler-test-data",
+						Namespace: "default",
+					},
+					Data: map[string]string{
+						"data": `{"modelHistories":{"linear":{"type":"Linear","syncPeriodsPassed":1,"replicaHistory":[{"time":"2023-07-01T12:00:00Z","replicas":3},{"time":"2023-07-01T12:00:15Z","replicas":4}]}}}`,
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(newScheme()).WithObjects(test.objects...).Build()
+			recorder := record.NewFakeRecorder(10)
+			store := &modelstate.Store{
+				Client:   fakeClient,
+				Recorder: recorder,
+			}
+			result, version, err := store.Load(context.Background(), testInstance)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("model history mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+			if !cmp.Equal(test.expectedVersion, version) {
+				t.Errorf("version mismatch (-want +got):\n%s", cmp.Diff(test.expectedVersion, version))
+			}
+
+			modelState := &jamiethompsonmev1alpha1.PHPAModelState{}
+			err = fakeClient.Get(context.Background(), types.NamespacedName{Name: "test", Namespace: "default"},
+				modelState)
+			if err != nil && !k8serrors.IsNotFound(err) {
+				t.Errorf("failed to get model state: %v", err)
+				return
+			}
+			var modelStateSpec *jamiethompsonmev1alpha1.PHPAModelStateSpec
+			if err == nil {
+				modelStateSpec = &modelState.Spec
+			}
+			if !cmp.Equal(test.expectedModelState, modelStateSpec) {
+				t.Errorf("model state mismatch (-want +got):\n%s", cmp.Diff(test.expectedModelState, modelStateSpec))
+			}
+
+			err = fakeClient.Get(context.Background(), types.NamespacedName{
+				Name:      "predictive-horizontal-pod-autoscaler-test-data",
+				Namespace: "default",
+			}, &corev1.ConfigMap{})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				t.Errorf("failed to get config map: %v", err)
+				return
+			}
+			if test.expectedConfigMap != (err == nil) {
+				t.Errorf("config map exists mismatch, expected %t", test.expectedConfigMap)
+			}
+
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			if !cmp.Equal(test.expectedEvents, events) {
+				t.Errorf("events mismatch (-want +got):\n%s", cmp.Diff(test.expectedEvents, events))
+			}
+		})
+	}
+}
+
+func TestStore_Read(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	expectedData := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+		ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+			"linear": {
+				Type:              jamiethompsonmev1alpha1.TypeLinear,
+				SyncPeriodsPassed: 1,
+				ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+					{Time: timeAt(0), Replicas: 3},
+					{Time: timeAt(15), Replicas: 4},
+				},
+			},
+		},
+	}
+
+	existingModelStateSpec := jamiethompsonmev1alpha1.PHPAModelStateSpec{
+		ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{
+			"linear": {
+				Type:              jamiethompsonmev1alpha1.TypeLinear,
+				SyncPeriodsPassed: 1,
+				ReplicaHistory: &jamiethompsonmev1alpha1.CompactReplicaHistory{
+					Start:     *timeAt(0),
+					Intervals: []int64{0, 15},
+					Replicas:  []int32{3, 4},
+				},
+			},
+		},
+	}
+
+	var tests = []struct {
+		description        string
+		expected           *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+		expectedErr        error
+		expectedModelState *jamiethompsonmev1alpha1.PHPAModelStateSpec
+		expectedConfigMap  bool
+		objects            []client.Object
+	}{
+		{
+			description:        "Fail, invalid config map",
+			expected:           nil,
+			expectedErr:        errors.New("failed to parse config map data: invalid character 'i' looking for beginning of value"),
+			expectedModelState: nil,
+			expectedConfigMap:  true,
+			objects: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "predictive-horizontal-pod-autoscaler-test-data",
+						Namespace: "default",
+					},
+					Data: map[string]string{
+						"data": "invalid",
+					},
+				},
+			},
+		},
+		{
+			description: "Success, no model state or config map",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+			},
+			expectedErr:        nil,
+			expectedModelState: nil,
+			expectedConfigMap:  false,
+			objects:            []client.Object{},
+		},
+		{
+			description:        "Success, existing model state",
+			expected:           expectedData,
+			expectedErr:        nil,
+			expectedModelState: &existingModelStateSpec,
+			expectedConfigMap:  false,
+			objects: []client.Object{
+				&jamiethompsonmev1alpha1.PHPAModelState{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "default",
+					},
+					Spec: existingModelStateSpec,
+				},
+			},
+		},
+		{
+			description:        "Success, config map read without migrating",
+			expected:           expectedData,
+			expectedErr:        nil,
+			expectedModelState: nil,
+			expectedConfigMap:  true,
+			objects: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "predictive-horizontal-pod-autoscaler-test-data",
+						Namespace: "default",
+					},
+					Data: map[string]string{
+						"data": `{"modelHistories":{"linear":{"type":"Linear","syncPeriodsPassed":1,"replicaHistory":[{"time":"2023-07-01T12:00:00Z","replicas":3},{"time":"2023-07-01T12:00:15Z","replicas":4}]}}}`,
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(newScheme()).WithObjects(test.objects...).Build()
+			recorder := record.NewFakeRecorder(10)
+			store := &modelstate.Store{
+				Client:   fakeClient,
+				Recorder: recorder,
+			}
+			result, err := store.Read(context.Background(), testInstance)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("model history mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+
+			modelState := &jamiethompsonmev1alpha1.PHPAModelState{}
+			err = fakeClient.Get(context.Background(), types.NamespacedName{Name: "test", Namespace: "default"},
+				modelState)
+			if err != nil && !k8serrors.IsNotFound(err) {
+				t.Errorf("failed to get model state: %v", err)
+				return
+			}
+			var modelStateSpec *jamiethompsonmev1alpha1.PHPAModelStateSpec
+			if err == nil {
+				modelStateSpec = &modelState.Spec
+			}
+			if !cmp.Equal(test.expectedModelState, modelStateSpec) {
+				t.Errorf("model state mismatch (-want +got):\n%s", cmp.Diff(test.expectedModelState, modelStateSpec))
+			}
+
+			err = fakeClient.Get(context.Background(), types.NamespacedName{
+				Name:      "predictive-horizontal-pod-autoscaler-test-data",
+				Namespace: "default",
+			}, &corev1.ConfigMap{})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				t.Errorf("failed to get config map: %v", err)
+				return
+			}
+			if test.expectedConfigMap != (err == nil) {
+				t.Errorf("config map exists mismatch, expected %t", test.expectedConfigMap)
+			}
+
+			if len(recorder.Events) != 0 {
+				t.Errorf("expected no events, got %d", len(recorder.Events))
+			}
+		})
+	}
+}
+
+func TestStore_Save(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	existingModelState := &jamiethompsonmev1alpha1.PHPAModelState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: jamiethompsonmev1alpha1.PHPAModelStateSpec{
+			ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{
+				"linear": {
+					Type:              jamiethompsonmev1alpha1.TypeLinear,
+					SyncPeriodsPassed: 1,
+				},
+			},
+		},
+	}
+
+	updatedData := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+		ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+			"linear": {
+				Type:              jamiethompsonmev1alpha1.TypeLinear,
+				SyncPeriodsPassed: 2,
+				ReplicaHistory:    []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			},
+		},
+	}
+
+	var tests = []struct {
+		description             string
+		expected                *jamiethompsonmev1alpha1.PHPAModelStateSpec
+		expectedErr             error
+		expectedOwnerReferences []metav1.OwnerReference
+		objects                 []client.Object
+		data                    *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+		version                 string
+	}{
+		{
+			description: "Fail, model state created since the model histories were loaded",
+			expected:    &existingModelState.Spec,
+			expectedErr: errors.New(`Operation cannot be fulfilled on phpamodelstates.jamiethompson.me "test": ` +
+				`the model history has been changed since it was loaded`),
+			expectedOwnerReferences: nil,
+			objects:                 []client.Object{existingModelState},
+			data:                    updatedData,
+			version:                 "",
+		},
+		{
+			description: "Fail, model state deleted since the model histories were loaded",
+			expected:    nil,
+			expectedErr: errors.New(`Operation cannot be fulfilled on phpamodelstates.jamiethompson.me "test": ` +
+				`the model history has been changed since it was loaded`),
+			expectedOwnerReferences: nil,
+			objects:                 []client.Object{},
+			data:                    updatedData,
+			version:                 "999",
+		},
+		{
+			description: "Fail, model state updated since the model histories were loaded",
+			expected:    &existingModelState.Spec,
+			expectedErr: errors.New(`Operation cannot be fulfilled on phpamodelstates.jamiethompson.me "test": ` +
+				`the model history has been changed since it was loaded`),
+			expectedOwnerReferences: nil,
+			objects:                 []client.Object{existingModelState},
+			data:                    updatedData,
+			version:                 "998",
+		},
+		{
+			description: "Create model state",
+			expected: &jamiethompsonmev1alpha1.PHPAModelStateSpec{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{
+					"linear": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory: &jamiethompsonmev1alpha1.CompactReplicaHistory{
+							Start:     *timeAt(0),
+							Intervals: []int64{0},
+							Replicas:  []int32{3},
+						},
+					},
+				},
+			},
+			expectedErr:             nil,
+			expectedOwnerReferences: testOwnerReferences,
+			objects:                 []client.Object{},
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timeAt(0), Replicas: 3},
+						},
+					},
+				},
+			},
+			version: "",
+		},
+		{
+			description: "Update existing model state",
+			expected: &jamiethompsonmev1alpha1.PHPAModelStateSpec{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelState{
+					"linear": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 2,
+					},
+				},
+			},
+			expectedErr:             nil,
+			expectedOwnerReferences: nil,
+			objects:                 []client.Object{existingModelState},
+			data:                    updatedData,
+			version:                 "999",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(newScheme()).WithObjects(test.objects...).Build()
+			store := &modelstate.Store{
+				Client:   fakeClient,
+				Recorder: record.NewFakeRecorder(10),
+			}
+			err := store.Save(context.Background(), testInstance, test.data, test.version)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+
+			result := &jamiethompsonmev1alpha1.PHPAModelState{}
+			err = fakeClient.Get(context.Background(), types.NamespacedName{Name: "test", Namespace: "default"}, result)
+			if err != nil && !k8serrors.IsNotFound(err) {
+				t.Errorf("failed to get model state: %v", err)
+				return
+			}
+			var resultSpec *jamiethompsonmev1alpha1.PHPAModelStateSpec
+			if err == nil {
+				resultSpec = &result.Spec
+			}
+
+			if !cmp.Equal(test.expectedOwnerReferences, result.OwnerReferences) {
+				t.Errorf("owner references mismatch (-want +got):\n%s", cmp.Diff(test.expectedOwnerReferences,
+					result.OwnerReferences))
+			}
+			if !cmp.Equal(test.expected, resultSpec) {
+				t.Errorf("model state mismatch (-want +got):\n%s", cmp.Diff(test.expected, resultSpec))
+			}
+		})
+	}
+}
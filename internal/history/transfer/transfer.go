@@ -0,0 +1,307 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package transfer provides reading, writing and validating of exported PHPA model histories, allowing model
+// histories to be moved between PHPAs.
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+)
+
+const (
+	// FormatJSON is the full model histories in JSON format, in the same format as they are stored in the model
+	// history config map
+	FormatJSON = "json"
+	// FormatCSV is the replica histories of the models in CSV format, with a header row naming the columns and a row
+	// for each replica count with the model name, model type, RFC 3339 time and replica count. Rows for the shared
+	// replica history have no model name or model type
+	FormatCSV = "csv"
+)
+
+const (
+	modelColumn    = "model"
+	typeColumn     = "type"
+	timeColumn     = "time"
+	replicasColumn = "replicas"
+)
+
+// Write writes the model histories in the format provided
+func Write(writer io.Writer, data *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, format string) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	case FormatCSV:
+		return writeCSV(writer, data)
+	}
+	return fmt.Errorf("unknown history format '%s'", format)
+}
+
+// Read reads model histories in the format provided
+func Read(reader io.Reader, format string) (*jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, error) {
+	switch format {
+	case FormatJSON:
+		decoder := json.NewDecoder(reader)
+		decoder.DisallowUnknownFields()
+		data := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{}
+		err := decoder.Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON history: %w", err)
+		}
+		if data.ModelHistories == nil {
+			data.ModelHistories = map[string]jamiethompsonmev1alpha1.ModelHistory{}
+		}
+		return data, nil
+	case FormatCSV:
+		return readCSV(reader)
+	}
+	return nil, fmt.Errorf("unknown history format '%s'", format)
+}
+
+// Validate checks that the model histories can be used by the PHPA, every model history must be for a model in the
+// PHPA's spec with the same type and every replica and metric history entry, including the shared history, must have
+// a time
+func Validate(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler,
+	data *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData) error {
+	for i, timestampedReplicas := range data.ReplicaHistory {
+		if timestampedReplicas.Time == nil {
+			return fmt.Errorf("invalid shared history, replica history entry %d has no time", i)
+		}
+		if timestampedReplicas.Replicas < 0 {
+			return fmt.Errorf("invalid shared history, replica history entry %d has negative replicas", i)
+		}
+	}
+
+	for i, timestampedMetrics := range data.MetricHistory {
+		if timestampedMetrics.Time == nil {
+			return fmt.Errorf("invalid shared history, metric history entry %d has no time", i)
+		}
+	}
+
+	models := map[string]jamiethompsonmev1alpha1.Model{}
+	for _, model := range instance.Spec.Models {
+		models[model.Name] = model
+	}
+
+	for _, name := range modelNames(data) {
+		modelHistory := data.ModelHistories[name]
+
+		model, exists := models[name]
+		if !exists {
+			return fmt.Errorf("invalid history for model '%s', no model with this name in the PHPA spec", name)
+		}
+
+		if modelHistory.Type != model.Type {
+			return fmt.Errorf("invalid history for model '%s', history is type '%s' but the model is type '%s'",
+				name, modelHistory.Type, model.Type)
+		}
+
+		for i, timestampedReplicas := range modelHistory.ReplicaHistory {
+			if timestampedReplicas.Time == nil {
+				return fmt.Errorf("invalid history for model '%s', replica history entry %d has no time", name, i)
+			}
+			if timestampedReplicas.Replicas < 0 {
+				return fmt.Errorf("invalid history for model '%s', replica history entry %d has negative replicas",
+					name, i)
+			}
+		}
+
+		for i, timestampedMetrics := range modelHistory.MetricHistory {
+			if timestampedMetrics.Time == nil {
+				return fmt.Errorf("invalid history for model '%s', metric history entry %d has no time", name, i)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Merge combines the imported model histories with the existing model histories, with the imported model histories
+// replacing the existing model history of the same model and any imported shared history replacing the existing
+// shared history. Unless overwrite is set an existing model history or shared history that has any replica or metric
+// history is not replaced and an error is returned instead
+func Merge(existing *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData,
+	imported *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData,
+	overwrite bool) (*jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, error) {
+	merged := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+		ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+		ReplicaHistory: existing.ReplicaHistory,
+		MetricHistory:  existing.MetricHistory,
+	}
+
+	if len(imported.ReplicaHistory) > 0 || len(imported.MetricHistory) > 0 {
+		if !overwrite && (len(existing.ReplicaHistory) > 0 || len(existing.MetricHistory) > 0) {
+			return nil, errors.New("the PHPA already has a stored shared history")
+		}
+		merged.ReplicaHistory = imported.ReplicaHistory
+		merged.MetricHistory = imported.MetricHistory
+	}
+
+	for name, modelHistory := range existing.ModelHistories {
+		merged.ModelHistories[name] = modelHistory
+	}
+
+	for _, name := range modelNames(imported) {
+		existingHistory, exists := existing.ModelHistories[name]
+		if exists && !overwrite && (len(existingHistory.ReplicaHistory) > 0 || len(existingHistory.MetricHistory) > 0) {
+			return nil, fmt.Errorf("model '%s' already has a stored history", name)
+		}
+		merged.ModelHistories[name] = imported.ModelHistories[name]
+	}
+
+	return merged, nil
+}
+
+func writeCSV(writer io.Writer, data *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData) error {
+	csvWriter := csv.NewWriter(writer)
+
+	err := csvWriter.Write([]string{modelColumn, typeColumn, timeColumn, replicasColumn})
+	if err != nil {
+		return err
+	}
+
+	err = writeCSVReplicaHistory(csvWriter, "", "", data.ReplicaHistory)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range modelNames(data) {
+		modelHistory := data.ModelHistories[name]
+		err := writeCSVReplicaHistory(csvWriter, name, modelHistory.Type, modelHistory.ReplicaHistory)
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func writeCSVReplicaHistory(csvWriter *csv.Writer, name string, modelType string,
+	replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) error {
+	for _, timestampedReplicas := range replicaHistory {
+		recordTime := ""
+		if timestampedReplicas.Time != nil {
+			recordTime = timestampedReplicas.Time.UTC().Format(time.RFC3339)
+		}
+		err := csvWriter.Write([]string{name, modelType, recordTime,
+			strconv.FormatInt(int64(timestampedReplicas.Replicas), 10)})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readCSV(reader io.Reader) (*jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData, error) {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV history: %w", err)
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("no header row provided in CSV history")
+	}
+
+	indexes := map[string]int{}
+	for i, column := range records[0] {
+		indexes[strings.TrimSpace(column)] = i
+	}
+
+	for _, column := range []string{modelColumn, typeColumn, timeColumn, replicasColumn} {
+		if _, exists := indexes[column]; !exists {
+			return nil, fmt.Errorf("CSV history must have a '%s' column", column)
+		}
+	}
+
+	data := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+		ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+	}
+	for i, record := range records[1:] {
+		row := i + 2
+
+		name := strings.TrimSpace(record[indexes[modelColumn]])
+		modelType := strings.TrimSpace(record[indexes[typeColumn]])
+
+		recordTime, err := time.Parse(time.RFC3339, strings.TrimSpace(record[indexes[timeColumn]]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid time on row %d: %w", row, err)
+		}
+
+		replicas, err := strconv.ParseInt(strings.TrimSpace(record[indexes[replicasColumn]]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid replicas on row %d: %w", row, err)
+		}
+
+		timestampedReplicas := jamiethompsonmev1alpha1.TimestampedReplicas{
+			Time:     &metav1.Time{Time: recordTime},
+			Replicas: int32(replicas),
+		}
+
+		// Rows without a model name are the shared replica history
+		if name == "" {
+			if modelType != "" {
+				return nil, fmt.Errorf("invalid type on row %d, shared history rows must not have a type", row)
+			}
+			data.ReplicaHistory = append(data.ReplicaHistory, timestampedReplicas)
+			continue
+		}
+
+		modelHistory, exists := data.ModelHistories[name]
+		if !exists {
+			modelHistory = jamiethompsonmev1alpha1.ModelHistory{
+				Type:              modelType,
+				SyncPeriodsPassed: 1,
+				ReplicaHistory:    []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			}
+		}
+
+		if modelHistory.Type != modelType {
+			return nil, fmt.Errorf("invalid type on row %d, model '%s' has already been given the type '%s'", row,
+				name, modelHistory.Type)
+		}
+
+		modelHistory.ReplicaHistory = append(modelHistory.ReplicaHistory, timestampedReplicas)
+		data.ModelHistories[name] = modelHistory
+	}
+
+	return data, nil
+}
+
+// modelNames returns the names of the models with histories in sorted order, so that output and errors are
+// consistent
+func modelNames(data *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData) []string {
+	names := make([]string, 0, len(data.ModelHistories))
+	for name := range data.ModelHistories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,612 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transfer_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history/transfer"
+)
+
+func timePtr(t time.Time) *metav1.Time {
+	return &metav1.Time{Time: t}
+}
+
+func TestWrite(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		description string
+		expected    string
+		expectedErr error
+		data        *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+		format      string
+	}{
+		{
+			description: "Fail, unknown format",
+			expected:    "",
+			expectedErr: errors.New("unknown history format 'xml'"),
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+			},
+			format: "xml",
+		},
+		{
+			description: "Success, JSON",
+			expected: `{
+  "modelHistories": {
+    "linear": {
+      "type": "Linear",
+      "syncPeriodsPassed": 1,
+      "replicaHistory": [
+        {
+          "time": "2023-07-01T12:00:00Z",
+          "replicas": 2
+        }
+      ],
+      "startTime": null
+    }
+  }
+}
+`,
+			expectedErr: nil,
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timePtr(start), Replicas: 2},
+						},
+					},
+				},
+			},
+			format: "json",
+		},
+		{
+			description: "Success, CSV, models sorted by name",
+			expected: `model,type,time,replicas
+holt-winters,HoltWinters,2023-07-01T12:00:00Z,4
+linear,Linear,2023-07-01T12:00:00Z,2
+linear,Linear,2023-07-01T12:01:00Z,3
+`,
+			expectedErr: nil,
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type: jamiethompsonmev1alpha1.TypeLinear,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timePtr(start), Replicas: 2},
+							{Time: timePtr(start.Add(time.Minute)), Replicas: 3},
+						},
+					},
+					"holt-winters": {
+						Type: jamiethompsonmev1alpha1.TypeHoltWinters,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timePtr(start), Replicas: 4},
+						},
+					},
+				},
+			},
+			format: "csv",
+		},
+		{
+			description: "Success, CSV, shared history before models",
+			expected: `model,type,time,replicas
+,,2023-07-01T12:00:00Z,1
+,,2023-07-01T12:01:00Z,2
+linear,Linear,2023-07-01T12:01:00Z,2
+`,
+			expectedErr: nil,
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type: jamiethompsonmev1alpha1.TypeLinear,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timePtr(start.Add(time.Minute)), Replicas: 2},
+						},
+					},
+				},
+				ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+					{Time: timePtr(start), Replicas: 1},
+					{Time: timePtr(start.Add(time.Minute)), Replicas: 2},
+				},
+			},
+			format: "csv",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			err := transfer.Write(buffer, test.data, test.format)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, buffer.String()) {
+				t.Errorf("output mismatch (-want +got):\n%s", cmp.Diff(test.expected, buffer.String()))
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		description string
+		expected    *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+		expectedErr error
+		input       string
+		format      string
+	}{
+		{
+			description: "Fail, unknown format",
+			expected:    nil,
+			expectedErr: errors.New("unknown history format 'xml'"),
+			input:       "",
+			format:      "xml",
+		},
+		{
+			description: "Fail, invalid JSON",
+			expected:    nil,
+			expectedErr: errors.New(`failed to parse JSON history: json: unknown field "unknown"`),
+			input:       `{"unknown": true}`,
+			format:      "json",
+		},
+		{
+			description: "Fail, CSV missing header row",
+			expected:    nil,
+			expectedErr: errors.New("no header row provided in CSV history"),
+			input:       "",
+			format:      "csv",
+		},
+		{
+			description: "Fail, CSV missing column",
+			expected:    nil,
+			expectedErr: errors.New("CSV history must have a 'type' column"),
+			input:       "model,time,replicas\n",
+			format:      "csv",
+		},
+		{
+			description: "Fail, CSV invalid time",
+			expected:    nil,
+			expectedErr: errors.New(`invalid time on row 2: parsing time "invalid" as "2006-01-02T15:04:05Z07:00": cannot parse "invalid" as "2006"`),
+			input:       "model,type,time,replicas\nlinear,Linear,invalid,2\n",
+			format:      "csv",
+		},
+		{
+			description: "Fail, CSV invalid replicas",
+			expected:    nil,
+			expectedErr: errors.New(`invalid replicas on row 2: strconv.ParseInt: parsing "two": invalid syntax`),
+			input:       "model,type,time,replicas\nlinear,Linear,2023-07-01T12:00:00Z,two\n",
+			format:      "csv",
+		},
+		{
+			description: "Fail, CSV model with multiple types",
+			expected:    nil,
+			expectedErr: errors.New("invalid type on row 3, model 'linear' has already been given the type 'Linear'"),
+			input: "model,type,time,replicas\nlinear,Linear,2023-07-01T12:00:00Z,2\n" +
+				"linear,HoltWinters,2023-07-01T12:01:00Z,3\n",
+			format: "csv",
+		},
+		{
+			description: "Fail, CSV shared history row with a type",
+			expected:    nil,
+			expectedErr: errors.New("invalid type on row 2, shared history rows must not have a type"),
+			input:       "model,type,time,replicas\n,Linear,2023-07-01T12:00:00Z,2\n",
+			format:      "csv",
+		},
+		{
+			description: "Success, JSON with no model histories",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+			},
+			expectedErr: nil,
+			input:       `{}`,
+			format:      "json",
+		},
+		{
+			description: "Success, JSON",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 2,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timePtr(start), Replicas: 2},
+						},
+					},
+				},
+			},
+			expectedErr: nil,
+			input: `{"modelHistories":{"linear":{"type":"Linear","syncPeriodsPassed":2,"replicaHistory":[` +
+				`{"time":"2023-07-01T12:00:00Z","replicas":2}]}}}`,
+			format: "json",
+		},
+		{
+			description: "Success, CSV with columns in any order",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timePtr(start), Replicas: 2},
+							{Time: timePtr(start.Add(time.Minute)), Replicas: 3},
+						},
+					},
+					"holt-winters": {
+						Type:              jamiethompsonmev1alpha1.TypeHoltWinters,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timePtr(start), Replicas: 4},
+						},
+					},
+				},
+			},
+			expectedErr: nil,
+			input: "time,replicas,model,type\n2023-07-01T12:00:00Z,2,linear,Linear\n" +
+				"2023-07-01T12:00:00Z,4,holt-winters,HoltWinters\n2023-07-01T12:01:00Z,3,linear,Linear\n",
+			format: "csv",
+		},
+		{
+			description: "Success, CSV with shared history",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type:              jamiethompsonmev1alpha1.TypeLinear,
+						SyncPeriodsPassed: 1,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timePtr(start.Add(time.Minute)), Replicas: 2},
+						},
+					},
+				},
+				ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+					{Time: timePtr(start), Replicas: 1},
+					{Time: timePtr(start.Add(time.Minute)), Replicas: 2},
+				},
+			},
+			expectedErr: nil,
+			input: "model,type,time,replicas\n,,2023-07-01T12:00:00Z,1\n,,2023-07-01T12:01:00Z,2\n" +
+				"linear,Linear,2023-07-01T12:01:00Z,2\n",
+			format: "csv",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := transfer.Read(strings.NewReader(test.input), test.format)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	instance := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+		Spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+			Models: []jamiethompsonmev1alpha1.Model{
+				{
+					Type: jamiethompsonmev1alpha1.TypeLinear,
+					Name: "linear",
+				},
+				{
+					Type: jamiethompsonmev1alpha1.TypeHoltWinters,
+					Name: "holt-winters",
+				},
+			},
+		},
+	}
+
+	var tests = []struct {
+		description string
+		expectedErr error
+		data        *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+	}{
+		{
+			description: "Fail, model not in spec",
+			expectedErr: errors.New("invalid history for model 'unknown', no model with this name in the PHPA spec"),
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"unknown": {
+						Type: jamiethompsonmev1alpha1.TypeLinear,
+					},
+				},
+			},
+		},
+		{
+			description: "Fail, model type mismatch",
+			expectedErr: errors.New("invalid history for model 'linear', history is type 'HoltWinters' but the model is type 'Linear'"),
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type: jamiethompsonmev1alpha1.TypeHoltWinters,
+					},
+				},
+			},
+		},
+		{
+			description: "Fail, replica history entry missing time",
+			expectedErr: errors.New("invalid history for model 'linear', replica history entry 1 has no time"),
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type: jamiethompsonmev1alpha1.TypeLinear,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timePtr(start), Replicas: 2},
+							{Replicas: 3},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "Fail, replica history entry negative replicas",
+			expectedErr: errors.New("invalid history for model 'linear', replica history entry 0 has negative replicas"),
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type: jamiethompsonmev1alpha1.TypeLinear,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timePtr(start), Replicas: -1},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "Fail, metric history entry missing time",
+			expectedErr: errors.New("invalid history for model 'linear', metric history entry 0 has no time"),
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": {
+						Type: jamiethompsonmev1alpha1.TypeLinear,
+						MetricHistory: []jamiethompsonmev1alpha1.TimestampedMetrics{
+							{Values: []int64{1}},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "Fail, shared replica history entry missing time",
+			expectedErr: errors.New("invalid shared history, replica history entry 0 has no time"),
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+					{Replicas: 2},
+				},
+			},
+		},
+		{
+			description: "Fail, shared metric history entry missing time",
+			expectedErr: errors.New("invalid shared history, metric history entry 0 has no time"),
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				MetricHistory: []jamiethompsonmev1alpha1.TimestampedMetrics{
+					{Values: []int64{1}},
+				},
+			},
+		},
+		{
+			description: "Success, subset of models",
+			expectedErr: nil,
+			data: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"holt-winters": {
+						Type: jamiethompsonmev1alpha1.TypeHoltWinters,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{Time: timePtr(start), Replicas: 2},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := transfer.Validate(instance, test.data)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	existingLinear := jamiethompsonmev1alpha1.ModelHistory{
+		Type: jamiethompsonmev1alpha1.TypeLinear,
+		ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+			{Time: timePtr(start), Replicas: 1},
+		},
+	}
+	importedLinear := jamiethompsonmev1alpha1.ModelHistory{
+		Type: jamiethompsonmev1alpha1.TypeLinear,
+		ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+			{Time: timePtr(start.Add(-time.Hour)), Replicas: 5},
+		},
+	}
+	importedHoltWinters := jamiethompsonmev1alpha1.ModelHistory{
+		Type: jamiethompsonmev1alpha1.TypeHoltWinters,
+		ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+			{Time: timePtr(start.Add(-time.Hour)), Replicas: 6},
+		},
+	}
+
+	var tests = []struct {
+		description string
+		expected    *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+		expectedErr error
+		existing    *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+		imported    *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData
+		overwrite   bool
+	}{
+		{
+			description: "Fail, model already has history",
+			expected:    nil,
+			expectedErr: errors.New("model 'linear' already has a stored history"),
+			existing: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": existingLinear,
+				},
+			},
+			imported: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": importedLinear,
+				},
+			},
+			overwrite: false,
+		},
+		{
+			description: "Fail, PHPA already has shared history",
+			expected:    nil,
+			expectedErr: errors.New("the PHPA already has a stored shared history"),
+			existing: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+				ReplicaHistory: existingLinear.ReplicaHistory,
+			},
+			imported: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+				ReplicaHistory: importedLinear.ReplicaHistory,
+			},
+			overwrite: false,
+		},
+		{
+			description: "Success, no imported shared history, existing shared history kept",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"holt-winters": importedHoltWinters,
+				},
+				ReplicaHistory: existingLinear.ReplicaHistory,
+			},
+			expectedErr: nil,
+			existing: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+				ReplicaHistory: existingLinear.ReplicaHistory,
+			},
+			imported: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"holt-winters": importedHoltWinters,
+				},
+			},
+			overwrite: false,
+		},
+		{
+			description: "Success, existing model without history replaced, other models kept",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear":       existingLinear,
+					"holt-winters": importedHoltWinters,
+				},
+			},
+			expectedErr: nil,
+			existing: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": existingLinear,
+					"holt-winters": {
+						Type:           jamiethompsonmev1alpha1.TypeHoltWinters,
+						ReplicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+					},
+				},
+			},
+			imported: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"holt-winters": importedHoltWinters,
+				},
+			},
+			overwrite: false,
+		},
+		{
+			description: "Success, overwrite existing history",
+			expected: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": importedLinear,
+				},
+				ReplicaHistory: importedLinear.ReplicaHistory,
+			},
+			expectedErr: nil,
+			existing: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": existingLinear,
+				},
+				ReplicaHistory: existingLinear.ReplicaHistory,
+			},
+			imported: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+				ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{
+					"linear": importedLinear,
+				},
+				ReplicaHistory: importedLinear.ReplicaHistory,
+			},
+			overwrite: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := transfer.Merge(test.existing, test.imported, test.overwrite)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
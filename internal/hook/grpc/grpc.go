@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package grpc handles interactions over gRPC
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	hookv1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/hook/v1alpha1"
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+)
+
+// Type grpc represents a gRPC request
+const Type = "grpc"
+
+// Execute represents a way to execute gRPC requests with values as parameters, calling services that implement the
+// Hook service. Connections are kept open and reused between requests to the same address.
+type Execute struct {
+	// DialOptions are additional options used when connecting to a gRPC service
+	DialOptions []gogrpc.DialOption
+
+	mu    sync.Mutex
+	conns map[connectionKey]*gogrpc.ClientConn
+}
+
+type connectionKey struct {
+	address string
+	tls     bool
+}
+
+// ExecuteWithValue executes a gRPC request with the value provided as parameter
+func (e *Execute) ExecuteWithValue(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+	if definition.GRPC == nil {
+		return "", fmt.Errorf("missing required 'grpc' configuration on hook definition")
+	}
+
+	conn, err := e.connection(definition.GRPC)
+	if err != nil {
+		return "", err
+	}
+
+	// Set up a context to provide a request timeout
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(definition.Timeout)*time.Millisecond)
+	defer cancel()
+
+	// Add metadata
+	if len(definition.GRPC.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(definition.GRPC.Metadata))
+	}
+
+	// Make request
+	resp, err := hookv1alpha1.NewHookClient(conn).Execute(ctx, &hookv1alpha1.ExecuteRequest{
+		Value: value,
+	})
+	if err != nil {
+		return "", fmt.Errorf("grpc request failed: %w", err)
+	}
+
+	return resp.GetValue(), nil
+}
+
+// GetType returns the grpc executer type
+func (e *Execute) GetType() string {
+	return Type
+}
+
+// Close closes all of the open connections
+func (e *Execute) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for key, conn := range e.conns {
+		conn.Close()
+		delete(e.conns, key)
+	}
+}
+
+// connection returns an existing connection to the address in the hook definition, or sets up a new connection if one
+// does not exist
+func (e *Execute) connection(hook *jamiethompsonmev1alpha1.GRPCHook) (*gogrpc.ClientConn, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := connectionKey{
+		address: hook.Address,
+		tls:     hook.TLS,
+	}
+
+	conn, exists := e.conns[key]
+	if exists {
+		return conn, nil
+	}
+
+	transportCredentials := insecure.NewCredentials()
+	if hook.TLS {
+		transportCredentials = credentials.NewClientTLSFromCert(nil, "")
+	}
+
+	// Dialing is non-blocking, this does not wait for the connection to be established
+	conn, err := gogrpc.Dial(hook.Address, append([]gogrpc.DialOption{
+		gogrpc.WithTransportCredentials(transportCredentials),
+	}, e.DialOptions...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.conns == nil {
+		e.conns = map[connectionKey]*gogrpc.ClientConn{}
+	}
+	e.conns[key] = conn
+
+	return conn, nil
+}
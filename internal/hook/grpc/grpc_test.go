@@ -0,0 +1,261 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	hookv1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/hook/v1alpha1"
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/hook/grpc"
+)
+
+type testHookServer struct {
+	hookv1alpha1.UnimplementedHookServer
+	ExecuteReactor func(ctx context.Context, req *hookv1alpha1.ExecuteRequest) (*hookv1alpha1.ExecuteResponse, error)
+}
+
+func (s *testHookServer) Execute(ctx context.Context, req *hookv1alpha1.ExecuteRequest) (*hookv1alpha1.ExecuteResponse, error) {
+	return s.ExecuteReactor(ctx, req)
+}
+
+// startTestServer starts a gRPC server using an in-memory listener, returning the dial options needed to connect to it
+func startTestServer(t *testing.T, server hookv1alpha1.HookServer) []gogrpc.DialOption {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := gogrpc.NewServer()
+	hookv1alpha1.RegisterHookServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	return []gogrpc.DialOption{
+		gogrpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+	}
+}
+
+func TestExecute_ExecuteWithValue(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+	var tests = []struct {
+		description string
+		expected    string
+		expectedErr error
+		definition  *jamiethompsonmev1alpha1.HookDefinition
+		value       string
+		server      hookv1alpha1.HookServer
+	}{
+		{
+			description: "Fail, missing gRPC configuration",
+			expected:    "",
+			expectedErr: errors.New(`missing required 'grpc' configuration on hook definition`),
+			definition: &jamiethompsonmev1alpha1.HookDefinition{
+				Type: "grpc",
+			},
+			value:  "test",
+			server: &testHookServer{},
+		},
+		{
+			description: "Fail, server returns error",
+			expected:    "",
+			expectedErr: errors.New(`grpc request failed: rpc error: code = Internal desc = prediction failed`),
+			definition: &jamiethompsonmev1alpha1.HookDefinition{
+				Type:    "grpc",
+				Timeout: 2500,
+				GRPC: &jamiethompsonmev1alpha1.GRPCHook{
+					Address: "bufnet",
+				},
+			},
+			value: "test",
+			server: &testHookServer{
+				ExecuteReactor: func(ctx context.Context, req *hookv1alpha1.ExecuteRequest) (*hookv1alpha1.ExecuteResponse, error) {
+					return nil, status.Error(codes.Internal, "prediction failed")
+				},
+			},
+		},
+		{
+			description: "Fail, timeout",
+			expected:    "",
+			expectedErr: errors.New(`grpc request failed: rpc error: code = DeadlineExceeded desc = context deadline exceeded`),
+			definition: &jamiethompsonmev1alpha1.HookDefinition{
+				Type:    "grpc",
+				Timeout: 10,
+				GRPC: &jamiethompsonmev1alpha1.GRPCHook{
+					Address: "bufnet",
+				},
+			},
+			value: "test",
+			server: &testHookServer{
+				ExecuteReactor: func(ctx context.Context, req *hookv1alpha1.ExecuteRequest) (*hookv1alpha1.ExecuteResponse, error) {
+					select {
+					case <-ctx.Done():
+					case <-time.After(time.Second):
+					}
+					return &hookv1alpha1.ExecuteResponse{Value: "too late"}, nil
+				},
+			},
+		},
+		{
+			description: "Success",
+			expected:    "success",
+			expectedErr: nil,
+			definition: &jamiethompsonmev1alpha1.HookDefinition{
+				Type:    "grpc",
+				Timeout: 2500,
+				GRPC: &jamiethompsonmev1alpha1.GRPCHook{
+					Address: "bufnet",
+				},
+			},
+			value: "test",
+			server: &testHookServer{
+				ExecuteReactor: func(ctx context.Context, req *hookv1alpha1.ExecuteRequest) (*hookv1alpha1.ExecuteResponse, error) {
+					if !cmp.Equal(req.GetValue(), "test") {
+						return nil, fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff("test", req.GetValue()))
+					}
+					return &hookv1alpha1.ExecuteResponse{Value: "success"}, nil
+				},
+			},
+		},
+		{
+			description: "Success, with metadata",
+			expected:    "success",
+			expectedErr: nil,
+			definition: &jamiethompsonmev1alpha1.HookDefinition{
+				Type:    "grpc",
+				Timeout: 2500,
+				GRPC: &jamiethompsonmev1alpha1.GRPCHook{
+					Address: "bufnet",
+					Metadata: map[string]string{
+						"authorization": "Bearer token",
+					},
+				},
+			},
+			value: "test",
+			server: &testHookServer{
+				ExecuteReactor: func(ctx context.Context, req *hookv1alpha1.ExecuteRequest) (*hookv1alpha1.ExecuteResponse, error) {
+					md, _ := metadata.FromIncomingContext(ctx)
+					authorization := md.Get("authorization")
+					if !cmp.Equal(authorization, []string{"Bearer token"}) {
+						return nil, fmt.Errorf("metadata mismatch (-want +got):\n%s", cmp.Diff([]string{"Bearer token"}, authorization))
+					}
+					return &hookv1alpha1.ExecuteResponse{Value: "success"}, nil
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			execute := &grpc.Execute{
+				DialOptions: startTestServer(t, test.server),
+			}
+			defer execute.Close()
+
+			result, err := execute.ExecuteWithValue(test.definition, test.value)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestExecute_ExecuteWithValue_ReusesConnection(t *testing.T) {
+	dials := 0
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := gogrpc.NewServer()
+	hookv1alpha1.RegisterHookServer(grpcServer, &testHookServer{
+		ExecuteReactor: func(ctx context.Context, req *hookv1alpha1.ExecuteRequest) (*hookv1alpha1.ExecuteResponse, error) {
+			return &hookv1alpha1.ExecuteResponse{Value: req.GetValue()}, nil
+		},
+	})
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	execute := &grpc.Execute{
+		DialOptions: []gogrpc.DialOption{
+			gogrpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+				dials++
+				return listener.DialContext(ctx)
+			}),
+		},
+	}
+	defer execute.Close()
+
+	definition := &jamiethompsonmev1alpha1.HookDefinition{
+		Type:    "grpc",
+		Timeout: 2500,
+		GRPC: &jamiethompsonmev1alpha1.GRPCHook{
+			Address: "bufnet",
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		value := fmt.Sprintf("request %d", i)
+		result, err := execute.ExecuteWithValue(definition, value)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !cmp.Equal(value, result) {
+			t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(value, result))
+		}
+	}
+
+	if !cmp.Equal(1, dials) {
+		t.Errorf("dials mismatch (-want +got):\n%s", cmp.Diff(1, dials))
+	}
+}
+
+func TestExecute_GetType(t *testing.T) {
+	var tests = []struct {
+		description string
+		expected    string
+		execute     *grpc.Execute
+	}{
+		{
+			description: "Return type",
+			expected:    "grpc",
+			execute:     &grpc.Execute{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result := test.execute.GetType()
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("type mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ limitations under the License.
 package hook
 
 import (
+	"fmt"
+
 	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
 )
 
@@ -26,3 +28,27 @@ type Executer interface {
 	ExecuteWithValue(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error)
 	GetType() string
 }
+
+// CombinedType is the type of the CombinedExecute, it is not a valid hook type itself
+const CombinedType = "combined"
+
+// CombinedExecute is used to route a hook to the appropriate executer based on the hook type
+// Should be initialised with available executers for it to use
+type CombinedExecute struct {
+	Executers []Executer
+}
+
+// ExecuteWithValue executes the hook with any executer that the CombinedExecute has been set up to use
+func (e *CombinedExecute) ExecuteWithValue(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+	for _, executer := range e.Executers {
+		if executer.GetType() == definition.Type {
+			return executer.ExecuteWithValue(definition, value)
+		}
+	}
+	return "", fmt.Errorf("unknown hook type '%s'", definition.Type)
+}
+
+// GetType returns the type of the CombinedExecute, "combined"
+func (e *CombinedExecute) GetType() string {
+	return CombinedType
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hook_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/hook"
+)
+
+func TestCombinedExecute_ExecuteWithValue(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description string
+		expected    string
+		expectedErr error
+		definition  *jamiethompsonmev1alpha1.HookDefinition
+		value       string
+		execute     *hook.CombinedExecute
+	}{
+		{
+			description: "Fail, no executers",
+			expected:    "",
+			expectedErr: errors.New("unknown hook type 'http'"),
+			definition: &jamiethompsonmev1alpha1.HookDefinition{
+				Type: "http",
+			},
+			value: "test",
+			execute: &hook.CombinedExecute{
+				Executers: []hook.Executer{},
+			},
+		},
+		{
+			description: "Fail, unknown hook type",
+			expected:    "",
+			expectedErr: errors.New("unknown hook type 'unknown'"),
+			definition: &jamiethompsonmev1alpha1.HookDefinition{
+				Type: "unknown",
+			},
+			value: "test",
+			execute: &hook.CombinedExecute{
+				Executers: []hook.Executer{
+					&fake.Execute{
+						GetTypeReactor: func() string {
+							return "http"
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "Fail, executer fails",
+			expected:    "",
+			expectedErr: errors.New("execute error"),
+			definition: &jamiethompsonmev1alpha1.HookDefinition{
+				Type: "grpc",
+			},
+			value: "test",
+			execute: &hook.CombinedExecute{
+				Executers: []hook.Executer{
+					&fake.Execute{
+						GetTypeReactor: func() string {
+							return "grpc"
+						},
+						ExecuteWithValueReactor: func(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+							return "", errors.New("execute error")
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "Success, routed to the matching executer",
+			expected:    "grpc result",
+			expectedErr: nil,
+			definition: &jamiethompsonmev1alpha1.HookDefinition{
+				Type: "grpc",
+			},
+			value: "test",
+			execute: &hook.CombinedExecute{
+				Executers: []hook.Executer{
+					&fake.Execute{
+						GetTypeReactor: func() string {
+							return "http"
+						},
+						ExecuteWithValueReactor: func(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+							return "http result", nil
+						},
+					},
+					&fake.Execute{
+						GetTypeReactor: func() string {
+							return "grpc"
+						},
+						ExecuteWithValueReactor: func(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+							return "grpc result", nil
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.execute.ExecuteWithValue(test.definition, test.value)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestCombinedExecute_GetType(t *testing.T) {
+	execute := &hook.CombinedExecute{}
+	result := execute.GetType()
+	if !cmp.Equal("combined", result) {
+		t.Errorf("type mismatch (-want +got):\n%s", cmp.Diff("combined", result))
+	}
+}
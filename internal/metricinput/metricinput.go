@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metricinput provides functionality for models that use raw metric values as their input, converting
+// gathered metrics into single values that can be forecast, and applying forecast values back onto gathered metrics so
+// they can be evaluated to calculate a replica count.
+package metricinput
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics/podmetrics"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+)
+
+// Values converts each gathered metric into a single raw value, in the same order as the gathered metrics. Resource
+// and pods metrics are converted into the total value across every pod, object and external metrics use the value
+// retrieved for the metric.
+func Values(gatheredMetrics []*metrics.Metric) ([]int64, error) {
+	values := make([]int64, len(gatheredMetrics))
+	for i, gatheredMetric := range gatheredMetrics {
+		switch gatheredMetric.Spec.Type {
+		case autoscalingv2.ResourceMetricSourceType:
+			if gatheredMetric.Resource == nil {
+				return nil, fmt.Errorf("missing gathered resource metric for metric %d", i)
+			}
+			values[i] = total(gatheredMetric.Resource.PodMetricsInfo)
+		case autoscalingv2.PodsMetricSourceType:
+			if gatheredMetric.Pods == nil {
+				return nil, fmt.Errorf("missing gathered pods metric for metric %d", i)
+			}
+			values[i] = total(gatheredMetric.Pods.PodMetricsInfo)
+		case autoscalingv2.ObjectMetricSourceType:
+			if gatheredMetric.Object == nil {
+				return nil, fmt.Errorf("missing gathered object metric for metric %d", i)
+			}
+			values[i] = value(gatheredMetric.Object.Current.Value, gatheredMetric.Object.Current.AverageValue)
+		case autoscalingv2.ExternalMetricSourceType:
+			if gatheredMetric.External == nil {
+				return nil, fmt.Errorf("missing gathered external metric for metric %d", i)
+			}
+			values[i] = value(gatheredMetric.External.Current.Value, gatheredMetric.External.Current.AverageValue)
+		default:
+			return nil, fmt.Errorf("unsupported metric source type '%s'", gatheredMetric.Spec.Type)
+		}
+	}
+	return values, nil
+}
+
+// Scale returns a copy of the gathered metrics with the value of each metric replaced with the forecast value
+// provided for it, the forecasts must be in the same order as the gathered metrics. Resource and pods metrics have
+// each pod's value scaled proportionally so that the total across every pod matches the forecast, keeping the same
+// pods so that the metrics can be evaluated in the same way as the gathered metrics.
+func Scale(gatheredMetrics []*metrics.Metric, forecasts []float64) ([]*metrics.Metric, error) {
+	if len(gatheredMetrics) != len(forecasts) {
+		return nil, fmt.Errorf("mismatch between number of gathered metrics (%d) and forecasts (%d)",
+			len(gatheredMetrics), len(forecasts))
+	}
+
+	scaledMetrics := make([]*metrics.Metric, len(gatheredMetrics))
+	for i, gatheredMetric := range gatheredMetrics {
+		// Negative forecasts can't be evaluated, treat them as zero
+		forecast := math.Max(forecasts[i], 0)
+
+		scaledMetric := *gatheredMetric
+		switch gatheredMetric.Spec.Type {
+		case autoscalingv2.ResourceMetricSourceType:
+			if gatheredMetric.Resource == nil {
+				return nil, fmt.Errorf("missing gathered resource metric for metric %d", i)
+			}
+			resource := *gatheredMetric.Resource
+			resource.PodMetricsInfo = scalePodMetrics(resource.PodMetricsInfo, forecast)
+			scaledMetric.Resource = &resource
+		case autoscalingv2.PodsMetricSourceType:
+			if gatheredMetric.Pods == nil {
+				return nil, fmt.Errorf("missing gathered pods metric for metric %d", i)
+			}
+			pods := *gatheredMetric.Pods
+			pods.PodMetricsInfo = scalePodMetrics(pods.PodMetricsInfo, forecast)
+			scaledMetric.Pods = &pods
+		case autoscalingv2.ObjectMetricSourceType:
+			if gatheredMetric.Object == nil {
+				return nil, fmt.Errorf("missing gathered object metric for metric %d", i)
+			}
+			object := *gatheredMetric.Object
+			object.Current.Value, object.Current.AverageValue = scaleValue(object.Current.Value,
+				object.Current.AverageValue, forecast)
+			scaledMetric.Object = &object
+		case autoscalingv2.ExternalMetricSourceType:
+			if gatheredMetric.External == nil {
+				return nil, fmt.Errorf("missing gathered external metric for metric %d", i)
+			}
+			external := *gatheredMetric.External
+			external.Current.Value, external.Current.AverageValue = scaleValue(external.Current.Value,
+				external.Current.AverageValue, forecast)
+			scaledMetric.External = &external
+		default:
+			return nil, fmt.Errorf("unsupported metric source type '%s'", gatheredMetric.Spec.Type)
+		}
+		scaledMetrics[i] = &scaledMetric
+	}
+	return scaledMetrics, nil
+}
+
+func total(podMetrics podmetrics.MetricsInfo) int64 {
+	total := int64(0)
+	for _, podMetric := range podMetrics {
+		total += podMetric.Value
+	}
+	return total
+}
+
+func value(value *int64, averageValue *int64) int64 {
+	if value != nil {
+		return *value
+	}
+	if averageValue != nil {
+		return *averageValue
+	}
+	return 0
+}
+
+func scalePodMetrics(podMetrics podmetrics.MetricsInfo, forecast float64) podmetrics.MetricsInfo {
+	scaled := make(podmetrics.MetricsInfo, len(podMetrics))
+	if len(podMetrics) == 0 {
+		return scaled
+	}
+
+	current := total(podMetrics)
+	for pod, podMetric := range podMetrics {
+		if current == 0 {
+			// No current usage to scale proportionally, so split the forecast evenly across every pod
+			podMetric.Value = int64(math.Round(forecast / float64(len(podMetrics))))
+		} else {
+			podMetric.Value = int64(math.Round(float64(podMetric.Value) * forecast / float64(current)))
+		}
+		scaled[pod] = podMetric
+	}
+	return scaled
+}
+
+func scaleValue(value *int64, averageValue *int64, forecast float64) (*int64, *int64) {
+	scaled := int64(math.Round(forecast))
+	if value != nil {
+		return &scaled, averageValue
+	}
+	if averageValue != nil {
+		return value, &scaled
+	}
+	return value, averageValue
+}
@@ -0,0 +1,337 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricinput_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics/external"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics/object"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics/podmetrics"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics/pods"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics/resource"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics/value"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/metricinput"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+)
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func TestValues(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description     string
+		expected        []int64
+		expectedErr     error
+		gatheredMetrics []*metrics.Metric
+	}{
+		{
+			description: "Fail unsupported metric source type",
+			expected:    nil,
+			expectedErr: errors.New("unsupported metric source type 'invalid'"),
+			gatheredMetrics: []*metrics.Metric{
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: "invalid",
+					},
+				},
+			},
+		},
+		{
+			description: "Fail missing gathered resource metric",
+			expected:    nil,
+			expectedErr: errors.New("missing gathered resource metric for metric 0"),
+			gatheredMetrics: []*metrics.Metric{
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.ResourceMetricSourceType,
+					},
+				},
+			},
+		},
+		{
+			description:     "Success, no metrics",
+			expected:        []int64{},
+			expectedErr:     nil,
+			gatheredMetrics: []*metrics.Metric{},
+		},
+		{
+			description: "Success, resource, pods, object and external metrics",
+			expected:    []int64{600, 30, 5000, 7000},
+			expectedErr: nil,
+			gatheredMetrics: []*metrics.Metric{
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.ResourceMetricSourceType,
+					},
+					Resource: &resource.Metric{
+						PodMetricsInfo: podmetrics.MetricsInfo{
+							"pod-1": podmetrics.Metric{Value: 200},
+							"pod-2": podmetrics.Metric{Value: 400},
+						},
+					},
+				},
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.PodsMetricSourceType,
+					},
+					Pods: &pods.Metric{
+						PodMetricsInfo: podmetrics.MetricsInfo{
+							"pod-1": podmetrics.Metric{Value: 10},
+							"pod-2": podmetrics.Metric{Value: 20},
+						},
+					},
+				},
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.ObjectMetricSourceType,
+					},
+					Object: &object.Metric{
+						Current: value.MetricValue{
+							Value: int64Ptr(5000),
+						},
+					},
+				},
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.ExternalMetricSourceType,
+					},
+					External: &external.Metric{
+						Current: value.MetricValue{
+							AverageValue: int64Ptr(7000),
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := metricinput.Values(test.gatheredMetrics)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("values mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestScale(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description     string
+		expected        []*metrics.Metric
+		expectedErr     error
+		gatheredMetrics []*metrics.Metric
+		forecasts       []float64
+	}{
+		{
+			description:     "Fail mismatched number of forecasts",
+			expected:        nil,
+			expectedErr:     errors.New("mismatch between number of gathered metrics (0) and forecasts (1)"),
+			gatheredMetrics: []*metrics.Metric{},
+			forecasts:       []float64{1},
+		},
+		{
+			description: "Fail unsupported metric source type",
+			expected:    nil,
+			expectedErr: errors.New("unsupported metric source type 'invalid'"),
+			gatheredMetrics: []*metrics.Metric{
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: "invalid",
+					},
+				},
+			},
+			forecasts: []float64{1},
+		},
+		{
+			description: "Success, resource metric scaled proportionally",
+			expected: []*metrics.Metric{
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.ResourceMetricSourceType,
+					},
+					Resource: &resource.Metric{
+						PodMetricsInfo: podmetrics.MetricsInfo{
+							"pod-1": podmetrics.Metric{Value: 400},
+							"pod-2": podmetrics.Metric{Value: 800},
+						},
+						ReadyPodCount: 2,
+					},
+				},
+			},
+			expectedErr: nil,
+			gatheredMetrics: []*metrics.Metric{
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.ResourceMetricSourceType,
+					},
+					Resource: &resource.Metric{
+						PodMetricsInfo: podmetrics.MetricsInfo{
+							"pod-1": podmetrics.Metric{Value: 200},
+							"pod-2": podmetrics.Metric{Value: 400},
+						},
+						ReadyPodCount: 2,
+					},
+				},
+			},
+			forecasts: []float64{1200},
+		},
+		{
+			description: "Success, pods metric with no current usage split evenly, negative forecast treated as zero",
+			expected: []*metrics.Metric{
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.PodsMetricSourceType,
+					},
+					Pods: &pods.Metric{
+						PodMetricsInfo: podmetrics.MetricsInfo{
+							"pod-1": podmetrics.Metric{Value: 50},
+							"pod-2": podmetrics.Metric{Value: 50},
+						},
+					},
+				},
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.PodsMetricSourceType,
+					},
+					Pods: &pods.Metric{
+						PodMetricsInfo: podmetrics.MetricsInfo{
+							"pod-1": podmetrics.Metric{Value: 0},
+						},
+					},
+				},
+			},
+			expectedErr: nil,
+			gatheredMetrics: []*metrics.Metric{
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.PodsMetricSourceType,
+					},
+					Pods: &pods.Metric{
+						PodMetricsInfo: podmetrics.MetricsInfo{
+							"pod-1": podmetrics.Metric{Value: 0},
+							"pod-2": podmetrics.Metric{Value: 0},
+						},
+					},
+				},
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.PodsMetricSourceType,
+					},
+					Pods: &pods.Metric{
+						PodMetricsInfo: podmetrics.MetricsInfo{
+							"pod-1": podmetrics.Metric{Value: 20},
+						},
+					},
+				},
+			},
+			forecasts: []float64{100, -5},
+		},
+		{
+			description: "Success, object and external metrics replaced",
+			expected: []*metrics.Metric{
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.ObjectMetricSourceType,
+					},
+					Object: &object.Metric{
+						Current: value.MetricValue{
+							Value: int64Ptr(6001),
+						},
+					},
+				},
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.ExternalMetricSourceType,
+					},
+					External: &external.Metric{
+						Current: value.MetricValue{
+							AverageValue: int64Ptr(9000),
+						},
+					},
+				},
+			},
+			expectedErr: nil,
+			gatheredMetrics: []*metrics.Metric{
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.ObjectMetricSourceType,
+					},
+					Object: &object.Metric{
+						Current: value.MetricValue{
+							Value: int64Ptr(5000),
+						},
+					},
+				},
+				{
+					Spec: autoscalingv2.MetricSpec{
+						Type: autoscalingv2.ExternalMetricSourceType,
+					},
+					External: &external.Metric{
+						Current: value.MetricValue{
+							AverageValue: int64Ptr(7000),
+						},
+					},
+				},
+			},
+			forecasts: []float64{6000.6, 9000},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			gatheredMetrics := make([]*metrics.Metric, len(test.gatheredMetrics))
+			for i, gatheredMetric := range test.gatheredMetrics {
+				copied := *gatheredMetric
+				gatheredMetrics[i] = &copied
+			}
+
+			result, err := metricinput.Scale(test.gatheredMetrics, test.forecasts)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("metrics mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+			if !cmp.Equal(gatheredMetrics, test.gatheredMetrics) {
+				t.Errorf("gathered metrics modified (-want +got):\n%s", cmp.Diff(gatheredMetrics, test.gatheredMetrics))
+			}
+		})
+	}
+}
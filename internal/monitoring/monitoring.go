@@ -0,0 +1,261 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package monitoring provides the Prometheus metrics that the PHPA exposes, these are registered with the
+// controller-runtime metrics registry so they are served on the manager's metrics endpoint alongside the
+// controller-runtime metrics.
+package monitoring
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/algorithm"
+)
+
+const namespace = "predictive_horizontal_pod_autoscaler"
+
+const (
+	labelNamespace = "namespace"
+	labelName      = "name"
+	labelModel     = "model"
+	labelAlgorithm = "algorithm"
+	labelHookType  = "hook_type"
+)
+
+var (
+	phpaLabels      = []string{labelNamespace, labelName}
+	modelLabels     = []string{labelNamespace, labelName, labelModel}
+	algorithmLabels = []string{labelNamespace, labelName, labelModel, labelAlgorithm}
+	hookLabels      = []string{labelNamespace, labelName, labelModel, labelHookType}
+)
+
+var (
+	calculatedReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "calculated_replicas",
+		Help:      "Replica count calculated from the current metrics, before any models are applied",
+	}, phpaLabels)
+	targetReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "target_replicas",
+		Help:      "Replica count the PHPA decided to scale to, after the decision type and scaling behavior are applied",
+	}, phpaLabels)
+	modelHistoryWriteErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "model_history_write_errors_total",
+		Help:      "Number of times the PHPA failed to write its model history",
+	}, phpaLabels)
+	modelPredictedReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "model_predicted_replicas",
+		Help:      "Replica count most recently predicted by the model",
+	}, modelLabels)
+	modelAccuracySamples = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "model_accuracy_samples",
+		Help:      "Number of scored predictions the model's accuracy is calculated from",
+	}, modelLabels)
+	modelMeanAbsoluteError = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "model_mean_absolute_error",
+		Help:      "Mean absolute difference between the model's recent predictions and the actual replica counts",
+	}, modelLabels)
+	modelMeanAbsolutePercentageError = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "model_mean_absolute_percentage_error",
+		Help:      "Mean absolute percentage difference between the model's recent predictions and the actual replica counts",
+	}, modelLabels)
+	modelBias = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "model_bias",
+		Help:      "Mean difference between the model's recent predictions and the actual replica counts",
+	}, modelLabels)
+	algorithmDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "algorithm_duration_seconds",
+		Help:      "Time taken to run the algorithm of a model to make a prediction",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, algorithmLabels)
+	algorithmTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "algorithm_timeouts_total",
+		Help:      "Number of times the algorithm of a model did not finish running within its timeout",
+	}, algorithmLabels)
+	hookDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "hook_duration_seconds",
+		Help:      "Time taken to execute the prediction or runtime tuning fetch hook of a model",
+		Buckets:   prometheus.DefBuckets,
+	}, hookLabels)
+	hookFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hook_failures_total",
+		Help:      "Number of times the prediction or runtime tuning fetch hook of a model failed",
+	}, hookLabels)
+)
+
+// phpaVecs are all of the metrics labelled by PHPA, used to remove the metrics of a deleted PHPA
+var phpaVecs = []*prometheus.MetricVec{
+	calculatedReplicas.MetricVec,
+	targetReplicas.MetricVec,
+	modelHistoryWriteErrors.MetricVec,
+	modelPredictedReplicas.MetricVec,
+	modelAccuracySamples.MetricVec,
+	modelMeanAbsoluteError.MetricVec,
+	modelMeanAbsolutePercentageError.MetricVec,
+	modelBias.MetricVec,
+	algorithmDuration.MetricVec,
+	algorithmTimeouts.MetricVec,
+	hookDuration.MetricVec,
+	hookFailures.MetricVec,
+}
+
+// runVecs are all of the metrics recorded when a model is run, these are labelled by the algorithm or hook type as
+// well as the model
+var runVecs = []*prometheus.MetricVec{
+	algorithmDuration.MetricVec,
+	algorithmTimeouts.MetricVec,
+	hookDuration.MetricVec,
+	hookFailures.MetricVec,
+}
+
+// accuracyVecs are all of the model accuracy metrics
+var accuracyVecs = []*prometheus.MetricVec{
+	modelAccuracySamples.MetricVec,
+	modelMeanAbsoluteError.MetricVec,
+	modelMeanAbsolutePercentageError.MetricVec,
+	modelBias.MetricVec,
+}
+
+func init() {
+	crmetrics.Registry.MustRegister(
+		calculatedReplicas,
+		targetReplicas,
+		modelHistoryWriteErrors,
+		modelPredictedReplicas,
+		modelAccuracySamples,
+		modelMeanAbsoluteError,
+		modelMeanAbsolutePercentageError,
+		modelBias,
+		algorithmDuration,
+		algorithmTimeouts,
+		hookDuration,
+		hookFailures,
+	)
+}
+
+// RecordCalculatedReplicas sets the replica count calculated from the current metrics for a PHPA
+func RecordCalculatedReplicas(phpaNamespace string, phpaName string, replicas int32) {
+	calculatedReplicas.WithLabelValues(phpaNamespace, phpaName).Set(float64(replicas))
+}
+
+// RecordTargetReplicas sets the replica count a PHPA decided to scale to
+func RecordTargetReplicas(phpaNamespace string, phpaName string, replicas int32) {
+	targetReplicas.WithLabelValues(phpaNamespace, phpaName).Set(float64(replicas))
+}
+
+// RecordModelHistoryWriteError counts a failure to write the model history of a PHPA
+func RecordModelHistoryWriteError(phpaNamespace string, phpaName string) {
+	modelHistoryWriteErrors.WithLabelValues(phpaNamespace, phpaName).Inc()
+}
+
+// RecordModelPrediction sets the replica count most recently predicted by a model of a PHPA
+func RecordModelPrediction(phpaNamespace string, phpaName string, model string, replicas int32) {
+	modelPredictedReplicas.WithLabelValues(phpaNamespace, phpaName, model).Set(float64(replicas))
+}
+
+// RecordAlgorithmRun records how long the algorithm of a model of a PHPA took to make a prediction, counting a timeout
+// if the error provided is caused by the algorithm timing out
+func RecordAlgorithmRun(phpaNamespace string, phpaName string, model string, algorithmType string,
+	duration time.Duration, err error) {
+	algorithmDuration.WithLabelValues(phpaNamespace, phpaName, model, algorithmType).Observe(duration.Seconds())
+	if errors.Is(err, algorithm.ErrTimeout) {
+		algorithmTimeouts.WithLabelValues(phpaNamespace, phpaName, model, algorithmType).Inc()
+	}
+}
+
+// RecordHookRun records how long the prediction or runtime tuning fetch hook of a model of a PHPA took to execute,
+// counting a failure if an error is provided
+func RecordHookRun(phpaNamespace string, phpaName string, model string, hookType string, duration time.Duration,
+	err error) {
+	hookDuration.WithLabelValues(phpaNamespace, phpaName, model, hookType).Observe(duration.Seconds())
+	if err != nil {
+		hookFailures.WithLabelValues(phpaNamespace, phpaName, model, hookType).Inc()
+	}
+}
+
+// RecordModelAccuracy sets the accuracy metrics for every model of a PHPA, removing the metrics of any models that no
+// longer have an accuracy
+func RecordModelAccuracy(phpaNamespace string, phpaName string, models []jamiethompsonmev1alpha1.ModelStatus) {
+	phpa := prometheus.Labels{
+		labelNamespace: phpaNamespace,
+		labelName:      phpaName,
+	}
+	for _, vec := range accuracyVecs {
+		vec.DeletePartialMatch(phpa)
+	}
+
+	for _, model := range models {
+		if model.Accuracy == nil {
+			continue
+		}
+
+		labels := prometheus.Labels{
+			labelNamespace: phpaNamespace,
+			labelName:      phpaName,
+			labelModel:     model.Name,
+		}
+
+		modelAccuracySamples.With(labels).Set(float64(model.Accuracy.Samples))
+		modelMeanAbsoluteError.With(labels).Set(model.Accuracy.MeanAbsoluteError)
+		modelBias.With(labels).Set(model.Accuracy.Bias)
+		if model.Accuracy.MeanAbsolutePercentageError != nil {
+			modelMeanAbsolutePercentageError.With(labels).Set(*model.Accuracy.MeanAbsolutePercentageError)
+		}
+	}
+}
+
+// DeleteModel removes the metrics for a model of a PHPA, used when the model is removed from the PHPA
+func DeleteModel(phpaNamespace string, phpaName string, model string) {
+	labels := prometheus.Labels{
+		labelNamespace: phpaNamespace,
+		labelName:      phpaName,
+		labelModel:     model,
+	}
+	modelPredictedReplicas.Delete(labels)
+	for _, vec := range accuracyVecs {
+		vec.Delete(labels)
+	}
+	for _, vec := range runVecs {
+		vec.DeletePartialMatch(labels)
+	}
+}
+
+// DeletePHPA removes all of the metrics for a PHPA, used when the PHPA is deleted
+func DeletePHPA(phpaNamespace string, phpaName string) {
+	labels := prometheus.Labels{
+		labelNamespace: phpaNamespace,
+		labelName:      phpaName,
+	}
+	for _, vec := range phpaVecs {
+		vec.DeletePartialMatch(labels)
+	}
+}
@@ -0,0 +1,313 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	dto "github.com/prometheus/client_model/go"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/algorithm"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/monitoring"
+)
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+// metricValue gathers the registered metrics and returns the value of the metric with the name and labels provided,
+// for histograms the number of observations is returned. If no metric matches nil is returned.
+func metricValue(t *testing.T, name string, labels map[string]string) *float64 {
+	families, err := crmetrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			if !labelsMatch(metric.GetLabel(), labels) {
+				continue
+			}
+
+			switch family.GetType() {
+			case dto.MetricType_GAUGE:
+				return float64Ptr(metric.GetGauge().GetValue())
+			case dto.MetricType_COUNTER:
+				return float64Ptr(metric.GetCounter().GetValue())
+			case dto.MetricType_HISTOGRAM:
+				return float64Ptr(float64(metric.GetHistogram().GetSampleCount()))
+			}
+		}
+	}
+
+	return nil
+}
+
+func labelsMatch(pairs []*dto.LabelPair, labels map[string]string) bool {
+	if len(pairs) != len(labels) {
+		return false
+	}
+	for _, pair := range pairs {
+		if labels[pair.GetName()] != pair.GetValue() {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRecordAlgorithmRun(t *testing.T) {
+	var tests = []struct {
+		description      string
+		expectedRuns     *float64
+		expectedTimeouts *float64
+		model            string
+		err              error
+	}{
+		{
+			description:      "Success",
+			expectedRuns:     float64Ptr(1),
+			expectedTimeouts: nil,
+			model:            "success",
+			err:              nil,
+		},
+		{
+			description:      "Fail, not a timeout",
+			expectedRuns:     float64Ptr(1),
+			expectedTimeouts: nil,
+			model:            "fail",
+			err:              errors.New("algorithm fail"),
+		},
+		{
+			description:      "Fail, timeout",
+			expectedRuns:     float64Ptr(1),
+			expectedTimeouts: float64Ptr(1),
+			model:            "timeout",
+			err:              fmt.Errorf("command 'algorithms/timeout.py' %w", algorithm.ErrTimeout),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			monitoring.RecordAlgorithmRun("default", "algorithm", test.model, "Linear", time.Second, test.err)
+
+			labels := map[string]string{
+				"namespace": "default",
+				"name":      "algorithm",
+				"model":     test.model,
+				"algorithm": "Linear",
+			}
+			runs := metricValue(t, "predictive_horizontal_pod_autoscaler_algorithm_duration_seconds", labels)
+			if !cmp.Equal(test.expectedRuns, runs) {
+				t.Errorf("runs mismatch (-want +got):\n%s", cmp.Diff(test.expectedRuns, runs))
+			}
+			timeouts := metricValue(t, "predictive_horizontal_pod_autoscaler_algorithm_timeouts_total", labels)
+			if !cmp.Equal(test.expectedTimeouts, timeouts) {
+				t.Errorf("timeouts mismatch (-want +got):\n%s", cmp.Diff(test.expectedTimeouts, timeouts))
+			}
+		})
+	}
+}
+
+func TestRecordHookRun(t *testing.T) {
+	var tests = []struct {
+		description        string
+		expectedExecutions *float64
+		expectedFailures   *float64
+		model              string
+		err                error
+	}{
+		{
+			description:        "Success",
+			expectedExecutions: float64Ptr(1),
+			expectedFailures:   nil,
+			model:              "success",
+			err:                nil,
+		},
+		{
+			description:        "Fail",
+			expectedExecutions: float64Ptr(1),
+			expectedFailures:   float64Ptr(1),
+			model:              "fail",
+			err:                errors.New("hook fail"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			monitoring.RecordHookRun("default", "hook", test.model, "http", time.Second, test.err)
+
+			labels := map[string]string{
+				"namespace": "default",
+				"name":      "hook",
+				"model":     test.model,
+				"hook_type": "http",
+			}
+			executions := metricValue(t, "predictive_horizontal_pod_autoscaler_hook_duration_seconds", labels)
+			if !cmp.Equal(test.expectedExecutions, executions) {
+				t.Errorf("executions mismatch (-want +got):\n%s", cmp.Diff(test.expectedExecutions, executions))
+			}
+			failures := metricValue(t, "predictive_horizontal_pod_autoscaler_hook_failures_total", labels)
+			if !cmp.Equal(test.expectedFailures, failures) {
+				t.Errorf("failures mismatch (-want +got):\n%s", cmp.Diff(test.expectedFailures, failures))
+			}
+		})
+	}
+}
+
+func TestDeletePHPA(t *testing.T) {
+	monitoring.RecordTargetReplicas("default", "deleted", 3)
+	monitoring.RecordAlgorithmRun("default", "deleted", "model", "Linear", time.Second,
+		fmt.Errorf("command 'algorithms/timeout.py' %w", algorithm.ErrTimeout))
+	monitoring.RecordHookRun("default", "deleted", "remote", "http", time.Second, errors.New("hook fail"))
+	monitoring.DeletePHPA("default", "deleted")
+
+	var tests = []struct {
+		description string
+		name        string
+		labels      map[string]string
+	}{
+		{
+			description: "Target replicas removed",
+			name:        "predictive_horizontal_pod_autoscaler_target_replicas",
+			labels:      map[string]string{"namespace": "default", "name": "deleted"},
+		},
+		{
+			description: "Algorithm duration removed",
+			name:        "predictive_horizontal_pod_autoscaler_algorithm_duration_seconds",
+			labels:      map[string]string{"namespace": "default", "name": "deleted", "model": "model", "algorithm": "Linear"},
+		},
+		{
+			description: "Algorithm timeouts removed",
+			name:        "predictive_horizontal_pod_autoscaler_algorithm_timeouts_total",
+			labels:      map[string]string{"namespace": "default", "name": "deleted", "model": "model", "algorithm": "Linear"},
+		},
+		{
+			description: "Hook duration removed",
+			name:        "predictive_horizontal_pod_autoscaler_hook_duration_seconds",
+			labels:      map[string]string{"namespace": "default", "name": "deleted", "model": "remote", "hook_type": "http"},
+		},
+		{
+			description: "Hook failures removed",
+			name:        "predictive_horizontal_pod_autoscaler_hook_failures_total",
+			labels:      map[string]string{"namespace": "default", "name": "deleted", "model": "remote", "hook_type": "http"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result := metricValue(t, test.name, test.labels)
+			if result != nil {
+				t.Errorf("expected metric to be removed, got %v", *result)
+			}
+		})
+	}
+}
+
+func TestRecordModelAccuracy(t *testing.T) {
+	monitoring.RecordModelPrediction("default", "accuracy", "removed", 3)
+	monitoring.RecordModelAccuracy("default", "accuracy", []jamiethompsonmev1alpha1.ModelStatus{
+		{
+			Name: "removed",
+			Accuracy: &jamiethompsonmev1alpha1.ModelAccuracy{
+				Samples: 1,
+			},
+		},
+	})
+	monitoring.RecordModelAccuracy("default", "accuracy", []jamiethompsonmev1alpha1.ModelStatus{
+		{
+			Name: "no-accuracy",
+		},
+		{
+			Name: "accurate",
+			Accuracy: &jamiethompsonmev1alpha1.ModelAccuracy{
+				Samples:           10,
+				MeanAbsoluteError: 1.5,
+				Bias:              -0.5,
+			},
+		},
+	})
+	monitoring.DeleteModel("default", "accuracy", "removed")
+
+	var tests = []struct {
+		description string
+		expected    *float64
+		name        string
+		model       string
+	}{
+		{
+			description: "Accuracy of previous model removed",
+			expected:    nil,
+			name:        "predictive_horizontal_pod_autoscaler_model_accuracy_samples",
+			model:       "removed",
+		},
+		{
+			description: "Prediction of deleted model removed",
+			expected:    nil,
+			name:        "predictive_horizontal_pod_autoscaler_model_predicted_replicas",
+			model:       "removed",
+		},
+		{
+			description: "No accuracy for model without accuracy",
+			expected:    nil,
+			name:        "predictive_horizontal_pod_autoscaler_model_accuracy_samples",
+			model:       "no-accuracy",
+		},
+		{
+			description: "Samples recorded",
+			expected:    float64Ptr(10),
+			name:        "predictive_horizontal_pod_autoscaler_model_accuracy_samples",
+			model:       "accurate",
+		},
+		{
+			description: "Mean absolute error recorded",
+			expected:    float64Ptr(1.5),
+			name:        "predictive_horizontal_pod_autoscaler_model_mean_absolute_error",
+			model:       "accurate",
+		},
+		{
+			description: "Bias recorded",
+			expected:    float64Ptr(-0.5),
+			name:        "predictive_horizontal_pod_autoscaler_model_bias",
+			model:       "accurate",
+		},
+		{
+			description: "No mean absolute percentage error recorded",
+			expected:    nil,
+			name:        "predictive_horizontal_pod_autoscaler_model_mean_absolute_percentage_error",
+			model:       "accurate",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result := metricValue(t, test.name, map[string]string{
+				"namespace": "default",
+				"name":      "accuracy",
+				"model":     test.model,
+			})
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
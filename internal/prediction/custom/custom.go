@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+)
+
+const (
+	defaultTimeout = 30000
+	defaultRuntime = jamiethompsonmev1alpha1.CustomRuntimePython
+)
+
+type customParameters struct {
+	Parameters     json.RawMessage                               `json:"parameters"`
+	ReplicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas `json:"replicaHistory"`
+}
+
+// Runner defines an algorithm runner, allowing algorithms to be run
+type AlgorithmRunner interface {
+	RunAlgorithmWithValue(algorithmPath string, value string, timeout int) (string, error)
+}
+
+// Predict provides logic for using a user supplied algorithm to make a prediction, only algorithms inside the
+// AllowedDirectory are run
+type Predict struct {
+	PythonRunner     AlgorithmRunner
+	ExecutableRunner AlgorithmRunner
+	AllowedDirectory string
+}
+
+// ResolvePath returns the path of a Custom model's algorithm with any symlinks resolved, returning an error if the
+// resolved path is not inside the allowed directory. Relative paths are relative to the allowed directory. If no
+// allowed directory is provided no algorithms are allowed, so Custom models cannot be used.
+func ResolvePath(allowedDirectory string, path string) (string, error) {
+	if allowedDirectory == "" {
+		return "", errors.New("custom models are disabled, no custom algorithm directory is configured")
+	}
+
+	directory, err := filepath.Abs(allowedDirectory)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve custom algorithm directory '%s': %w", allowedDirectory, err)
+	}
+	directory, err = filepath.EvalSymlinks(directory)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve custom algorithm directory '%s': %w", allowedDirectory, err)
+	}
+
+	algorithmPath := filepath.Clean(path)
+	if !filepath.IsAbs(algorithmPath) {
+		algorithmPath = filepath.Join(directory, algorithmPath)
+	}
+	algorithmPath, err = filepath.EvalSymlinks(algorithmPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path '%s': %w", path, err)
+	}
+
+	relative, err := filepath.Rel(directory, algorithmPath)
+	if err != nil || relative == "." || relative == ".." ||
+		strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path '%s' is outside of the custom algorithm directory '%s'", path, allowedDirectory)
+	}
+
+	return algorithmPath, nil
+}
+
+// GetPrediction runs the user supplied algorithm to predict what the replica count should be based on historical
+// evaluations
+func (p *Predict) GetPrediction(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
+	if model.Custom == nil {
+		return 0, errors.New("no Custom configuration provided for model")
+	}
+
+	if len(replicaHistory) == 0 {
+		return 0, errors.New("no evaluations provided for Custom model")
+	}
+
+	// The path is checked again here rather than relying on validation, the file system may have changed since
+	algorithmPath, err := ResolvePath(p.AllowedDirectory, model.Custom.Path)
+	if err != nil {
+		return 0, err
+	}
+
+	runtime := defaultRuntime
+	if model.Custom.Runtime != nil {
+		runtime = *model.Custom.Runtime
+	}
+
+	var runner AlgorithmRunner
+	switch runtime {
+	case jamiethompsonmev1alpha1.CustomRuntimePython:
+		runner = p.PythonRunner
+	case jamiethompsonmev1alpha1.CustomRuntimeExecutable:
+		runner = p.ExecutableRunner
+	default:
+		return 0, fmt.Errorf("unknown runtime '%s' for Custom model", runtime)
+	}
+
+	parameters := json.RawMessage("null")
+	if model.Custom.Parameters != nil && model.Custom.Parameters.Raw != nil {
+		parameters = model.Custom.Parameters.Raw
+	}
+
+	value, err := json.Marshal(customParameters{
+		Parameters:     parameters,
+		ReplicaHistory: replicaHistory,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal parameters for Custom model: %w", err)
+	}
+
+	timeout := defaultTimeout
+	if model.CalculationTimeout != nil {
+		timeout = *model.CalculationTimeout
+	}
+
+	output, err := runner.RunAlgorithmWithValue(algorithmPath, string(value), timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	prediction, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(prediction), nil
+}
+
+func (p *Predict) PruneHistory(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+	if model.Custom == nil {
+		return nil, errors.New("no Custom configuration provided for model")
+	}
+
+	if len(replicaHistory) < model.Custom.HistorySize {
+		return replicaHistory, nil
+	}
+
+	// Sort by date created, newest first
+	sort.Slice(replicaHistory, func(i, j int) bool {
+		return !replicaHistory[i].Time.Before(replicaHistory[j].Time)
+	})
+
+	// Remove oldest to fit into requirements
+	return replicaHistory[:model.Custom.HistorySize], nil
+}
+
+// GetType returns the type of the Prediction model
+func (p *Predict) GetType() string {
+	return jamiethompsonmev1alpha1.TypeCustom
+}
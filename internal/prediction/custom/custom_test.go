@@ -0,0 +1,507 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/custom"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestPredict_GetPrediction(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	// Set up an allowed directory of algorithms, with an algorithm outside of it and a symlink inside of it that
+	// points outside of it
+	directory := t.TempDir()
+	allowedDirectory := filepath.Join(directory, "algorithms")
+	err := os.Mkdir(allowedDirectory, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"algorithms/custom.py", "algorithms/custom", "outside.py"} {
+		err = os.WriteFile(filepath.Join(directory, path), []byte{}, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = os.Symlink(filepath.Join(directory, "outside.py"), filepath.Join(allowedDirectory, "escape.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolvedDirectory, err := filepath.EvalSymlinks(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pythonPath := filepath.Join(resolvedDirectory, "algorithms", "custom.py")
+	executablePath := filepath.Join(resolvedDirectory, "algorithms", "custom")
+
+	var tests = []struct {
+		description    string
+		expected       int32
+		expectedErr    error
+		predicter      *custom.Predict
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+	}{
+		{
+			description:    "Fail no Custom configuration",
+			expected:       0,
+			expectedErr:    errors.New("no Custom configuration provided for model"),
+			predicter:      &custom.Predict{},
+			model:          &jamiethompsonmev1alpha1.Model{},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+		},
+		{
+			description: "Fail no evaluations",
+			expected:    0,
+			expectedErr: errors.New("no evaluations provided for Custom model"),
+			predicter:   &custom.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeCustom,
+				Custom: &jamiethompsonmev1alpha1.Custom{
+					Path:        "custom.py",
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+		},
+		{
+			description: "Fail no custom algorithm directory",
+			expected:    0,
+			expectedErr: errors.New("custom models are disabled, no custom algorithm directory is configured"),
+			predicter:   &custom.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeCustom,
+				Custom: &jamiethompsonmev1alpha1.Custom{
+					Path:        "custom.py",
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Fail relative path outside of custom algorithm directory",
+			expected:    0,
+			expectedErr: fmt.Errorf("path '../outside.py' is outside of the custom algorithm directory '%s'",
+				allowedDirectory),
+			predicter: &custom.Predict{
+				AllowedDirectory: allowedDirectory,
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeCustom,
+				Custom: &jamiethompsonmev1alpha1.Custom{
+					Path:        "../outside.py",
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Fail absolute path outside of custom algorithm directory",
+			expected:    0,
+			expectedErr: fmt.Errorf("path '%s' is outside of the custom algorithm directory '%s'",
+				filepath.Join(directory, "outside.py"), allowedDirectory),
+			predicter: &custom.Predict{
+				AllowedDirectory: allowedDirectory,
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeCustom,
+				Custom: &jamiethompsonmev1alpha1.Custom{
+					Path:        filepath.Join(directory, "outside.py"),
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Fail symlink in custom algorithm directory points outside of it",
+			expected:    0,
+			expectedErr: fmt.Errorf("path 'escape.py' is outside of the custom algorithm directory '%s'",
+				allowedDirectory),
+			predicter: &custom.Predict{
+				AllowedDirectory: allowedDirectory,
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeCustom,
+				Custom: &jamiethompsonmev1alpha1.Custom{
+					Path:        "escape.py",
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Fail path is the custom algorithm directory",
+			expected:    0,
+			expectedErr: fmt.Errorf("path '.' is outside of the custom algorithm directory '%s'", allowedDirectory),
+			predicter: &custom.Predict{
+				AllowedDirectory: allowedDirectory,
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeCustom,
+				Custom: &jamiethompsonmev1alpha1.Custom{
+					Path:        ".",
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Fail unknown runtime",
+			expected:    0,
+			expectedErr: errors.New("unknown runtime 'invalid' for Custom model"),
+			predicter: &custom.Predict{
+				AllowedDirectory: allowedDirectory,
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeCustom,
+				Custom: &jamiethompsonmev1alpha1.Custom{
+					Path:        "custom.py",
+					Runtime:     stringPtr("invalid"),
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Fail execution of algorithm fails",
+			expected:    0,
+			expectedErr: errors.New("algorithm fail"),
+			predicter: &custom.Predict{
+				AllowedDirectory: allowedDirectory,
+				PythonRunner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return "", errors.New("algorithm fail")
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeCustom,
+				Custom: &jamiethompsonmev1alpha1.Custom{
+					Path:        "custom.py",
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Fail algorithm returns non-integer value",
+			expected:    0,
+			expectedErr: errors.New(`strconv.Atoi: parsing "invalid": invalid syntax`),
+			predicter: &custom.Predict{
+				AllowedDirectory: allowedDirectory,
+				PythonRunner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return "invalid", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeCustom,
+				Custom: &jamiethompsonmev1alpha1.Custom{
+					Path:        "custom.py",
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Success, default python runtime, no parameters, default timeout",
+			expected:    6,
+			expectedErr: nil,
+			predicter: &custom.Predict{
+				AllowedDirectory: allowedDirectory,
+				PythonRunner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						expectedValue := `{"parameters":null,"replicaHistory":[{"time":"2020-02-01T00:55:33Z","replicas":5}]}`
+						if !cmp.Equal(algorithmPath, pythonPath) {
+							return "", fmt.Errorf("algorithmPath mismatch (-want +got):\n%s", cmp.Diff(pythonPath, algorithmPath))
+						}
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						if !cmp.Equal(timeout, 30000) {
+							return "", fmt.Errorf("timeout mismatch (-want +got):\n%s", cmp.Diff(30000, timeout))
+						}
+						return "6", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeCustom,
+				Custom: &jamiethompsonmev1alpha1.Custom{
+					Path:        "custom.py",
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Success, executable runtime, parameters, custom timeout, trailing new line",
+			expected:    3,
+			expectedErr: nil,
+			predicter: &custom.Predict{
+				AllowedDirectory: allowedDirectory,
+				ExecutableRunner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						expectedValue := `{"parameters":{"seasons":[24,168],"trend":"add"},"replicaHistory":[{"time":"2020-02-01T00:55:33Z","replicas":5},{"time":"2020-02-01T00:56:33Z","replicas":4}]}`
+						if !cmp.Equal(algorithmPath, executablePath) {
+							return "", fmt.Errorf("algorithmPath mismatch (-want +got):\n%s", cmp.Diff(executablePath, algorithmPath))
+						}
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						if !cmp.Equal(timeout, 10000) {
+							return "", fmt.Errorf("timeout mismatch (-want +got):\n%s", cmp.Diff(10000, timeout))
+						}
+						return "3\n", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type:               jamiethompsonmev1alpha1.TypeCustom,
+				CalculationTimeout: intPtr(10000),
+				Custom: &jamiethompsonmev1alpha1.Custom{
+					Path:        "custom",
+					Runtime:     stringPtr(jamiethompsonmev1alpha1.CustomRuntimeExecutable),
+					HistorySize: 5,
+					Parameters: &apiextensionsv1.JSON{
+						Raw: []byte(`{"seasons":[24,168],"trend":"add"}`),
+					},
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+				{
+					Replicas: 4,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 56, 33, 0, time.UTC)},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.predicter.GetPrediction(test.model, test.replicaHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestModelPredict_PruneHistory(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description    string
+		expected       []jamiethompsonmev1alpha1.TimestampedReplicas
+		expectedErr    error
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+	}{
+		{
+			description:    "Fail no Custom configuration",
+			expected:       nil,
+			expectedErr:    errors.New("no Custom configuration provided for model"),
+			model:          &jamiethompsonmev1alpha1.Model{},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+		},
+		{
+			description: "Only 2 in history, max size 3",
+			expected: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 2,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(5) * time.Second)},
+				},
+				{
+					Replicas: 1,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(4) * time.Second)},
+				},
+			},
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				Custom: &jamiethompsonmev1alpha1.Custom{
+					HistorySize: 3,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 2,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(5) * time.Second)},
+				},
+				{
+					Replicas: 1,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(4) * time.Second)},
+				},
+			},
+		},
+		{
+			description: "2 too many, remove oldest 2",
+			expected: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 4,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(4) * time.Second)},
+				},
+				{
+					Replicas: 3,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(3) * time.Second)},
+				},
+			},
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				Custom: &jamiethompsonmev1alpha1.Custom{
+					HistorySize: 2,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 3,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(3) * time.Second)},
+				},
+				// START OLDEST
+				{
+					Replicas: 1,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(1) * time.Second)},
+				},
+				{
+					Replicas: 2,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(2) * time.Second)},
+				},
+				// END OLDEST
+				{
+					Replicas: 4,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(4) * time.Second)},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &custom.Predict{}
+			result, err := predicter.PruneHistory(test.model, test.replicaHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("remove IDs mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_GetType(t *testing.T) {
+	var tests = []struct {
+		description string
+		expected    string
+	}{
+		{
+			description: "Successful get type",
+			expected:    "Custom",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &custom.Predict{}
+			result := predicter.GetType()
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("type mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
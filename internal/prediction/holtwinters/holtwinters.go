@@ -19,17 +19,25 @@ package holtwinters
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"sort"
 	"strconv"
 
 	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/hook"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
 )
 
 const algorithmPath = "algorithms/holt_winters/holt_winters.py"
 
+// DefaultBackend is the backend used by Holt-Winters models that do not set a backend
+const DefaultBackend = jamiethompsonmev1alpha1.BackendPython
+
 const (
-	defaultTimeout = 30000
+	defaultTimeout              = 30000
+	defaultLookAheadSteps       = 1
+	defaultLookAheadAggregation = jamiethompsonmev1alpha1.LookAheadAggregationMaximum
 )
 
 // Runner defines an algorithm runner, allowing algorithms to be run
@@ -52,15 +60,32 @@ type holtWintersParametersParameters struct {
 	Seasonal             string    `json:"seasonal"`
 	SeasonalPeriods      int       `json:"seasonalPeriods"`
 	DampedTrend          *bool     `json:"dampedTrend,omitempty"`
+	DampingTrend         *float64  `json:"dampingTrend,omitempty"`
 	InitializationMethod *string   `json:"initializationMethod,omitempty"`
 	InitialLevel         *float64  `json:"initialLevel,omitempty"`
 	InitialTrend         *float64  `json:"initialTrend,omitempty"`
 	InitialSeasonal      *float64  `json:"initialSeasonal,omitempty"`
+	LookAheadSteps       int       `json:"lookAheadSteps"`
+	LookAheadAggregation string    `json:"lookAheadAggregation"`
+	ForecastDetail       bool      `json:"forecastDetail,omitempty"`
+	Tune                 bool      `json:"tune,omitempty"`
+}
+
+type holtWintersTuneResult struct {
+	Alpha float64 `json:"alpha"`
+	Beta  float64 `json:"beta"`
+	Gamma float64 `json:"gamma"`
+}
+
+type holtWintersForecast struct {
+	Forecast []float64 `json:"forecast"`
+	SSE      float64   `json:"sse"`
 }
 
 type runTimeTuningFetchHookRequest struct {
 	Model          jamiethompsonmev1alpha1.Model                 `json:"model"`
 	ReplicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas `json:"replicaHistory"`
+	MetricHistory  []jamiethompsonmev1alpha1.TimestampedValue    `json:"metricHistory,omitempty"`
 }
 
 type runTimeTuningFetchHookResult struct {
@@ -71,71 +96,323 @@ type runTimeTuningFetchHookResult struct {
 
 // GetPrediction uses holt winters to predict what the replica count should be based on historical evaluations
 func (p *Predict) GetPrediction(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
-	err := p.validate(model)
+	// Collect data for historical series
+	series := make([]float64, len(replicaHistory))
+	for i, timestampedReplica := range replicaHistory {
+		series[i] = float64(timestampedReplica.Replicas)
+	}
+
+	prediction, err := p.predict(model, series, &runTimeTuningFetchHookRequest{
+		Model:          *model,
+		ReplicaHistory: replicaHistory,
+	})
 	if err != nil {
 		return 0, err
 	}
 
-	// Statsmodels requires at least 2 * seasonal_periods to make a prediction with Holt Winters
-	// https://github.com/statsmodels/statsmodels/blob/77bb1d276c7d11bc8657497b4307aa7575c3e65c/statsmodels/tsa/exponential_smoothing/initialization.py#L57-L61
-	if len(replicaHistory) < 2*model.HoltWinters.SeasonalPeriods {
-		return 0, nil
+	return int32(math.Ceil(prediction)), nil
+}
+
+// GetPredictionInterval uses holt winters to predict what the replica count should be based on historical evaluations,
+// along with the bounds of the prediction interval at the provided confidence
+func (p *Predict) GetPredictionInterval(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, confidence float64) (*prediction.Interval, error) {
+	// Collect data for historical series
+	series := make([]float64, len(replicaHistory))
+	for i, timestampedReplica := range replicaHistory {
+		series[i] = float64(timestampedReplica.Replicas)
 	}
 
-	// Statsmodels requires at least 10 + 2 * (seasonal_periods // 2) to make a prediction with Holt Winters
-	// https://github.com/statsmodels/statsmodels/blob/77bb1d276c7d11bc8657497b4307aa7575c3e65c/statsmodels/tsa/exponential_smoothing/initialization.py#L66-L71
-	if len(replicaHistory) < 10+2*(model.HoltWinters.SeasonalPeriods/2) {
+	parameters, err := p.buildParameters(model, series, &runTimeTuningFetchHookRequest{
+		Model:          *model,
+		ReplicaHistory: replicaHistory,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if parameters == nil {
+		return &prediction.Interval{}, nil
+	}
+
+	backend := DefaultBackend
+	if model.Backend != nil {
+		backend = *model.Backend
+	}
+
+	var forecast []float64
+	var sse float64
+	switch backend {
+	case jamiethompsonmev1alpha1.BackendNative:
+		forecast, sse, err = nativeForecast(parameters, parameters.LookAheadSteps)
+	case jamiethompsonmev1alpha1.BackendPython:
+		forecast, sse, err = p.getPythonForecast(model, parameters)
+	default:
+		err = fmt.Errorf("unknown backend '%s' for Holt-Winters prediction", backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lower, upper := forecastInterval(parameters, forecast, sse, confidence)
+
+	return &prediction.Interval{
+		Prediction: int32(math.Ceil(aggregate(forecast, parameters.LookAheadAggregation))),
+		Lower:      int32(math.Floor(aggregate(lower, parameters.LookAheadAggregation))),
+		Upper:      int32(math.Ceil(aggregate(upper, parameters.LookAheadAggregation))),
+	}, nil
+}
+
+// GetMetricPrediction uses holt winters to forecast a metric value based on the metric's history
+func (p *Predict) GetMetricPrediction(model *jamiethompsonmev1alpha1.Model, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (float64, error) {
+	// Collect data for historical series
+	series := make([]float64, len(metricHistory))
+	for i, timestampedValue := range metricHistory {
+		series[i] = timestampedValue.Value
+	}
+
+	return p.predict(model, series, &runTimeTuningFetchHookRequest{
+		Model:         *model,
+		MetricHistory: metricHistory,
+	})
+}
+
+func (p *Predict) predict(model *jamiethompsonmev1alpha1.Model, series []float64,
+	hookRequest *runTimeTuningFetchHookRequest) (float64, error) {
+	parameters, err := p.buildParameters(model, series, hookRequest)
+	if err != nil {
+		return 0, err
+	}
+
+	if parameters == nil {
 		return 0, nil
 	}
 
+	backend := DefaultBackend
+	if model.Backend != nil {
+		backend = *model.Backend
+	}
+
+	switch backend {
+	case jamiethompsonmev1alpha1.BackendNative:
+		return p.getNativePrediction(parameters)
+	case jamiethompsonmev1alpha1.BackendPython:
+		return p.getPythonPrediction(model, parameters)
+	}
+
+	return 0, fmt.Errorf("unknown backend '%s' for Holt-Winters prediction", backend)
+}
+
+// buildParameters builds the parameters for the Holt-Winters calculation, fetching any runtime tuning values. If there
+// are not enough observations to make a prediction no parameters are returned.
+func (p *Predict) buildParameters(model *jamiethompsonmev1alpha1.Model, series []float64,
+	hookRequest *runTimeTuningFetchHookRequest) (*holtWintersParametersParameters, error) {
+	err := p.validate(model)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enoughObservations(model, len(series)) {
+		return nil, nil
+	}
+
+	model, err = p.fetchTuning(model, hookRequest)
+	if err != nil {
+		return nil, err
+	}
+
 	alpha := model.HoltWinters.Alpha
 	beta := model.HoltWinters.Beta
 	gamma := model.HoltWinters.Gamma
 
-	if model.HoltWinters.RuntimeTuningFetchHook != nil {
+	if alpha == nil {
+		return nil, errors.New("no alpha tuning value provided for Holt-Winters prediction")
+	}
+	if beta == nil {
+		return nil, errors.New("no beta tuning value provided for Holt-Winters prediction")
+	}
+	if gamma == nil {
+		return nil, errors.New("no gamma tuning value provided for Holt-Winters prediction")
+	}
 
-		// Convert request into JSON string
-		request, err := json.Marshal(&runTimeTuningFetchHookRequest{
-			Model:          *model,
-			ReplicaHistory: replicaHistory,
-		})
-		if err != nil {
-			// Should not occur
-			panic(err)
+	lookAheadSteps := defaultLookAheadSteps
+	lookAheadAggregation := defaultLookAheadAggregation
+	if model.HoltWinters.LookAhead != nil {
+		lookAheadSteps = model.HoltWinters.LookAhead.Steps
+		if model.HoltWinters.LookAhead.Aggregation != nil {
+			lookAheadAggregation = *model.HoltWinters.LookAhead.Aggregation
 		}
+	}
 
-		// Request runtime tuning values
-		hookResult, err := p.HookExecute.ExecuteWithValue(model.HoltWinters.RuntimeTuningFetchHook, string(request))
-		if err != nil {
-			return 0, err
-		}
+	parameters := holtWintersParametersParameters{
+		Series:               series,
+		Alpha:                *alpha,
+		Beta:                 *beta,
+		Gamma:                *gamma,
+		Trend:                model.HoltWinters.Trend,
+		Seasonal:             model.HoltWinters.Seasonal,
+		SeasonalPeriods:      model.HoltWinters.SeasonalPeriods,
+		DampedTrend:          model.HoltWinters.DampedTrend,
+		DampingTrend:         model.HoltWinters.DampingTrend,
+		InitializationMethod: model.HoltWinters.InitializationMethod,
+		InitialLevel:         model.HoltWinters.InitialLevel,
+		InitialTrend:         model.HoltWinters.InitialTrend,
+		InitialSeasonal:      model.HoltWinters.InitialSeasonal,
+		LookAheadSteps:       lookAheadSteps,
+		LookAheadAggregation: lookAheadAggregation,
+	}
 
-		// Parse result
-		var result runTimeTuningFetchHookResult
-		err = json.Unmarshal([]byte(hookResult), &result)
-		if err != nil {
-			return 0, err
-		}
+	return &parameters, nil
+}
 
-		if result.Alpha != nil {
-			alpha = result.Alpha
-		}
-		if result.Beta != nil {
-			beta = result.Beta
-		}
-		if result.Gamma != nil {
-			gamma = result.Gamma
-		}
+// FetchTuning runs the model's runtime tuning fetch hook, returning a copy of the model with the fetched tuning values
+// set and the hook removed. Models without a runtime tuning fetch hook are returned unchanged
+func (p *Predict) FetchTuning(model *jamiethompsonmev1alpha1.Model,
+	replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas,
+	metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (*jamiethompsonmev1alpha1.Model, error) {
+	err := p.validate(model)
+	if err != nil {
+		return nil, err
 	}
 
-	if alpha == nil {
-		return 0, errors.New("no alpha tuning value provided for Holt-Winters prediction")
+	return p.fetchTuning(model, &runTimeTuningFetchHookRequest{
+		Model:          *model,
+		ReplicaHistory: replicaHistory,
+		MetricHistory:  metricHistory,
+	})
+}
+
+// fetchTuning runs the model's runtime tuning fetch hook with the request provided, returning a copy of the model with
+// the fetched tuning values set and the hook removed
+func (p *Predict) fetchTuning(model *jamiethompsonmev1alpha1.Model,
+	hookRequest *runTimeTuningFetchHookRequest) (*jamiethompsonmev1alpha1.Model, error) {
+	if model.HoltWinters.RuntimeTuningFetchHook == nil {
+		return model, nil
 	}
-	if beta == nil {
-		return 0, errors.New("no beta tuning value provided for Holt-Winters prediction")
+
+	// Convert request into JSON string
+	request, err := json.Marshal(hookRequest)
+	if err != nil {
+		// Should not occur
+		panic(err)
 	}
-	if gamma == nil {
-		return 0, errors.New("no gamma tuning value provided for Holt-Winters prediction")
+
+	// Request runtime tuning values
+	hookResult, err := p.HookExecute.ExecuteWithValue(model.HoltWinters.RuntimeTuningFetchHook, string(request))
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse result
+	var result runTimeTuningFetchHookResult
+	err = json.Unmarshal([]byte(hookResult), &result)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedModel := model.DeepCopy()
+	fetchedModel.HoltWinters.RuntimeTuningFetchHook = nil
+	if result.Alpha != nil {
+		fetchedModel.HoltWinters.Alpha = result.Alpha
+	}
+	if result.Beta != nil {
+		fetchedModel.HoltWinters.Beta = result.Beta
+	}
+	if result.Gamma != nil {
+		fetchedModel.HoltWinters.Gamma = result.Gamma
+	}
+
+	return fetchedModel, nil
+}
+
+// getNativePrediction calculates the prediction in-process using the native Holt-Winters implementation
+func (p *Predict) getNativePrediction(parameters *holtWintersParametersParameters) (float64, error) {
+	forecast, _, err := nativeForecast(parameters, parameters.LookAheadSteps)
+	if err != nil {
+		return 0, err
+	}
+
+	return aggregate(forecast, parameters.LookAheadAggregation), nil
+}
+
+// getPythonPrediction calculates the prediction by running the statsmodels based Python algorithm
+func (p *Predict) getPythonPrediction(model *jamiethompsonmev1alpha1.Model,
+	parameters *holtWintersParametersParameters) (float64, error) {
+	serialized, err := json.Marshal(parameters)
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	timeout := defaultTimeout
+	if model.CalculationTimeout != nil {
+		timeout = *model.CalculationTimeout
+	}
+
+	value, err := p.Runner.RunAlgorithmWithValue(algorithmPath, string(serialized), timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	prediction, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(prediction), nil
+}
+
+// getPythonForecast calculates the forecast for every step and the sum of squared errors of the fitted model by running
+// the statsmodels based Python algorithm
+func (p *Predict) getPythonForecast(model *jamiethompsonmev1alpha1.Model,
+	parameters *holtWintersParametersParameters) ([]float64, float64, error) {
+	detailedParameters := *parameters
+	detailedParameters.ForecastDetail = true
+
+	serialized, err := json.Marshal(detailedParameters)
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	timeout := defaultTimeout
+	if model.CalculationTimeout != nil {
+		timeout = *model.CalculationTimeout
+	}
+
+	value, err := p.Runner.RunAlgorithmWithValue(algorithmPath, string(serialized), timeout)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result holtWintersForecast
+	err = json.Unmarshal([]byte(value), &result)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid response from Holt-Winters algorithm: %w", err)
+	}
+
+	if len(result.Forecast) != parameters.LookAheadSteps {
+		return nil, 0, fmt.Errorf("invalid response from Holt-Winters algorithm, expected %d forecast values but got %d",
+			parameters.LookAheadSteps, len(result.Forecast))
+	}
+
+	return result.Forecast, result.SSE, nil
+}
+
+// Tune fits the alpha, beta and gamma smoothing parameters to the replica history by running the statsmodels based
+// Python algorithm with the parameters optimised, if there are not enough observations no parameters are returned
+func (p *Predict) Tune(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (*jamiethompsonmev1alpha1.HoltWintersTunedParameters, error) {
+	err := p.validate(model)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := DefaultBackend
+	if model.Backend != nil {
+		backend = *model.Backend
+	}
+
+	if backend != jamiethompsonmev1alpha1.BackendPython {
+		return nil, fmt.Errorf("tuning is not supported by the '%s' backend for Holt-Winters", backend)
 	}
 
 	// Collect data for historical series
@@ -144,19 +421,24 @@ func (p *Predict) GetPrediction(model *jamiethompsonmev1alpha1.Model, replicaHis
 		series[i] = float64(timestampedReplica.Replicas)
 	}
 
+	if !enoughObservations(model, len(series)) {
+		return nil, nil
+	}
+
 	parameters, err := json.Marshal(holtWintersParametersParameters{
 		Series:               series,
-		Alpha:                *alpha,
-		Beta:                 *beta,
-		Gamma:                *gamma,
 		Trend:                model.HoltWinters.Trend,
 		Seasonal:             model.HoltWinters.Seasonal,
 		SeasonalPeriods:      model.HoltWinters.SeasonalPeriods,
 		DampedTrend:          model.HoltWinters.DampedTrend,
+		DampingTrend:         model.HoltWinters.DampingTrend,
 		InitializationMethod: model.HoltWinters.InitializationMethod,
 		InitialLevel:         model.HoltWinters.InitialLevel,
 		InitialTrend:         model.HoltWinters.InitialTrend,
 		InitialSeasonal:      model.HoltWinters.InitialSeasonal,
+		LookAheadSteps:       defaultLookAheadSteps,
+		LookAheadAggregation: defaultLookAheadAggregation,
+		Tune:                 true,
 	})
 	if err != nil {
 		// Should not occur, panic
@@ -170,15 +452,20 @@ func (p *Predict) GetPrediction(model *jamiethompsonmev1alpha1.Model, replicaHis
 
 	value, err := p.Runner.RunAlgorithmWithValue(algorithmPath, string(parameters), timeout)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	prediction, err := strconv.Atoi(value)
+	var result holtWintersTuneResult
+	err = json.Unmarshal([]byte(value), &result)
 	if err != nil {
-		return 0, err
+		return nil, fmt.Errorf("invalid response from Holt-Winters algorithm: %w", err)
 	}
 
-	return int32(prediction), nil
+	return &jamiethompsonmev1alpha1.HoltWintersTunedParameters{
+		Alpha: result.Alpha,
+		Beta:  result.Beta,
+		Gamma: result.Gamma,
+	}, nil
 }
 
 func (p *Predict) PruneHistory(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
@@ -207,11 +494,92 @@ func (p *Predict) PruneHistory(model *jamiethompsonmev1alpha1.Model, replicaHist
 	return replicaHistory, nil
 }
 
+// HasEnoughHistory returns if there are enough observations in the history for statsmodels to fit the model
+func (p *Predict) HasEnoughHistory(model *jamiethompsonmev1alpha1.Model, historyLength int) (bool, error) {
+	err := p.validate(model)
+	if err != nil {
+		return false, err
+	}
+
+	return enoughObservations(model, historyLength), nil
+}
+
 // GetType returns the type of the Prediction model
 func (p *Predict) GetType() string {
 	return jamiethompsonmev1alpha1.TypeHoltWinters
 }
 
+// forecastInterval calculates the lower and upper bounds of the prediction interval for each step of the forecast at
+// the provided confidence. The variance of each step is calculated using the analytical formula for additive error
+// Holt-Winters models, estimating the variance of the errors from the sum of squared errors of the fitted values; for
+// models with multiplicative components this is an approximation.
+// See https://otexts.com/fpp2/ets-forecasting.html for details.
+func forecastInterval(parameters *holtWintersParametersParameters, forecast []float64, sse float64,
+	confidence float64) ([]float64, []float64) {
+	variance := sse / float64(len(parameters.Series))
+	z := math.Sqrt2 * math.Erfinv(confidence)
+
+	phi := 1.0
+	if parameters.DampedTrend != nil && *parameters.DampedTrend && parameters.DampingTrend != nil {
+		phi = *parameters.DampingTrend
+	}
+
+	lower := make([]float64, len(forecast))
+	upper := make([]float64, len(forecast))
+
+	// Each step after the first adds the variance of the errors carried forward through the level, trend and
+	// seasonal components
+	errorMultiplier := 1.0
+	dampingSum := 0.0
+	for step := 1; step <= len(forecast); step++ {
+		margin := z * math.Sqrt(variance*errorMultiplier)
+		lower[step-1] = forecast[step-1] - margin
+		upper[step-1] = forecast[step-1] + margin
+
+		dampingSum += math.Pow(phi, float64(step))
+		c := parameters.Alpha * (1 + parameters.Beta*dampingSum)
+		if step%parameters.SeasonalPeriods == 0 {
+			c += parameters.Gamma
+		}
+		errorMultiplier += c * c
+	}
+
+	return lower, upper
+}
+
+// aggregate combines the values forecast over the look ahead horizon into a single value
+func aggregate(values []float64, aggregation string) float64 {
+	if aggregation == jamiethompsonmev1alpha1.LookAheadAggregationLast {
+		return values[len(values)-1]
+	}
+
+	result := values[0]
+	for _, value := range values[1:] {
+		if value > result {
+			result = value
+		}
+	}
+
+	return result
+}
+
+// enoughObservations returns if there are enough observations for statsmodels to fit the model
+func enoughObservations(model *jamiethompsonmev1alpha1.Model, observations int) bool {
+	// Statsmodels requires at least 2 * seasonal_periods to make a prediction with Holt Winters
+	// https://github.com/statsmodels/statsmodels/blob/77bb1d276c7d11bc8657497b4307aa7575c3e65c/statsmodels/tsa/exponential_smoothing/initialization.py#L57-L61
+	if observations < 2*model.HoltWinters.SeasonalPeriods {
+		return false
+	}
+
+	// Statsmodels requires at least 10 + 2 * (seasonal_periods // 2) to make a prediction with Holt Winters
+	// https://github.com/statsmodels/statsmodels/blob/77bb1d276c7d11bc8657497b4307aa7575c3e65c/statsmodels/tsa/exponential_smoothing/initialization.py#L66-L71
+	if observations < 10+2*(model.HoltWinters.SeasonalPeriods/2) {
+		return false
+	}
+
+	return true
+}
+
 func (p *Predict) validate(model *jamiethompsonmev1alpha1.Model) error {
 	if model.HoltWinters == nil {
 		return errors.New("no HoltWinters configuration provided for model")
@@ -221,5 +589,18 @@ func (p *Predict) validate(model *jamiethompsonmev1alpha1.Model) error {
 		return errors.New("no required 'trend' value provided for model")
 	}
 
+	if model.HoltWinters.LookAhead != nil {
+		if model.HoltWinters.LookAhead.Steps < 1 {
+			return fmt.Errorf("invalid look ahead steps %d provided for model, must be at least 1",
+				model.HoltWinters.LookAhead.Steps)
+		}
+
+		aggregation := model.HoltWinters.LookAhead.Aggregation
+		if aggregation != nil && *aggregation != jamiethompsonmev1alpha1.LookAheadAggregationMaximum &&
+			*aggregation != jamiethompsonmev1alpha1.LookAheadAggregationLast {
+			return fmt.Errorf("unknown look ahead aggregation '%s' provided for model", *aggregation)
+		}
+	}
+
 	return nil
 }
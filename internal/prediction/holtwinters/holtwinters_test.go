@@ -17,13 +17,17 @@ limitations under the License.
 package holtwinters_test
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/holtwinters"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -36,6 +40,24 @@ func float64Ptr(val float64) *float64 {
 	return &val
 }
 
+func boolPtr(val bool) *bool {
+	return &val
+}
+
+func stringPtr(val string) *string {
+	return &val
+}
+
+func replicaHistory(replicas ...int32) []jamiethompsonmev1alpha1.TimestampedReplicas {
+	history := []jamiethompsonmev1alpha1.TimestampedReplicas{}
+	for _, replica := range replicas {
+		history = append(history, jamiethompsonmev1alpha1.TimestampedReplicas{
+			Replicas: replica,
+		})
+	}
+	return history
+}
+
 func TestPredict_GetPrediction(t *testing.T) {
 	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
 		if x == nil || y == nil {
@@ -73,6 +95,37 @@ func TestPredict_GetPrediction(t *testing.T) {
 			},
 			[]jamiethompsonmev1alpha1.TimestampedReplicas{},
 		},
+		{
+			"Fail invalid look ahead steps",
+			0,
+			errors.New("invalid look ahead steps 0 provided for model, must be at least 1"),
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Trend: "add",
+					LookAhead: &jamiethompsonmev1alpha1.HoltWintersLookAhead{
+						Steps: 0,
+					},
+				},
+			},
+			[]jamiethompsonmev1alpha1.TimestampedReplicas{},
+		},
+		{
+			"Fail unknown look ahead aggregation",
+			0,
+			errors.New("unknown look ahead aggregation 'invalid' provided for model"),
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Trend: "add",
+					LookAhead: &jamiethompsonmev1alpha1.HoltWintersLookAhead{
+						Steps:       3,
+						Aggregation: stringPtr("invalid"),
+					},
+				},
+			},
+			[]jamiethompsonmev1alpha1.TimestampedReplicas{},
+		},
 		{
 			"Success, less than 2 * seasonal_periods observations",
 			0,
@@ -951,6 +1004,273 @@ func TestPredict_GetPrediction(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Fail, unknown backend",
+			0,
+			errors.New("unknown backend 'invalid' for Holt-Winters prediction"),
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr("invalid"),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.9),
+					SeasonalPeriods: 2,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14),
+		},
+		{
+			"Fail, native backend, unknown seasonal component",
+			0,
+			errors.New("unknown seasonal component type 'invalid'"),
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.9),
+					SeasonalPeriods: 2,
+					Trend:           "add",
+					Seasonal:        "invalid",
+				},
+			},
+			replicaHistory(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14),
+		},
+		{
+			"Fail, native backend, multiplicative with non-positive series",
+			0,
+			errors.New("invalid data provided, series must be strictly positive when using multiplicative trend or seasonal components"),
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.9),
+					SeasonalPeriods: 2,
+					Trend:           "mul",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14),
+		},
+		{
+			"Fail, native backend, unknown initialization method",
+			0,
+			errors.New("unknown initialization method 'invalid'"),
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:                float64Ptr(0.9),
+					Beta:                 float64Ptr(0.9),
+					Gamma:                float64Ptr(0.9),
+					SeasonalPeriods:      2,
+					Trend:                "add",
+					Seasonal:             "add",
+					InitializationMethod: stringPtr("invalid"),
+				},
+			},
+			replicaHistory(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14),
+		},
+		{
+			"Fail, native backend, known initialization method without initial level",
+			0,
+			errors.New("initialization method is 'known' but no initialLevel provided"),
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:                float64Ptr(0.9),
+					Beta:                 float64Ptr(0.9),
+					Gamma:                float64Ptr(0.9),
+					SeasonalPeriods:      2,
+					Trend:                "add",
+					Seasonal:             "add",
+					InitializationMethod: stringPtr("known"),
+				},
+			},
+			replicaHistory(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14),
+		},
+		{
+			// Matches the statsmodels result in algorithms/holt_winters/test_holt_winters.py
+			"Success, native backend, additive, 13 observations",
+			3,
+			nil,
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.3),
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 1),
+		},
+		{
+			// Matches the statsmodels result in algorithms/holt_winters/test_holt_winters.py
+			"Success, native backend, multiplicative trend, 15 observations",
+			1,
+			nil,
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.3),
+					Beta:            float64Ptr(0.3),
+					Gamma:           float64Ptr(0.9),
+					SeasonalPeriods: 3,
+					Trend:           "mul",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1),
+		},
+		{
+			// Matches the statsmodels result in algorithms/holt_winters/test_holt_winters.py
+			"Success, native backend, additive trend + multiplicative seasonal, legacy-heuristic, 19 observations",
+			6,
+			nil,
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:                float64Ptr(0.005),
+					Beta:                 float64Ptr(0.9),
+					Gamma:                float64Ptr(0.4),
+					SeasonalPeriods:      3,
+					Trend:                "add",
+					Seasonal:             "mul",
+					InitializationMethod: stringPtr("legacy-heuristic"),
+				},
+			},
+			replicaHistory(1, 1, 1, 1, 2, 1, 1, 3, 1, 1, 4, 1, 1, 5, 1, 1, 6, 1, 1),
+		},
+		{
+			"Success, native backend, heuristic, even seasonal periods",
+			2,
+			nil,
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:                float64Ptr(0.5),
+					Beta:                 float64Ptr(0.1),
+					Gamma:                float64Ptr(0.5),
+					SeasonalPeriods:      4,
+					Trend:                "additive",
+					Seasonal:             "multiplicative",
+					InitializationMethod: stringPtr("heuristic"),
+				},
+			},
+			replicaHistory(2, 4, 6, 4, 2, 4, 6, 4, 2, 4, 6, 4, 2, 4, 6, 4),
+		},
+		{
+			"Success, native backend, known initialization, damped trend",
+			6,
+			nil,
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:                float64Ptr(0.5),
+					Beta:                 float64Ptr(0.5),
+					Gamma:                float64Ptr(0.5),
+					SeasonalPeriods:      2,
+					Trend:                "add",
+					Seasonal:             "add",
+					DampedTrend:          boolPtr(true),
+					DampingTrend:         float64Ptr(0.5),
+					InitializationMethod: stringPtr("known"),
+					InitialLevel:         float64Ptr(1),
+					InitialTrend:         float64Ptr(1),
+					InitialSeasonal:      float64Ptr(0),
+				},
+			},
+			replicaHistory(1, 2, 3, 4, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5),
+		},
+		{
+			"Success, python backend, look ahead passed to algorithm",
+			5,
+			nil,
+			&holtwinters.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						expectedValue := `{"series":[1,2,3,4,5,6,7,8,9,10,11,12,13,14],"alpha":0.9,"beta":0.9,"gamma":0.9,"trend":"add","seasonal":"add","seasonalPeriods":2,"lookAheadSteps":4,"lookAheadAggregation":"last"}`
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						return "5", nil
+					},
+				},
+			},
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.9),
+					SeasonalPeriods: 2,
+					Trend:           "add",
+					Seasonal:        "add",
+					LookAhead: &jamiethompsonmev1alpha1.HoltWintersLookAhead{
+						Steps:       4,
+						Aggregation: stringPtr(jamiethompsonmev1alpha1.LookAheadAggregationLast),
+					},
+				},
+			},
+			replicaHistory(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14),
+		},
+		{
+			"Success, native backend, look ahead 3 steps, default maximum aggregation",
+			3,
+			nil,
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.3),
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+					LookAhead: &jamiethompsonmev1alpha1.HoltWintersLookAhead{
+						Steps: 3,
+					},
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 1),
+		},
+		{
+			"Success, native backend, look ahead 3 steps, last aggregation",
+			1,
+			nil,
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.3),
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+					LookAhead: &jamiethompsonmev1alpha1.HoltWintersLookAhead{
+						Steps:       3,
+						Aggregation: stringPtr(jamiethompsonmev1alpha1.LookAheadAggregationLast),
+					},
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 1),
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
@@ -966,6 +1286,793 @@ func TestPredict_GetPrediction(t *testing.T) {
 	}
 }
 
+func metricHistory(values ...float64) []jamiethompsonmev1alpha1.TimestampedValue {
+	history := []jamiethompsonmev1alpha1.TimestampedValue{}
+	for _, value := range values {
+		history = append(history, jamiethompsonmev1alpha1.TimestampedValue{
+			Value: value,
+		})
+	}
+	return history
+}
+
+func TestPredict_GetPredictionInterval(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description    string
+		expected       *prediction.Interval
+		expectedErr    error
+		predicter      *holtwinters.Predict
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+		confidence     float64
+	}{
+		{
+			"Fail no HoltWinters configuration",
+			nil,
+			errors.New("no HoltWinters configuration provided for model"),
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{},
+			[]jamiethompsonmev1alpha1.TimestampedReplicas{},
+			0.8,
+		},
+		{
+			"Success, less than required observations, return empty interval",
+			&prediction.Interval{},
+			nil,
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.3),
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1),
+			0.8,
+		},
+		{
+			"Fail, unknown backend",
+			nil,
+			errors.New("unknown backend 'invalid' for Holt-Winters prediction"),
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr("invalid"),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.3),
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 2),
+			0.8,
+		},
+		{
+			"Fail, python backend, fail to run holt winters algorithm",
+			nil,
+			errors.New("algorithm fail"),
+			&holtwinters.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return "", errors.New("algorithm fail")
+					},
+				},
+			},
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.3),
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 2),
+			0.8,
+		},
+		{
+			"Fail, python backend, invalid JSON response",
+			nil,
+			errors.New("invalid response from Holt-Winters algorithm: invalid character 'i' looking for beginning of value"),
+			&holtwinters.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return "invalid", nil
+					},
+				},
+			},
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.3),
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 2),
+			0.8,
+		},
+		{
+			"Fail, python backend, wrong number of forecast values",
+			nil,
+			errors.New("invalid response from Holt-Winters algorithm, expected 1 forecast values but got 2"),
+			&holtwinters.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return `{"forecast":[1,2],"sse":1}`, nil
+					},
+				},
+			},
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.3),
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 2),
+			0.8,
+		},
+		{
+			"Success, python backend",
+			&prediction.Interval{
+				Prediction: 5,
+				Lower:      4,
+				Upper:      6,
+			},
+			nil,
+			&holtwinters.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						expectedValue := `{"series":[1,3,1,1,3,1,1,3,1,1,3,1,2],"alpha":0.9,"beta":0.9,"gamma":0.3,"trend":"add","seasonal":"add","seasonalPeriods":3,"lookAheadSteps":1,"lookAheadAggregation":"maximum","forecastDetail":true}`
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						return `{"forecast":[4.71],"sse":1}`, nil
+					},
+				},
+			},
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.3),
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 2),
+			0.8,
+		},
+		{
+			"Success, native backend",
+			&prediction.Interval{
+				Prediction: 5,
+				Lower:      4,
+				Upper:      6,
+			},
+			nil,
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.3),
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 2),
+			0.8,
+		},
+		{
+			"Success, native backend, look ahead 3 steps, last aggregation",
+			&prediction.Interval{
+				Prediction: 5,
+				Lower:      3,
+				Upper:      6,
+			},
+			nil,
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:           float64Ptr(0.9),
+					Beta:            float64Ptr(0.9),
+					Gamma:           float64Ptr(0.3),
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+					LookAhead: &jamiethompsonmev1alpha1.HoltWintersLookAhead{
+						Steps:       3,
+						Aggregation: stringPtr(jamiethompsonmev1alpha1.LookAheadAggregationLast),
+					},
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 2),
+			0.8,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.predicter.GetPredictionInterval(test.model, test.replicaHistory, test.confidence)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_GetMetricPrediction(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description   string
+		expected      float64
+		expectedErr   error
+		predicter     *holtwinters.Predict
+		model         *jamiethompsonmev1alpha1.Model
+		metricHistory []jamiethompsonmev1alpha1.TimestampedValue
+	}{
+		{
+			"Fail no HoltWinters configuration",
+			0,
+			errors.New("no HoltWinters configuration provided for model"),
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{},
+			metricHistory(),
+		},
+		{
+			"Less than required observations, return 0",
+			0,
+			nil,
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					SeasonalPeriods: 2,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			metricHistory(1000, 2000, 1000),
+		},
+		{
+			"Success, python backend, runtime tuning hook provided metric history",
+			1500,
+			nil,
+			&holtwinters.Predict{
+				HookExecute: &fake.Execute{
+					ExecuteWithValueReactor: func(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+						var request struct {
+							ReplicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas `json:"replicaHistory"`
+							MetricHistory  []jamiethompsonmev1alpha1.TimestampedValue    `json:"metricHistory"`
+						}
+						err := json.Unmarshal([]byte(value), &request)
+						if err != nil {
+							return "", err
+						}
+						if request.ReplicaHistory != nil {
+							return "", fmt.Errorf("unexpected replica history provided: %v", request.ReplicaHistory)
+						}
+						expectedHistory := metricHistory(1000.5, 2000, 1000, 2000, 1000, 2000, 1000, 2000, 1000, 2000, 1000,
+							2000, 1000, 2000)
+						if !cmp.Equal(request.MetricHistory, expectedHistory) {
+							return "", fmt.Errorf("metric history mismatch (-want +got):\n%s",
+								cmp.Diff(expectedHistory, request.MetricHistory))
+						}
+						return `{"alpha":0.9,"beta":0.9,"gamma":0.9}`, nil
+					},
+				},
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						expectedValue := `{"series":[1000.5,2000,1000,2000,1000,2000,1000,2000,1000,2000,1000,2000,1000,2000],"alpha":0.9,"beta":0.9,"gamma":0.9,"trend":"add","seasonal":"add","seasonalPeriods":2,"lookAheadSteps":1,"lookAheadAggregation":"maximum"}`
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						return "1500", nil
+					},
+				},
+			},
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					SeasonalPeriods: 2,
+					Trend:           "add",
+					Seasonal:        "add",
+					RuntimeTuningFetchHook: &jamiethompsonmev1alpha1.HookDefinition{
+						Type:    "http",
+						Timeout: 2500,
+					},
+				},
+			},
+			metricHistory(1000.5, 2000, 1000, 2000, 1000, 2000, 1000, 2000, 1000, 2000, 1000, 2000, 1000, 2000),
+		},
+		{
+			"Success, native backend, value not rounded",
+			1000.5,
+			nil,
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:                float64Ptr(0.5),
+					Beta:                 float64Ptr(0.5),
+					Gamma:                float64Ptr(0.5),
+					SeasonalPeriods:      2,
+					Trend:                "add",
+					Seasonal:             "add",
+					InitializationMethod: stringPtr("known"),
+					InitialLevel:         float64Ptr(1000.5),
+					InitialTrend:         float64Ptr(0),
+					InitialSeasonal:      float64Ptr(0),
+				},
+			},
+			metricHistory(1000.5, 1000.5, 1000.5, 1000.5, 1000.5, 1000.5, 1000.5, 1000.5, 1000.5, 1000.5, 1000.5, 1000.5,
+				1000.5, 1000.5),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.predicter.GetMetricPrediction(test.model, test.metricHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result, cmpopts.EquateApprox(0, 1e-9)) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_FetchTuning(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	hook := &jamiethompsonmev1alpha1.HookDefinition{
+		Type:    "http",
+		Timeout: 2500,
+	}
+
+	var tests = []struct {
+		description    string
+		expected       *jamiethompsonmev1alpha1.Model
+		expectedErr    error
+		predicter      *holtwinters.Predict
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+		metricHistory  []jamiethompsonmev1alpha1.TimestampedValue
+	}{
+		{
+			description:    "Fail no HoltWinters configuration",
+			expected:       nil,
+			expectedErr:    errors.New("no HoltWinters configuration provided for model"),
+			predicter:      &holtwinters.Predict{},
+			model:          &jamiethompsonmev1alpha1.Model{},
+			replicaHistory: replicaHistory(),
+			metricHistory:  nil,
+		},
+		{
+			description: "Fail runtime tuning hook",
+			expected:    nil,
+			expectedErr: errors.New("fail to execute hook"),
+			predicter: &holtwinters.Predict{
+				HookExecute: &fake.Execute{
+					ExecuteWithValueReactor: func(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+						return "", errors.New("fail to execute hook")
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Trend:                  "add",
+					RuntimeTuningFetchHook: hook,
+				},
+			},
+			replicaHistory: replicaHistory(1, 2, 3),
+			metricHistory:  nil,
+		},
+		{
+			description: "Fail invalid runtime tuning hook response",
+			expected:    nil,
+			expectedErr: errors.New("invalid character 'i' looking for beginning of value"),
+			predicter: &holtwinters.Predict{
+				HookExecute: &fake.Execute{
+					ExecuteWithValueReactor: func(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+						return "invalid", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Trend:                  "add",
+					RuntimeTuningFetchHook: hook,
+				},
+			},
+			replicaHistory: replicaHistory(1, 2, 3),
+			metricHistory:  nil,
+		},
+		{
+			description: "Success, no runtime tuning hook, model unchanged",
+			expected: &jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha: float64Ptr(0.1),
+					Trend: "add",
+				},
+			},
+			expectedErr: nil,
+			predicter:   &holtwinters.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha: float64Ptr(0.1),
+					Trend: "add",
+				},
+			},
+			replicaHistory: replicaHistory(1, 2, 3),
+			metricHistory:  nil,
+		},
+		{
+			description: "Success, replica history, only values provided by the hook replaced and hook removed",
+			expected: &jamiethompsonmev1alpha1.Model{
+				Name: "model",
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha: float64Ptr(0.9),
+					Beta:  float64Ptr(0.2),
+					Gamma: float64Ptr(0.8),
+					Trend: "add",
+				},
+			},
+			expectedErr: nil,
+			predicter: &holtwinters.Predict{
+				HookExecute: &fake.Execute{
+					ExecuteWithValueReactor: func(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+						var request struct {
+							Model          jamiethompsonmev1alpha1.Model                 `json:"model"`
+							ReplicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas `json:"replicaHistory"`
+							MetricHistory  []jamiethompsonmev1alpha1.TimestampedValue    `json:"metricHistory"`
+						}
+						err := json.Unmarshal([]byte(value), &request)
+						if err != nil {
+							return "", err
+						}
+						if request.Model.Name != "model" {
+							return "", fmt.Errorf("unexpected model provided: %s", request.Model.Name)
+						}
+						if request.MetricHistory != nil {
+							return "", fmt.Errorf("unexpected metric history provided: %v", request.MetricHistory)
+						}
+						expectedHistory := replicaHistory(1, 2)
+						if !cmp.Equal(request.ReplicaHistory, expectedHistory) {
+							return "", fmt.Errorf("replica history mismatch (-want +got):\n%s",
+								cmp.Diff(expectedHistory, request.ReplicaHistory))
+						}
+						return `{"alpha":0.9,"gamma":0.8}`, nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Name: "model",
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha:                  float64Ptr(0.1),
+					Beta:                   float64Ptr(0.2),
+					Trend:                  "add",
+					RuntimeTuningFetchHook: hook,
+				},
+			},
+			replicaHistory: replicaHistory(1, 2),
+			metricHistory:  nil,
+		},
+		{
+			description: "Success, metric history",
+			expected: &jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Alpha: float64Ptr(0.9),
+					Beta:  float64Ptr(0.9),
+					Gamma: float64Ptr(0.9),
+					Trend: "add",
+				},
+			},
+			expectedErr: nil,
+			predicter: &holtwinters.Predict{
+				HookExecute: &fake.Execute{
+					ExecuteWithValueReactor: func(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+						var request struct {
+							ReplicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas `json:"replicaHistory"`
+							MetricHistory  []jamiethompsonmev1alpha1.TimestampedValue    `json:"metricHistory"`
+						}
+						err := json.Unmarshal([]byte(value), &request)
+						if err != nil {
+							return "", err
+						}
+						if request.ReplicaHistory != nil {
+							return "", fmt.Errorf("unexpected replica history provided: %v", request.ReplicaHistory)
+						}
+						expectedHistory := metricHistory(1000.5, 2000)
+						if !cmp.Equal(request.MetricHistory, expectedHistory) {
+							return "", fmt.Errorf("metric history mismatch (-want +got):\n%s",
+								cmp.Diff(expectedHistory, request.MetricHistory))
+						}
+						return `{"alpha":0.9,"beta":0.9,"gamma":0.9}`, nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					Trend:                  "add",
+					RuntimeTuningFetchHook: hook,
+				},
+			},
+			replicaHistory: nil,
+			metricHistory:  metricHistory(1000.5, 2000),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.predicter.FetchTuning(test.model, test.replicaHistory, test.metricHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_Tune(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description    string
+		expected       *jamiethompsonmev1alpha1.HoltWintersTunedParameters
+		expectedErr    error
+		predicter      *holtwinters.Predict
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+	}{
+		{
+			"Fail no HoltWinters configuration",
+			nil,
+			errors.New("no HoltWinters configuration provided for model"),
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{},
+			[]jamiethompsonmev1alpha1.TimestampedReplicas{},
+		},
+		{
+			"Fail, native backend",
+			nil,
+			errors.New("tuning is not supported by the 'native' backend for Holt-Winters"),
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 2),
+		},
+		{
+			"Success, less than required observations, no parameters",
+			nil,
+			nil,
+			&holtwinters.Predict{},
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1),
+		},
+		{
+			"Fail, fail to run holt winters algorithm",
+			nil,
+			errors.New("algorithm fail"),
+			&holtwinters.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return "", errors.New("algorithm fail")
+					},
+				},
+			},
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 2),
+		},
+		{
+			"Fail, holt winters algorithm returns invalid JSON",
+			nil,
+			errors.New("invalid response from Holt-Winters algorithm: invalid character 'i' looking for beginning of value"),
+			&holtwinters.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return "invalid", nil
+					},
+				},
+			},
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 2),
+		},
+		{
+			"Success",
+			&jamiethompsonmev1alpha1.HoltWintersTunedParameters{
+				Alpha: 0.5,
+				Beta:  0.1,
+				Gamma: 0.3,
+			},
+			nil,
+			&holtwinters.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						if algorithmPath != "algorithms/holt_winters/holt_winters.py" {
+							return "", fmt.Errorf("unexpected algorithm path '%s'", algorithmPath)
+						}
+						if timeout != 10000 {
+							return "", fmt.Errorf("unexpected timeout %d", timeout)
+						}
+						expectedValue := `{"series":[1,3,1,1,3,1,1,3,1,1,3,1,2],"alpha":0,"beta":0,"gamma":0,"trend":"add","seasonal":"add","seasonalPeriods":3,"lookAheadSteps":1,"lookAheadAggregation":"maximum","tune":true}`
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						return `{"alpha":0.5,"beta":0.1,"gamma":0.3}`, nil
+					},
+				},
+			},
+			&jamiethompsonmev1alpha1.Model{
+				CalculationTimeout: intPtr(10000),
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			replicaHistory(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1, 2),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.predicter.Tune(test.model, test.replicaHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_HasEnoughHistory(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description   string
+		expected      bool
+		expectedErr   error
+		model         *jamiethompsonmev1alpha1.Model
+		historyLength int
+	}{
+		{
+			"Fail no HoltWinters configuration",
+			false,
+			errors.New("no HoltWinters configuration provided for model"),
+			&jamiethompsonmev1alpha1.Model{},
+			12,
+		},
+		{
+			"11 in history, seasonal period 3, less than required observations",
+			false,
+			nil,
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			11,
+		},
+		{
+			"12 in history, seasonal period 3, enough observations",
+			true,
+			nil,
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					SeasonalPeriods: 3,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			12,
+		},
+		{
+			"13 in history, seasonal period 7, less than required observations",
+			false,
+			nil,
+			&jamiethompsonmev1alpha1.Model{
+				HoltWinters: &jamiethompsonmev1alpha1.HoltWinters{
+					SeasonalPeriods: 7,
+					Trend:           "add",
+					Seasonal:        "add",
+				},
+			},
+			13,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &holtwinters.Predict{}
+			result, err := predicter.HasEnoughHistory(test.model, test.historyLength)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("enough history mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
 func TestModelPredict_PruneHistory(t *testing.T) {
 	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
 		if x == nil || y == nil {
@@ -0,0 +1,364 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// This is a native implementation of Holt-Winters exponential smoothing, it is designed to produce the same forecasts
+// as the statsmodels ExponentialSmoothing model used by the Python algorithm when it is fitted with fixed smoothing
+// parameters (optimized=False). The implementation closely follows the statsmodels source code:
+// https://github.com/statsmodels/statsmodels/blob/v0.14.0/statsmodels/tsa/holtwinters/model.py
+// https://github.com/statsmodels/statsmodels/blob/v0.14.0/statsmodels/tsa/exponential_smoothing/initialization.py
+
+const (
+	componentAdditive       = "add"
+	componentMultiplicative = "mul"
+)
+
+const (
+	initializationEstimated       = "estimated"
+	initializationHeuristic       = "heuristic"
+	initializationKnown           = "known"
+	initializationLegacyHeuristic = "legacy-heuristic"
+)
+
+// heuristicObservations is the number of deseasonalised observations that the heuristic initialization uses to
+// calculate the initial level and trend
+const heuristicObservations = 10
+
+// nativeForecast uses Holt-Winters exponential smoothing to forecast the series for the provided number of steps,
+// returning a forecast value for each step and the sum of squared errors of the model's fitted values
+func nativeForecast(parameters *holtWintersParametersParameters, steps int) ([]float64, float64, error) {
+	trend, err := normaliseComponent("trend", parameters.Trend)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	seasonal, err := normaliseComponent("seasonal", parameters.Seasonal)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	m := parameters.SeasonalPeriods
+	y := parameters.Series
+	n := len(y)
+
+	if m < 1 {
+		return nil, 0, errors.New("invalid data provided, seasonalPeriods must be at least 1")
+	}
+
+	if n < 2*m {
+		return nil, 0, errors.New("invalid data provided, must be at least 2 * seasonalPeriods observations")
+	}
+
+	if n < 10+2*(m/2) {
+		return nil, 0, errors.New("invalid data provided, must be at least 10 + 2 * (seasonalPeriods / 2) observations")
+	}
+
+	if trend == componentMultiplicative || seasonal == componentMultiplicative {
+		for _, value := range y {
+			if value <= 0 {
+				return nil, 0, errors.New("invalid data provided, series must be strictly positive when using multiplicative trend or seasonal components")
+			}
+		}
+	}
+
+	initializationMethod := initializationEstimated
+	if parameters.InitializationMethod != nil {
+		initializationMethod = *parameters.InitializationMethod
+	}
+
+	var l0, b0 float64
+	var s0 []float64
+	switch initializationMethod {
+	case initializationEstimated, initializationHeuristic:
+		// The estimated method only differs from the heuristic method when the smoothing parameters are optimised,
+		// since they are fixed here the initial values are calculated using the heuristic
+		l0, b0, s0, err = initializeHeuristic(y, trend, seasonal, m)
+	case initializationLegacyHeuristic:
+		l0, b0, s0 = initializeLegacyHeuristic(y, trend, seasonal, m)
+	case initializationKnown:
+		l0, b0, s0, err = initializeKnown(parameters, m)
+	default:
+		err = fmt.Errorf("unknown initialization method '%s'", initializationMethod)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	phi := 1.0
+	if parameters.DampedTrend != nil && *parameters.DampedTrend {
+		if parameters.DampingTrend == nil {
+			return nil, 0, errors.New("invalid data provided, dampingTrend must be provided when dampedTrend is enabled")
+		}
+		phi = *parameters.DampingTrend
+	}
+
+	trended := func(level float64, trend float64) float64 { return level + trend }
+	detrend := func(level float64, previousLevel float64) float64 { return level - previousLevel }
+	dampen := func(trend float64, phi float64) float64 { return trend * phi }
+	if trend == componentMultiplicative {
+		trended = func(level float64, trend float64) float64 { return level * trend }
+		detrend = func(level float64, previousLevel float64) float64 { return level / previousLevel }
+		dampen = math.Pow
+	}
+
+	alpha := parameters.Alpha
+	beta := parameters.Beta
+	gamma := parameters.Gamma
+
+	levels := make([]float64, n+1)
+	trends := make([]float64, n+1)
+	seasons := make([]float64, n+m+steps+1)
+	levels[0] = l0
+	trends[0] = b0
+	copy(seasons, s0)
+
+	sse := 0.0
+	for i := 1; i <= n; i++ {
+		previous := trended(levels[i-1], dampen(trends[i-1], phi))
+
+		fitted := previous + seasons[i-1]
+		if seasonal == componentMultiplicative {
+			fitted = previous * seasons[i-1]
+		}
+		sse += (y[i-1] - fitted) * (y[i-1] - fitted)
+
+		if seasonal == componentMultiplicative {
+			levels[i] = alpha*y[i-1]/seasons[i-1] + (1-alpha)*previous
+			seasons[i+m-1] = gamma*y[i-1]/previous + (1-gamma)*seasons[i-1]
+		} else {
+			levels[i] = alpha*y[i-1] - alpha*seasons[i-1] + (1-alpha)*previous
+			seasons[i+m-1] = gamma*y[i-1] - gamma*previous + (1-gamma)*seasons[i-1]
+		}
+		trends[i] = beta*detrend(levels[i], levels[i-1]) + (1-beta)*dampen(trends[i-1], phi)
+	}
+
+	// Extend the seasonal component over the forecast in the same way as statsmodels HoltWintersResults._predict:
+	//   s[i + m - 1:] = [s[(i - 1) + j % m] for j in range(h + 1 + 1)]
+	// with i as the number of observations. At j=0 this overwrites the season updated by the last observation with
+	// the season from one cycle earlier, so forecast steps that are a multiple of m use the season from observation
+	// n-m rather than observation n, while every other step uses the latest season for its position. This quirk is
+	// kept so that the native backend gives the same forecasts as the python backend
+	for j := 0; j <= steps; j++ {
+		seasons[n+m-1+j] = seasons[n-1+j%m]
+	}
+
+	forecast := make([]float64, steps)
+	dampingSum := 0.0
+	for step := 1; step <= steps; step++ {
+		dampingSum += math.Pow(phi, float64(step))
+		value := trended(levels[n], dampen(trends[n], dampingSum))
+		if seasonal == componentMultiplicative {
+			value *= seasons[n+step-1]
+		} else {
+			value += seasons[n+step-1]
+		}
+		forecast[step-1] = value
+	}
+
+	return forecast, sse, nil
+}
+
+// initializeHeuristic calculates the initial level, trend and seasonal values by decomposing the series with a
+// centered moving average, following section 2.6 of Hyndman et al. (2008), Forecasting with Exponential Smoothing.
+func initializeHeuristic(y []float64, trend string, seasonal string, m int) (float64, float64, []float64, error) {
+	// Use at most 5 full seasonal cycles to calculate the initial values
+	cycles := len(y) / m
+	if cycles > 5 {
+		cycles = 5
+	}
+	series := y[:cycles*m]
+
+	movingAverage := centeredMovingAverage(series, m)
+
+	// Average the detrended values for each point in the season
+	initialSeasonal := make([]float64, m)
+	for i := 0; i < m; i++ {
+		total := 0.0
+		count := 0
+		for j := i; j < len(series); j += m {
+			if math.IsNaN(movingAverage[j]) {
+				continue
+			}
+			if seasonal == componentMultiplicative {
+				total += series[j] / movingAverage[j]
+			} else {
+				total += series[j] - movingAverage[j]
+			}
+			count++
+		}
+		initialSeasonal[i] = total / float64(count)
+	}
+
+	// Normalise the seasonal values
+	seasonalMean := mean(initialSeasonal)
+	for i := range initialSeasonal {
+		if seasonal == componentMultiplicative {
+			initialSeasonal[i] /= seasonalMean
+		} else {
+			initialSeasonal[i] -= seasonalMean
+		}
+	}
+
+	deseasonalised := []float64{}
+	for _, value := range movingAverage {
+		if !math.IsNaN(value) {
+			deseasonalised = append(deseasonalised, value)
+		}
+	}
+
+	if len(deseasonalised) < heuristicObservations {
+		return 0, 0, nil, fmt.Errorf("invalid data provided, heuristic initialization requires at least %d deseasonalised observations",
+			heuristicObservations)
+	}
+
+	// Fit a linear regression to the first deseasonalised observations, using the times 1 to 10, the initial level
+	// is the intercept (time 0) and the trend is the gradient
+	timeMean := float64(heuristicObservations+1) / 2
+	valueMean := mean(deseasonalised[:heuristicObservations])
+	covariance := 0.0
+	variance := 0.0
+	for i, value := range deseasonalised[:heuristicObservations] {
+		timeDiff := float64(i+1) - timeMean
+		covariance += timeDiff * (value - valueMean)
+		variance += timeDiff * timeDiff
+	}
+	gradient := covariance / variance
+	initialLevel := valueMean - gradient*timeMean
+
+	initialTrend := gradient
+	if trend == componentMultiplicative {
+		initialTrend = 1 + gradient/initialLevel
+	}
+
+	return initialLevel, initialTrend, initialSeasonal, nil
+}
+
+// initializeLegacyHeuristic calculates the initial level, trend and seasonal values using the heuristic used by
+// statsmodels versions prior to 0.12
+func initializeLegacyHeuristic(y []float64, trend string, seasonal string, m int) (float64, float64, []float64) {
+	levelTotal := 0.0
+	levelCount := 0
+	for i := 0; i < len(y); i += m {
+		levelTotal += y[i]
+		levelCount++
+	}
+	initialLevel := levelTotal / float64(levelCount)
+
+	lag := y[:m]
+	lead := y[m : 2*m]
+
+	var initialTrend float64
+	if trend == componentMultiplicative {
+		initialTrend = math.Exp((math.Log(mean(lead)) - math.Log(mean(lag))) / float64(m))
+	} else {
+		for i := range lead {
+			initialTrend += (lead[i] - lag[i]) / float64(m)
+		}
+		initialTrend /= float64(m)
+	}
+
+	initialSeasonal := make([]float64, m)
+	for i := range initialSeasonal {
+		if seasonal == componentMultiplicative {
+			initialSeasonal[i] = y[i] / initialLevel
+		} else {
+			initialSeasonal[i] = y[i] - initialLevel
+		}
+	}
+
+	return initialLevel, initialTrend, initialSeasonal
+}
+
+// initializeKnown uses the initial level, trend and seasonal values provided, the single initial seasonal value is
+// used for every point in the season
+func initializeKnown(parameters *holtWintersParametersParameters, m int) (float64, float64, []float64, error) {
+	if parameters.InitialLevel == nil {
+		return 0, 0, nil, errors.New("initialization method is 'known' but no initialLevel provided")
+	}
+
+	if parameters.InitialTrend == nil {
+		return 0, 0, nil, errors.New("initialization method is 'known' but no initialTrend provided")
+	}
+
+	if parameters.InitialSeasonal == nil {
+		return 0, 0, nil, errors.New("initialization method is 'known' but no initialSeasonal provided")
+	}
+
+	initialSeasonal := make([]float64, m)
+	for i := range initialSeasonal {
+		initialSeasonal[i] = *parameters.InitialSeasonal
+	}
+
+	return *parameters.InitialLevel, *parameters.InitialTrend, initialSeasonal, nil
+}
+
+// centeredMovingAverage calculates the centered moving average of the series with a window the size of the season,
+// if the season has an even length a 2 x m moving average is used to center it. Values at the edges of the series
+// without a full window are NaN.
+func centeredMovingAverage(series []float64, m int) []float64 {
+	movingAverage := make([]float64, len(series))
+	offset := (m - 1) / 2
+	for i := range series {
+		end := i + 1 + offset
+		start := end - m
+		if start < 0 || end > len(series) {
+			movingAverage[i] = math.NaN()
+			continue
+		}
+		movingAverage[i] = mean(series[start:end])
+	}
+
+	if m%2 != 0 {
+		return movingAverage
+	}
+
+	centered := make([]float64, len(series))
+	for i := range series {
+		if i+1 >= len(series) {
+			centered[i] = math.NaN()
+			continue
+		}
+		centered[i] = (movingAverage[i] + movingAverage[i+1]) / 2
+	}
+
+	return centered
+}
+
+// normaliseComponent converts a trend or seasonal component type to its short form, e.g. 'additive' to 'add'
+func normaliseComponent(name string, component string) (string, error) {
+	switch component {
+	case "add", "additive":
+		return componentAdditive, nil
+	case "mul", "multiplicative":
+		return componentMultiplicative, nil
+	}
+	return "", fmt.Errorf("unknown %s component type '%s'", name, component)
+}
+
+func mean(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
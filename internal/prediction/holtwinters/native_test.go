@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestNativeForecast(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	known := initializationKnown
+	boolPtr := func(val bool) *bool { return &val }
+	float64Ptr := func(val float64) *float64 { return &val }
+
+	// Alternating series with a season of 2, the last observation is 4 and the observation one season before it is 5
+	series := []float64{11, 9, 12, 8, 13, 7, 14, 6, 15, 5, 16, 4}
+
+	// The smoothing parameters of these cases are set to 0 or 1 so that the forecasts have a closed form, the expected
+	// forecasts are calculated from that closed form rather than by running the smoothing equations
+	var tests = []struct {
+		description string
+		expected    []float64
+		expectedErr error
+		parameters  *holtWintersParametersParameters
+	}{
+		{
+			description: "Fail, damped trend without a damping trend",
+			expected:    nil,
+			expectedErr: errors.New("invalid data provided, dampingTrend must be provided when dampedTrend is enabled"),
+			parameters: &holtWintersParametersParameters{
+				Series:               series,
+				Alpha:                1,
+				Trend:                "add",
+				Seasonal:             "add",
+				SeasonalPeriods:      2,
+				DampedTrend:          boolPtr(true),
+				InitializationMethod: &known,
+				InitialLevel:         float64Ptr(10),
+				InitialTrend:         float64Ptr(2),
+				InitialSeasonal:      float64Ptr(0),
+			},
+		},
+		{
+			// With alpha 1 the level is the last observation and with beta 0 the trend is only damped each
+			// observation, so the trend after n observations is b0 * phi^n and step h adds the trend multiplied by
+			// phi + phi^2 + ... + phi^h
+			description: "Damped additive trend",
+			expected: []float64{
+				4 + 2*math.Pow(0.9, 12)*0.9,
+				4 + 2*math.Pow(0.9, 12)*(0.9+0.81),
+				4 + 2*math.Pow(0.9, 12)*(0.9+0.81+0.729),
+			},
+			expectedErr: nil,
+			parameters: &holtWintersParametersParameters{
+				Series:               series,
+				Alpha:                1,
+				Beta:                 0,
+				Gamma:                0,
+				Trend:                "add",
+				Seasonal:             "add",
+				SeasonalPeriods:      2,
+				DampedTrend:          boolPtr(true),
+				DampingTrend:         float64Ptr(0.9),
+				InitializationMethod: &known,
+				InitialLevel:         float64Ptr(10),
+				InitialTrend:         float64Ptr(2),
+				InitialSeasonal:      float64Ptr(0),
+			},
+		},
+		{
+			// The multiplicative equivalent, the trend after n observations is b0^(phi^n) and step h multiplies the
+			// level by the trend raised to phi + phi^2 + ... + phi^h
+			description: "Damped multiplicative trend",
+			expected: []float64{
+				4 * math.Pow(1.5, math.Pow(0.9, 12)*0.9),
+				4 * math.Pow(1.5, math.Pow(0.9, 12)*(0.9+0.81)),
+				4 * math.Pow(1.5, math.Pow(0.9, 12)*(0.9+0.81+0.729)),
+			},
+			expectedErr: nil,
+			parameters: &holtWintersParametersParameters{
+				Series:               series,
+				Alpha:                1,
+				Beta:                 0,
+				Gamma:                0,
+				Trend:                "mul",
+				Seasonal:             "add",
+				SeasonalPeriods:      2,
+				DampedTrend:          boolPtr(true),
+				DampingTrend:         float64Ptr(0.9),
+				InitializationMethod: &known,
+				InitialLevel:         float64Ptr(10),
+				InitialTrend:         float64Ptr(1.5),
+				InitialSeasonal:      float64Ptr(0),
+			},
+		},
+		{
+			// With alpha 0 and no trend the level stays at 10 and with gamma 1 each season is the observation minus
+			// 10. Steps that are not a multiple of the season use the season of the last observation at the same
+			// position (16), steps that are a multiple of the season use the season of the observation one season
+			// before the last (5) rather than the last observation (4), matching statsmodels
+			description: "Additive seasonal",
+			expected:    []float64{16, 5, 16, 5},
+			expectedErr: nil,
+			parameters: &holtWintersParametersParameters{
+				Series:               series,
+				Alpha:                0,
+				Beta:                 0,
+				Gamma:                1,
+				Trend:                "add",
+				Seasonal:             "add",
+				SeasonalPeriods:      2,
+				InitializationMethod: &known,
+				InitialLevel:         float64Ptr(10),
+				InitialTrend:         float64Ptr(0),
+				InitialSeasonal:      float64Ptr(0),
+			},
+		},
+		{
+			// The multiplicative equivalent, each season is the observation divided by 10
+			description: "Multiplicative seasonal",
+			expected:    []float64{16, 5, 16, 5},
+			expectedErr: nil,
+			parameters: &holtWintersParametersParameters{
+				Series:               series,
+				Alpha:                0,
+				Beta:                 0,
+				Gamma:                1,
+				Trend:                "add",
+				Seasonal:             "mul",
+				SeasonalPeriods:      2,
+				InitializationMethod: &known,
+				InitialLevel:         float64Ptr(10),
+				InitialTrend:         float64Ptr(0),
+				InitialSeasonal:      float64Ptr(1),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, _, err := nativeForecast(test.parameters, len(test.expected))
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result, cmpopts.EquateApprox(0, 1e-9)) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
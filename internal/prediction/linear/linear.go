@@ -19,21 +19,42 @@ package linear
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"sort"
 	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
 
 	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
 )
 
 const (
 	defaultTimeout = 30000
+	defaultBackend = jamiethompsonmev1alpha1.BackendNative
 )
 
 const algorithmPath = "algorithms/linear_regression/linear_regression.py"
 
 type linearRegressionParameters struct {
-	LookAhead      int                                           `json:"lookAhead"`
-	ReplicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas `json:"replicaHistory"`
+	LookAhead          int                     `json:"lookAhead"`
+	ReplicaHistory     []linearRegressionValue `json:"replicaHistory"`
+	CurrentTime        metav1.Time             `json:"currentTime"`
+	IntervalConfidence *float64                `json:"intervalConfidence,omitempty"`
+}
+
+type linearRegressionInterval struct {
+	Prediction float64 `json:"prediction"`
+	Lower      float64 `json:"lower"`
+	Upper      float64 `json:"upper"`
+}
+
+type linearRegressionValue struct {
+	Time     *metav1.Time `json:"time"`
+	Replicas float64      `json:"replicas"`
 }
 
 // Config represents a linear regression prediction model configuration
@@ -50,27 +71,141 @@ type AlgorithmRunner interface {
 // Predict provides logic for using Linear Regression to make a prediction
 type Predict struct {
 	Runner AlgorithmRunner
+	// Clock provides the current time that predictions are made from, if not set the system clock is used
+	Clock clock.PassiveClock
 }
 
 // GetPrediction uses a linear regression to predict what the replica count should be based on historical evaluations
 func (p *Predict) GetPrediction(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
+	history := make([]jamiethompsonmev1alpha1.TimestampedValue, len(replicaHistory))
+	for i, timestampedReplica := range replicaHistory {
+		history[i] = jamiethompsonmev1alpha1.TimestampedValue{
+			Time:  timestampedReplica.Time,
+			Value: float64(timestampedReplica.Replicas),
+		}
+	}
+
+	prediction, err := p.predict(model, history)
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(math.Ceil(prediction)), nil
+}
+
+// GetPredictionInterval uses a linear regression to predict what the replica count should be based on historical
+// evaluations, along with the bounds of the prediction interval at the provided confidence
+func (p *Predict) GetPredictionInterval(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, confidence float64) (*prediction.Interval, error) {
 	if model.Linear == nil {
-		return 0, errors.New("no Linear configuration provided for model")
+		return nil, errors.New("no Linear configuration provided for model")
 	}
 
 	if len(replicaHistory) == 0 {
+		return nil, errors.New("no evaluations provided for Linear regression model")
+	}
+
+	history := make([]jamiethompsonmev1alpha1.TimestampedValue, len(replicaHistory))
+	for i, timestampedReplica := range replicaHistory {
+		history[i] = jamiethompsonmev1alpha1.TimestampedValue{
+			Time:  timestampedReplica.Time,
+			Value: float64(timestampedReplica.Replicas),
+		}
+	}
+
+	var predicted, lower, upper float64
+	var err error
+
+	// At least 3 evaluations are needed to estimate the variance of the regression, with fewer there is no interval
+	// so just make a prediction
+	if len(history) < 3 {
+		predicted, err = p.predict(model, history)
+		lower = predicted
+		upper = predicted
+	} else {
+		backend := defaultBackend
+		if model.Backend != nil {
+			backend = *model.Backend
+		}
+
+		switch backend {
+		case jamiethompsonmev1alpha1.BackendNative:
+			predicted, lower, upper, err = nativeRegressionInterval(model.Linear.LookAhead, history, p.now(),
+				confidence)
+		case jamiethompsonmev1alpha1.BackendPython:
+			predicted, lower, upper, err = p.getPythonPredictionInterval(model, history, confidence)
+		default:
+			err = fmt.Errorf("unknown backend '%s' for Linear regression prediction", backend)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &prediction.Interval{
+		Prediction: int32(math.Ceil(predicted)),
+		Lower:      int32(math.Floor(lower)),
+		Upper:      int32(math.Ceil(upper)),
+	}, nil
+}
+
+// GetMetricPrediction uses a linear regression to forecast a metric value based on the metric's history
+func (p *Predict) GetMetricPrediction(model *jamiethompsonmev1alpha1.Model, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (float64, error) {
+	return p.predict(model, metricHistory)
+}
+
+func (p *Predict) predict(model *jamiethompsonmev1alpha1.Model, history []jamiethompsonmev1alpha1.TimestampedValue) (float64, error) {
+	if model.Linear == nil {
+		return 0, errors.New("no Linear configuration provided for model")
+	}
+
+	if len(history) == 0 {
 		return 0, errors.New("no evaluations provided for Linear regression model")
 	}
 
-	if len(replicaHistory) == 1 {
+	if len(history) == 1 {
 		// If only 1 evaluation is provided do not try and calculate using the linear regression model, just return
-		// the target replicas from the only evaluation
-		return replicaHistory[0].Replicas, nil
+		// the value from the only evaluation
+		return history[0].Value, nil
+	}
+
+	backend := defaultBackend
+	if model.Backend != nil {
+		backend = *model.Backend
+	}
+
+	switch backend {
+	case jamiethompsonmev1alpha1.BackendNative:
+		return nativeRegression(model.Linear.LookAhead, history, p.now())
+	case jamiethompsonmev1alpha1.BackendPython:
+		return p.getPythonPrediction(model, history)
+	}
+
+	return 0, fmt.Errorf("unknown backend '%s' for Linear regression prediction", backend)
+}
+
+// now returns the current time from the clock, falling back to the system clock if no clock is set
+func (p *Predict) now() time.Time {
+	if p.Clock == nil {
+		return time.Now().UTC()
+	}
+	return p.Clock.Now().UTC()
+}
+
+// getPythonPrediction calculates the prediction by running the statsmodels based Python algorithm
+func (p *Predict) getPythonPrediction(model *jamiethompsonmev1alpha1.Model,
+	history []jamiethompsonmev1alpha1.TimestampedValue) (float64, error) {
+	values := make([]linearRegressionValue, len(history))
+	for i, timestampedValue := range history {
+		values[i] = linearRegressionValue{
+			Time:     timestampedValue.Time,
+			Replicas: timestampedValue.Value,
+		}
 	}
 
 	parameters, err := json.Marshal(linearRegressionParameters{
 		LookAhead:      model.Linear.LookAhead,
-		ReplicaHistory: replicaHistory,
+		ReplicaHistory: values,
+		CurrentTime:    metav1.NewTime(p.now()),
 	})
 	if err != nil {
 		// Should not occur, panic
@@ -92,7 +227,49 @@ func (p *Predict) GetPrediction(model *jamiethompsonmev1alpha1.Model, replicaHis
 		return 0, err
 	}
 
-	return int32(prediction), nil
+	return float64(prediction), nil
+}
+
+// getPythonPredictionInterval calculates the prediction and prediction interval by running the statsmodels based Python
+// algorithm
+func (p *Predict) getPythonPredictionInterval(model *jamiethompsonmev1alpha1.Model,
+	history []jamiethompsonmev1alpha1.TimestampedValue, confidence float64) (float64, float64, float64, error) {
+	values := make([]linearRegressionValue, len(history))
+	for i, timestampedValue := range history {
+		values[i] = linearRegressionValue{
+			Time:     timestampedValue.Time,
+			Replicas: timestampedValue.Value,
+		}
+	}
+
+	parameters, err := json.Marshal(linearRegressionParameters{
+		LookAhead:          model.Linear.LookAhead,
+		ReplicaHistory:     values,
+		CurrentTime:        metav1.NewTime(p.now()),
+		IntervalConfidence: &confidence,
+	})
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	timeout := defaultTimeout
+	if model.CalculationTimeout != nil {
+		timeout = *model.CalculationTimeout
+	}
+
+	value, err := p.Runner.RunAlgorithmWithValue(algorithmPath, string(parameters), timeout)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	var result linearRegressionInterval
+	err = json.Unmarshal([]byte(value), &result)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid response from Linear regression algorithm: %w", err)
+	}
+
+	return result.Prediction, result.Lower, result.Upper, nil
 }
 
 func (p *Predict) PruneHistory(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
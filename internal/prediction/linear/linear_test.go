@@ -18,20 +18,28 @@ package linear_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/linear"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
 )
 
 func intPtr(i int) *int {
 	return &i
 }
 
+func stringPtr(s string) *string {
+	return &s
+}
+
 func TestPredict_GetPrediction(t *testing.T) {
 	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
 		if x == nil || y == nil {
@@ -89,7 +97,7 @@ func TestPredict_GetPrediction(t *testing.T) {
 			},
 		},
 		{
-			description: "Fail execution of algorithm fails",
+			description: "Fail python backend, execution of algorithm fails",
 			expected:    0,
 			expectedErr: errors.New("algorithm fail"),
 			predicter: &linear.Predict{
@@ -100,7 +108,8 @@ func TestPredict_GetPrediction(t *testing.T) {
 				},
 			},
 			model: &jamiethompsonmev1alpha1.Model{
-				Type: jamiethompsonmev1alpha1.TypeLinear,
+				Type:    jamiethompsonmev1alpha1.TypeLinear,
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendPython),
 				Linear: &jamiethompsonmev1alpha1.Linear{
 					HistorySize: 5,
 					LookAhead:   0,
@@ -116,7 +125,7 @@ func TestPredict_GetPrediction(t *testing.T) {
 			},
 		},
 		{
-			description: "Fail algorithm returns non-integer castable value",
+			description: "Fail python backend, algorithm returns non-integer castable value",
 			expected:    0,
 			expectedErr: errors.New(`strconv.Atoi: parsing "invalid": invalid syntax`),
 			predicter: &linear.Predict{
@@ -127,7 +136,8 @@ func TestPredict_GetPrediction(t *testing.T) {
 				},
 			},
 			model: &jamiethompsonmev1alpha1.Model{
-				Type: jamiethompsonmev1alpha1.TypeLinear,
+				Type:    jamiethompsonmev1alpha1.TypeLinear,
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendPython),
 				Linear: &jamiethompsonmev1alpha1.Linear{
 					HistorySize: 5,
 					LookAhead:   0,
@@ -146,15 +156,385 @@ func TestPredict_GetPrediction(t *testing.T) {
 			description: "Success",
 			expected:    3,
 			expectedErr: nil,
+			predicter: &linear.Predict{
+				Clock: testingclock.NewFakePassiveClock(time.Date(2020, time.February, 1, 0, 56, 0, 0, time.UTC)),
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeLinear,
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   0,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 1,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 40, 0, time.UTC)},
+				},
+				{
+					Replicas: 2,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 50, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Success python backend, use custom timeout",
+			expected:    3,
+			expectedErr: nil,
 			predicter: &linear.Predict{
 				Runner: &fake.Run{
 					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						if !cmp.Equal(timeout, 10) {
+							return "", fmt.Errorf("timeout mismatch (-want +got):\n%s", cmp.Diff(10, timeout))
+						}
 						return "3", nil
 					},
 				},
 			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type:    jamiethompsonmev1alpha1.TypeLinear,
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendPython),
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   0,
+				},
+				CalculationTimeout: intPtr(10),
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 1,
+				},
+				{
+					Replicas: 2,
+				},
+			},
+		},
+		{
+			description: "Fail unknown backend",
+			expected:    0,
+			expectedErr: errors.New("unknown backend 'invalid' for Linear regression prediction"),
+			predicter:   &linear.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type:    jamiethompsonmev1alpha1.TypeLinear,
+				Backend: stringPtr("invalid"),
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   0,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 1,
+				},
+				{
+					Replicas: 2,
+				},
+			},
+		},
+		{
+			description: "Fail native backend, replica history missing time",
+			expected:    0,
+			expectedErr: errors.New("invalid replica history provided, missing time"),
+			predicter:   &linear.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type:    jamiethompsonmev1alpha1.TypeLinear,
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   0,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 1,
+				},
+				{
+					Replicas: 2,
+				},
+			},
+		},
+		{
+			description: "Success native backend by default, constant replicas",
+			expected:    5,
+			expectedErr: nil,
+			predicter:   &linear.Predict{},
 			model: &jamiethompsonmev1alpha1.Model{
 				Type: jamiethompsonmev1alpha1.TypeLinear,
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   10000,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Now().UTC().Add(time.Duration(-30) * time.Second)},
+				},
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Now().UTC().Add(time.Duration(-20) * time.Second)},
+				},
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Now().UTC().Add(time.Duration(-10) * time.Second)},
+				},
+			},
+		},
+		{
+			description: "Success native backend, decreasing replicas",
+			expected:    4,
+			expectedErr: nil,
+			predicter:   &linear.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type:    jamiethompsonmev1alpha1.TypeLinear,
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   0,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 10,
+					Time:     &metav1.Time{Time: time.Now().UTC().Add(time.Duration(-30) * time.Second)},
+				},
+				{
+					Replicas: 8,
+					Time:     &metav1.Time{Time: time.Now().UTC().Add(time.Duration(-20) * time.Second)},
+				},
+				{
+					Replicas: 6,
+					Time:     &metav1.Time{Time: time.Now().UTC().Add(time.Duration(-10) * time.Second)},
+				},
+			},
+		},
+		{
+			description: "Success native backend, predict from clock time",
+			expected:    2,
+			expectedErr: nil,
+			predicter: &linear.Predict{
+				Clock: testingclock.NewFakePassiveClock(time.Date(2020, time.February, 1, 0, 56, 0, 0, time.UTC)),
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type:    jamiethompsonmev1alpha1.TypeLinear,
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendNative),
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   10000,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 10,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 30, 0, time.UTC)},
+				},
+				{
+					Replicas: 8,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 40, 0, time.UTC)},
+				},
+				{
+					Replicas: 6,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 50, 0, time.UTC)},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.predicter.GetPrediction(test.model, test.replicaHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_GetMetricPrediction(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description   string
+		expected      float64
+		expectedErr   error
+		predicter     *linear.Predict
+		model         *jamiethompsonmev1alpha1.Model
+		metricHistory []jamiethompsonmev1alpha1.TimestampedValue
+	}{
+		{
+			description:   "Fail no Linear configuration",
+			expected:      0,
+			expectedErr:   errors.New("no Linear configuration provided for model"),
+			predicter:     &linear.Predict{},
+			model:         &jamiethompsonmev1alpha1.Model{},
+			metricHistory: []jamiethompsonmev1alpha1.TimestampedValue{},
+		},
+		{
+			description: "Fail no evaluations",
+			expected:    0,
+			expectedErr: errors.New("no evaluations provided for Linear regression model"),
+			predicter:   &linear.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeLinear,
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   0,
+				},
+			},
+			metricHistory: []jamiethompsonmev1alpha1.TimestampedValue{},
+		},
+		{
+			description: "Success, only one evaluation, return the value without rounding",
+			expected:    1500.5,
+			expectedErr: nil,
+			predicter:   &linear.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeLinear,
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   0,
+				},
+			},
+			metricHistory: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 1500.5,
+				},
+			},
+		},
+		{
+			description: "Success python backend",
+			expected:    2500,
+			expectedErr: nil,
+			predicter: &linear.Predict{
+				Clock: testingclock.NewFakePassiveClock(time.Date(2020, time.February, 1, 0, 56, 0, 0, time.UTC)),
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						expectedValue := `{"lookAhead":0,"replicaHistory":[{"time":"2020-02-01T00:55:33Z","replicas":1500.5},{"time":"2020-02-01T00:55:43Z","replicas":2000}],"currentTime":"2020-02-01T00:56:00Z"}`
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						return "2500", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type:    jamiethompsonmev1alpha1.TypeLinear,
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendPython),
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   0,
+				},
+			},
+			metricHistory: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 1500.5,
+					Time:  &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+				{
+					Value: 2000,
+					Time:  &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 43, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Success native backend, constant values",
+			expected:    1500.5,
+			expectedErr: nil,
+			predicter:   &linear.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeLinear,
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   10000,
+				},
+			},
+			metricHistory: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 1500.5,
+					Time:  &metav1.Time{Time: time.Now().UTC().Add(time.Duration(-30) * time.Second)},
+				},
+				{
+					Value: 1500.5,
+					Time:  &metav1.Time{Time: time.Now().UTC().Add(time.Duration(-20) * time.Second)},
+				},
+				{
+					Value: 1500.5,
+					Time:  &metav1.Time{Time: time.Now().UTC().Add(time.Duration(-10) * time.Second)},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.predicter.GetMetricPrediction(test.model, test.metricHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result, cmpopts.EquateApprox(0, 1e-9)) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_GetPredictionInterval(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description    string
+		expected       *prediction.Interval
+		expectedErr    error
+		predicter      *linear.Predict
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+		confidence     float64
+	}{
+		{
+			description:    "Fail no Linear configuration",
+			expected:       nil,
+			expectedErr:    errors.New("no Linear configuration provided for model"),
+			predicter:      &linear.Predict{},
+			model:          &jamiethompsonmev1alpha1.Model{},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			confidence:     0.8,
+		},
+		{
+			description: "Fail no evaluations",
+			expected:    nil,
+			expectedErr: errors.New("no evaluations provided for Linear regression model"),
+			predicter:   &linear.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeLinear,
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   0,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			confidence:     0.8,
+		},
+		{
+			description: "Fail unknown backend",
+			expected:    nil,
+			expectedErr: errors.New("unknown backend 'invalid' for Linear regression prediction"),
+			predicter:   &linear.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type:    jamiethompsonmev1alpha1.TypeLinear,
+				Backend: stringPtr("invalid"),
 				Linear: &jamiethompsonmev1alpha1.Linear{
 					HistorySize: 5,
 					LookAhead:   0,
@@ -163,44 +543,196 @@ func TestPredict_GetPrediction(t *testing.T) {
 			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
 				{
 					Replicas: 1,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
 				},
 				{
 					Replicas: 2,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 43, 0, time.UTC)},
+				},
+				{
+					Replicas: 3,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 53, 0, time.UTC)},
 				},
 			},
+			confidence: 0.8,
 		},
 		{
-			description: "Success, use custom timeout",
-			expected:    3,
+			description: "Fail python backend, execution of algorithm fails",
+			expected:    nil,
+			expectedErr: errors.New("algorithm fail"),
+			predicter: &linear.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return "", errors.New("algorithm fail")
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type:    jamiethompsonmev1alpha1.TypeLinear,
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendPython),
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   0,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 1,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+				{
+					Replicas: 2,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 43, 0, time.UTC)},
+				},
+				{
+					Replicas: 3,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 53, 0, time.UTC)},
+				},
+			},
+			confidence: 0.8,
+		},
+		{
+			description: "Fail python backend, algorithm returns invalid JSON",
+			expected:    nil,
+			expectedErr: errors.New("invalid response from Linear regression algorithm: invalid character 'i' looking for beginning of value"),
+			predicter: &linear.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return "invalid", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type:    jamiethompsonmev1alpha1.TypeLinear,
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendPython),
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   0,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 1,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+				{
+					Replicas: 2,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 43, 0, time.UTC)},
+				},
+				{
+					Replicas: 3,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 53, 0, time.UTC)},
+				},
+			},
+			confidence: 0.8,
+		},
+		{
+			description: "Success, only one evaluation, interval is the only value",
+			expected: &prediction.Interval{
+				Prediction: 3,
+				Lower:      3,
+				Upper:      3,
+			},
+			expectedErr: nil,
+			predicter:   &linear.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeLinear,
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   0,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 3,
+				},
+			},
+			confidence: 0.8,
+		},
+		{
+			description: "Success python backend",
+			expected: &prediction.Interval{
+				Prediction: 4,
+				Lower:      2,
+				Upper:      6,
+			},
 			expectedErr: nil,
 			predicter: &linear.Predict{
+				Clock: testingclock.NewFakePassiveClock(time.Date(2020, time.February, 1, 0, 56, 0, 0, time.UTC)),
 				Runner: &fake.Run{
 					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
-						return "3", nil
+						expectedValue := `{"lookAhead":0,"replicaHistory":[{"time":"2020-02-01T00:55:33Z","replicas":1},{"time":"2020-02-01T00:55:43Z","replicas":2},{"time":"2020-02-01T00:55:53Z","replicas":3}],"currentTime":"2020-02-01T00:56:00Z","intervalConfidence":0.8}`
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						return `{"prediction":3.5,"lower":2.5,"upper":5.5}`, nil
 					},
 				},
 			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type:    jamiethompsonmev1alpha1.TypeLinear,
+				Backend: stringPtr(jamiethompsonmev1alpha1.BackendPython),
+				Linear: &jamiethompsonmev1alpha1.Linear{
+					HistorySize: 5,
+					LookAhead:   0,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 1,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+				{
+					Replicas: 2,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 43, 0, time.UTC)},
+				},
+				{
+					Replicas: 3,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 53, 0, time.UTC)},
+				},
+			},
+			confidence: 0.8,
+		},
+		{
+			description: "Success native backend",
+			expected: &prediction.Interval{
+				Prediction: 6,
+				Lower:      3,
+				Upper:      8,
+			},
+			expectedErr: nil,
+			predicter:   &linear.Predict{},
 			model: &jamiethompsonmev1alpha1.Model{
 				Type: jamiethompsonmev1alpha1.TypeLinear,
 				Linear: &jamiethompsonmev1alpha1.Linear{
 					HistorySize: 5,
 					LookAhead:   0,
 				},
-				CalculationTimeout: intPtr(10),
 			},
 			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
 				{
 					Replicas: 1,
+					Time:     &metav1.Time{Time: time.Now().UTC().Add(time.Duration(-40) * time.Second)},
+				},
+				{
+					Replicas: 3,
+					Time:     &metav1.Time{Time: time.Now().UTC().Add(time.Duration(-30) * time.Second)},
 				},
 				{
 					Replicas: 2,
+					Time:     &metav1.Time{Time: time.Now().UTC().Add(time.Duration(-20) * time.Second)},
+				},
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Now().UTC().Add(time.Duration(-10) * time.Second)},
 				},
 			},
+			confidence: 0.6,
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
-			result, err := test.predicter.GetPrediction(test.model, test.replicaHistory)
+			result, err := test.predicter.GetPredictionInterval(test.model, test.replicaHistory, test.confidence)
 			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
 				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
 				return
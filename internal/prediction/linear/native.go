@@ -0,0 +1,241 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linear
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+)
+
+// nativeRegression uses an ordinary least squares linear regression to predict the value at the look ahead time (in
+// milliseconds) after the current time. This is designed to produce the same results as the statsmodels OLS regression
+// used by the Python algorithm.
+func nativeRegression(lookAhead int, history []jamiethompsonmev1alpha1.TimestampedValue,
+	now time.Time) (float64, error) {
+	x, y, err := regressionData(lookAhead, history, now)
+	if err != nil {
+		return 0, err
+	}
+
+	xMean := mean(x)
+	yMean := mean(y)
+
+	covariance := 0.0
+	variance := 0.0
+	for i := range x {
+		covariance += (x[i] - xMean) * (y[i] - yMean)
+		variance += (x[i] - xMean) * (x[i] - xMean)
+	}
+
+	if variance == 0 {
+		// Every value was recorded at the same time, statsmodels solves this using the Moore-Penrose
+		// pseudoinverse which gives the minimum norm solution, resulting in this intercept
+		return yMean / (1 + x[0]*x[0]), nil
+	}
+
+	gradient := covariance / variance
+
+	// Predict the value at the search time, which is the intercept
+	return yMean - gradient*xMean, nil
+}
+
+// nativeRegressionInterval uses an ordinary least squares linear regression to predict the value at the look ahead time
+// along with the lower and upper bounds of the prediction interval at the provided confidence. This is designed to
+// produce the same results as the statsmodels OLS prediction interval (obs_ci_lower and obs_ci_upper) used by the
+// Python algorithm. If there are not enough observations to estimate the variance the bounds are the prediction.
+func nativeRegressionInterval(lookAhead int, history []jamiethompsonmev1alpha1.TimestampedValue, now time.Time,
+	confidence float64) (float64, float64, float64, error) {
+	prediction, err := nativeRegression(lookAhead, history, now)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	x, y, err := regressionData(lookAhead, history, now)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	xMean := mean(x)
+	yMean := mean(y)
+
+	covariance := 0.0
+	variance := 0.0
+	for i := range x {
+		covariance += (x[i] - xMean) * (y[i] - yMean)
+		variance += (x[i] - xMean) * (x[i] - xMean)
+	}
+
+	// The residual degrees of freedom must be positive to estimate the variance of the residuals
+	degreesOfFreedom := len(x) - 2
+	if degreesOfFreedom < 1 || variance == 0 {
+		return prediction, prediction, prediction, nil
+	}
+
+	gradient := covariance / variance
+
+	residualSumOfSquares := 0.0
+	for i := range x {
+		residual := y[i] - (prediction + gradient*x[i])
+		residualSumOfSquares += residual * residual
+	}
+
+	// Standard error of a new observation at the search time (x = 0)
+	residualVariance := residualSumOfSquares / float64(degreesOfFreedom)
+	standardError := math.Sqrt(residualVariance * (1 + 1/float64(len(x)) + xMean*xMean/variance))
+
+	margin := studentTQuantile((1+confidence)/2, float64(degreesOfFreedom)) * standardError
+
+	return prediction, prediction - margin, prediction + margin, nil
+}
+
+// regressionData converts the history into the x and y values used to fit the regression. In order to not deal with
+// huge values and get rounding errors, x is the difference between the time being searched for and the value recorded
+// time in seconds, the search time is then 0.
+func regressionData(lookAhead int, history []jamiethompsonmev1alpha1.TimestampedValue,
+	now time.Time) ([]float64, []float64, error) {
+	searchTime := now.Add(time.Duration(lookAhead) * time.Millisecond)
+
+	x := make([]float64, len(history))
+	y := make([]float64, len(history))
+	for i, timestampedValue := range history {
+		if timestampedValue.Time == nil {
+			return nil, nil, errors.New("invalid replica history provided, missing time")
+		}
+		// The history is serialized with a precision of seconds, so truncate to seconds to match
+		created := timestampedValue.Time.Time.Truncate(time.Second)
+		x[i] = searchTime.Sub(created).Seconds()
+		y[i] = timestampedValue.Value
+	}
+
+	return x, y, nil
+}
+
+// studentTQuantile returns the value below which the provided probability of a Student's t-distribution with the
+// provided degrees of freedom falls, found by bisecting the cumulative distribution function
+func studentTQuantile(probability float64, degreesOfFreedom float64) float64 {
+	if probability == 0.5 {
+		return 0
+	}
+
+	if probability < 0.5 {
+		return -studentTQuantile(1-probability, degreesOfFreedom)
+	}
+
+	low := 0.0
+	high := 1.0
+	for studentTCDF(high, degreesOfFreedom) < probability {
+		low = high
+		high *= 2
+	}
+
+	for i := 0; i < 200; i++ {
+		mid := (low + high) / 2
+		if studentTCDF(mid, degreesOfFreedom) < probability {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	return (low + high) / 2
+}
+
+// studentTCDF returns the cumulative distribution function of a Student's t-distribution with the provided degrees
+// of freedom for a non-negative t
+func studentTCDF(t float64, degreesOfFreedom float64) float64 {
+	return 1 - 0.5*regularizedIncompleteBeta(degreesOfFreedom/(degreesOfFreedom+t*t), degreesOfFreedom/2, 0.5)
+}
+
+// regularizedIncompleteBeta calculates the regularized incomplete beta function I_x(a, b) using its continued fraction
+// representation, evaluated with the modified Lentz's method
+func regularizedIncompleteBeta(x float64, a float64, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	// The continued fraction converges quickly for x < (a + 1) / (a + b + 2), otherwise use the symmetry relation
+	if x > (a+1)/(a+b+2) {
+		return 1 - regularizedIncompleteBeta(1-x, b, a)
+	}
+
+	lgammaA, _ := math.Lgamma(a)
+	lgammaB, _ := math.Lgamma(b)
+	lgammaAB, _ := math.Lgamma(a + b)
+	front := math.Exp(lgammaAB-lgammaA-lgammaB+a*math.Log(x)+b*math.Log(1-x)) / a
+
+	const tiny = 1e-300
+	const epsilon = 1e-15
+
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	result := d
+
+	for m := 1; m <= 300; m++ {
+		mf := float64(m)
+
+		// Even step
+		numerator := mf * (b - mf) * x / ((a + 2*mf - 1) * (a + 2*mf))
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		result *= d * c
+
+		// Odd step
+		numerator = -(a + mf) * (a + b + mf) * x / ((a + 2*mf) * (a + 2*mf + 1))
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		result *= delta
+
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+
+	return front * result
+}
+
+func mean(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
@@ -0,0 +1,427 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linear
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNativeRegression(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	// These test cases match the statsmodels results in algorithms/linear_regression/test_linear_regression.py
+	history := []jamiethompsonmev1alpha1.TimestampedValue{
+		{
+			Value: 1,
+			Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 55, 33, 0, time.UTC)},
+		},
+		{
+			Value: 2,
+			Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 55, 43, 0, time.UTC)},
+		},
+		{
+			Value: 3,
+			Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 55, 53, 0, time.UTC)},
+		},
+		{
+			Value: 4,
+			Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 56, 3, 0, time.UTC)},
+		},
+	}
+
+	var tests = []struct {
+		description string
+		expected    int32
+		expectedErr error
+		lookAhead   int
+		history     []jamiethompsonmev1alpha1.TimestampedValue
+		now         time.Time
+	}{
+		{
+			description: "Fail, replica history missing time",
+			expected:    0,
+			expectedErr: errors.New("invalid replica history provided, missing time"),
+			lookAhead:   0,
+			history: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 1,
+				},
+				{
+					Value: 2,
+				},
+			},
+			now: time.Date(2020, 2, 1, 0, 56, 12, 0, time.UTC),
+		},
+		{
+			description: "Successful prediction, now",
+			expected:    5,
+			expectedErr: nil,
+			lookAhead:   0,
+			history:     history,
+			now:         time.Date(2020, 2, 1, 0, 56, 12, 0, time.UTC),
+		},
+		{
+			description: "Successful prediction, 10 seconds in the future",
+			expected:    6,
+			expectedErr: nil,
+			lookAhead:   10000,
+			history:     history,
+			now:         time.Date(2020, 2, 1, 0, 56, 12, 0, time.UTC),
+		},
+		{
+			description: "Successful prediction, 15 seconds in the future",
+			expected:    7,
+			expectedErr: nil,
+			lookAhead:   15000,
+			history:     history,
+			now:         time.Date(2020, 2, 1, 0, 56, 12, 0, time.UTC),
+		},
+		{
+			description: "Successful prediction, sub-second times truncated",
+			expected:    5,
+			expectedErr: nil,
+			lookAhead:   0,
+			history: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 1,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 55, 33, 900000000, time.UTC)},
+				},
+				{
+					Value: 2,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 55, 43, 500000000, time.UTC)},
+				},
+				{
+					Value: 3,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 55, 53, 100000000, time.UTC)},
+				},
+				{
+					Value: 4,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 56, 3, 0, time.UTC)},
+				},
+			},
+			now: time.Date(2020, 2, 1, 0, 56, 12, 0, time.UTC),
+		},
+		{
+			description: "Successful prediction, all recorded at the same time",
+			expected:    1,
+			expectedErr: nil,
+			lookAhead:   0,
+			history: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 3,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 56, 10, 0, time.UTC)},
+				},
+				{
+					Value: 5,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 56, 10, 0, time.UTC)},
+				},
+			},
+			now: time.Date(2020, 2, 1, 0, 56, 12, 0, time.UTC),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := nativeRegression(test.lookAhead, test.history, test.now)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, int32(math.Ceil(result))) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, int32(math.Ceil(result))))
+			}
+		})
+	}
+}
+
+func TestNativeRegression_Fixtures(t *testing.T) {
+	now := time.Date(2020, time.February, 1, 0, 56, 0, 0, time.UTC)
+
+	// Each expected value is the exact ordinary least squares intercept at the look ahead time, solved in rational
+	// arithmetic, which is the same estimate as the statsmodels OLS fit used by the Python algorithm. The values are
+	// compared before rounding so that any difference from the least squares solution is caught
+	var tests = []struct {
+		description string
+		expected    float64
+		lookAhead   int
+		history     []jamiethompsonmev1alpha1.TimestampedValue
+	}{
+		{
+			description: "Increasing values",
+			expected:    11,
+			lookAhead:   15000,
+			history: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 2,
+					Time:  &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 30, 0, time.UTC)},
+				},
+				{
+					Value: 4,
+					Time:  &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 40, 0, time.UTC)},
+				},
+				{
+					Value: 6,
+					Time:  &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 50, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Noisy values",
+			expected:    9.7,
+			lookAhead:   30000,
+			history: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 3,
+					Time:  &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 0, 0, time.UTC)},
+				},
+				{
+					Value: 5,
+					Time:  &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 15, 0, time.UTC)},
+				},
+				{
+					Value: 4,
+					Time:  &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 30, 0, time.UTC)},
+				},
+				{
+					Value: 7,
+					Time:  &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 45, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Decreasing values",
+			expected:    1.5,
+			lookAhead:   20000,
+			history: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 12,
+					Time:  &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 0, 0, time.UTC)},
+				},
+				{
+					Value: 9,
+					Time:  &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 20, 0, time.UTC)},
+				},
+				{
+					Value: 7,
+					Time:  &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 40, 0, time.UTC)},
+				},
+				{
+					Value: 4,
+					Time:  &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 56, 0, 0, time.UTC)},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := nativeRegression(test.lookAhead, test.history, now)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if !cmp.Equal(test.expected, result, cmpopts.EquateApprox(0, 1e-9)) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestNativeRegressionInterval(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description        string
+		expectedPrediction float64
+		expectedLower      float64
+		expectedUpper      float64
+		expectedErr        error
+		lookAhead          int
+		history            []jamiethompsonmev1alpha1.TimestampedValue
+		now                time.Time
+		confidence         float64
+	}{
+		{
+			description:        "Fail, replica history missing time",
+			expectedPrediction: 0,
+			expectedLower:      0,
+			expectedUpper:      0,
+			expectedErr:        errors.New("invalid replica history provided, missing time"),
+			lookAhead:          0,
+			history: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 1,
+				},
+				{
+					Value: 2,
+				},
+				{
+					Value: 3,
+				},
+			},
+			now:        time.Date(2020, 2, 1, 0, 56, 12, 0, time.UTC),
+			confidence: 0.8,
+		},
+		{
+			description:        "Success, only 2 observations, no interval",
+			expectedPrediction: 4,
+			expectedLower:      4,
+			expectedUpper:      4,
+			expectedErr:        nil,
+			lookAhead:          0,
+			history: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 2,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 55, 53, 0, time.UTC)},
+				},
+				{
+					Value: 3,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 56, 3, 0, time.UTC)},
+				},
+			},
+			now:        time.Date(2020, 2, 1, 0, 56, 13, 0, time.UTC),
+			confidence: 0.8,
+		},
+		{
+			description:        "Success, perfect fit, no interval",
+			expectedPrediction: 5,
+			expectedLower:      5,
+			expectedUpper:      5,
+			expectedErr:        nil,
+			lookAhead:          0,
+			history: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 2,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 55, 43, 0, time.UTC)},
+				},
+				{
+					Value: 3,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 55, 53, 0, time.UTC)},
+				},
+				{
+					Value: 4,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 56, 3, 0, time.UTC)},
+				},
+			},
+			now:        time.Date(2020, 2, 1, 0, 56, 13, 0, time.UTC),
+			confidence: 0.8,
+		},
+		{
+			// Calculated using a t-distribution with 2 degrees of freedom, matching statsmodels obs_ci_lower and
+			// obs_ci_upper
+			description:        "Success, 80% interval",
+			expectedPrediction: 5.5,
+			expectedLower:      5.5 - 1.8856180831641267*math.Sqrt(3.375),
+			expectedUpper:      5.5 + 1.8856180831641267*math.Sqrt(3.375),
+			expectedErr:        nil,
+			lookAhead:          0,
+			history: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 1,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 55, 33, 0, time.UTC)},
+				},
+				{
+					Value: 3,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 55, 43, 0, time.UTC)},
+				},
+				{
+					Value: 2,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 55, 53, 0, time.UTC)},
+				},
+				{
+					Value: 5,
+					Time:  &metav1.Time{Time: time.Date(2020, 2, 1, 0, 56, 3, 0, time.UTC)},
+				},
+			},
+			now:        time.Date(2020, 2, 1, 0, 56, 13, 0, time.UTC),
+			confidence: 0.8,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			prediction, lower, upper, err := nativeRegressionInterval(test.lookAhead, test.history, test.now,
+				test.confidence)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			expected := []float64{test.expectedPrediction, test.expectedLower, test.expectedUpper}
+			result := []float64{prediction, lower, upper}
+			if !cmp.Equal(expected, result, cmpopts.EquateApprox(0, 1e-9)) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(expected, result))
+			}
+		})
+	}
+}
+
+func TestStudentTQuantile(t *testing.T) {
+	var tests = []struct {
+		description      string
+		expected         float64
+		probability      float64
+		degreesOfFreedom float64
+	}{
+		{
+			description:      "Median",
+			expected:         0,
+			probability:      0.5,
+			degreesOfFreedom: 3,
+		},
+		{
+			description:      "97.5%, 1 degree of freedom",
+			expected:         12.706204736174698,
+			probability:      0.975,
+			degreesOfFreedom: 1,
+		},
+		{
+			description:      "95%, 10 degrees of freedom",
+			expected:         1.8124611228107335,
+			probability:      0.95,
+			degreesOfFreedom: 10,
+		},
+		{
+			description:      "5%, 10 degrees of freedom",
+			expected:         -1.8124611228107335,
+			probability:      0.05,
+			degreesOfFreedom: 10,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result := studentTQuantile(test.probability, test.degreesOfFreedom)
+			if !cmp.Equal(test.expected, result, cmpopts.EquateApprox(0, 1e-9)) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mstl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+)
+
+const algorithmPath = "algorithms/mstl/mstl.py"
+
+const (
+	defaultTimeout = 30000
+)
+
+// AlgorithmRunner defines an algorithm runner, allowing algorithms to be run
+type AlgorithmRunner interface {
+	RunAlgorithmWithValue(algorithmPath string, value string, timeout int) (string, error)
+}
+
+// Predict provides logic for using MSTL to make a prediction
+type Predict struct {
+	Runner AlgorithmRunner
+}
+
+type mstlParameters struct {
+	Series          []float64 `json:"series"`
+	SeasonalPeriods []int     `json:"seasonalPeriods"`
+}
+
+// GetPrediction uses MSTL to predict what the replica count should be based on historical evaluations
+func (p *Predict) GetPrediction(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
+	history := make([]jamiethompsonmev1alpha1.TimestampedValue, len(replicaHistory))
+	for i, timestampedReplica := range replicaHistory {
+		history[i] = jamiethompsonmev1alpha1.TimestampedValue{
+			Time:  timestampedReplica.Time,
+			Value: float64(timestampedReplica.Replicas),
+		}
+	}
+
+	prediction, err := p.predict(model, history)
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(math.Ceil(prediction)), nil
+}
+
+// GetMetricPrediction uses MSTL to forecast a metric value based on the metric's history
+func (p *Predict) GetMetricPrediction(model *jamiethompsonmev1alpha1.Model, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (float64, error) {
+	return p.predict(model, metricHistory)
+}
+
+func (p *Predict) predict(model *jamiethompsonmev1alpha1.Model, history []jamiethompsonmev1alpha1.TimestampedValue) (float64, error) {
+	err := p.validate(model)
+	if err != nil {
+		return 0, err
+	}
+
+	// MSTL requires at least 2 * the longest seasonal period to decompose every seasonal component
+	if len(history) < 2*longestSeasonalPeriod(model.MSTL) {
+		return 0, nil
+	}
+
+	// Make sure the series is in the order the values were recorded in, oldest first
+	sortedHistory := make([]jamiethompsonmev1alpha1.TimestampedValue, len(history))
+	copy(sortedHistory, history)
+	sort.SliceStable(sortedHistory, func(i, j int) bool {
+		return sortedHistory[i].Time.Before(sortedHistory[j].Time)
+	})
+
+	// Collect data for historical series
+	series := make([]float64, len(sortedHistory))
+	for i, timestampedValue := range sortedHistory {
+		series[i] = timestampedValue.Value
+	}
+
+	parameters, err := json.Marshal(mstlParameters{
+		Series:          series,
+		SeasonalPeriods: model.MSTL.SeasonalPeriods,
+	})
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	timeout := defaultTimeout
+	if model.CalculationTimeout != nil {
+		timeout = *model.CalculationTimeout
+	}
+
+	value, err := p.Runner.RunAlgorithmWithValue(algorithmPath, string(parameters), timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	prediction, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(prediction), nil
+}
+
+func (p *Predict) PruneHistory(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+	err := p.validate(model)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort by date created, newest first
+	sort.Slice(replicaHistory, func(i, j int) bool {
+		return !replicaHistory[i].Time.Before(replicaHistory[j].Time)
+	})
+
+	// If there are too many of the longest season stored, remove the oldest ones, allowing full seasons to build up
+	// before pruning the old ones in the same way as Holt-Winters
+	longest := longestSeasonalPeriod(model.MSTL)
+	numberOfSeasonsToRemove := len(replicaHistory)/longest - model.MSTL.StoredSeasons
+	if numberOfSeasonsToRemove <= 0 {
+		return replicaHistory, nil
+	}
+
+	return replicaHistory[:len(replicaHistory)-numberOfSeasonsToRemove*longest], nil
+}
+
+// HasEnoughHistory returns if there are enough observations in the history to decompose every seasonal component
+func (p *Predict) HasEnoughHistory(model *jamiethompsonmev1alpha1.Model, historyLength int) (bool, error) {
+	err := p.validate(model)
+	if err != nil {
+		return false, err
+	}
+
+	return historyLength >= 2*longestSeasonalPeriod(model.MSTL), nil
+}
+
+// GetType returns the type of the Prediction model
+func (p *Predict) GetType() string {
+	return jamiethompsonmev1alpha1.TypeMSTL
+}
+
+func (p *Predict) validate(model *jamiethompsonmev1alpha1.Model) error {
+	if model.MSTL == nil {
+		return errors.New("no MSTL configuration provided for model")
+	}
+
+	if len(model.MSTL.SeasonalPeriods) == 0 {
+		return errors.New("no required 'seasonalPeriods' value provided for model")
+	}
+
+	for _, period := range model.MSTL.SeasonalPeriods {
+		if period < 2 {
+			return fmt.Errorf("invalid seasonal period %d provided for model, must be at least 2", period)
+		}
+	}
+
+	return nil
+}
+
+func longestSeasonalPeriod(mstl *jamiethompsonmev1alpha1.MSTL) int {
+	longest := 0
+	for _, period := range mstl.SeasonalPeriods {
+		if period > longest {
+			longest = period
+		}
+	}
+	return longest
+}
@@ -0,0 +1,457 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mstl_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/mstl"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+// history builds a replica history from the replica counts provided, with the first replica count being the oldest
+func history(replicas ...int32) []jamiethompsonmev1alpha1.TimestampedReplicas {
+	replicaHistory := make([]jamiethompsonmev1alpha1.TimestampedReplicas, len(replicas))
+	for i, replicaCount := range replicas {
+		replicaHistory[i] = jamiethompsonmev1alpha1.TimestampedReplicas{
+			Replicas: replicaCount,
+			Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(i) * time.Second)},
+		}
+	}
+	return replicaHistory
+}
+
+// reversed returns the replica history in reverse order, newest first
+func reversed(replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) []jamiethompsonmev1alpha1.TimestampedReplicas {
+	result := make([]jamiethompsonmev1alpha1.TimestampedReplicas, len(replicaHistory))
+	for i, timestampedReplica := range replicaHistory {
+		result[len(replicaHistory)-1-i] = timestampedReplica
+	}
+	return result
+}
+
+func TestPredict_GetPrediction(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description    string
+		expected       int32
+		expectedErr    error
+		predicter      *mstl.Predict
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+	}{
+		{
+			description:    "Fail no MSTL configuration",
+			expected:       0,
+			expectedErr:    errors.New("no MSTL configuration provided for model"),
+			predicter:      &mstl.Predict{},
+			model:          &jamiethompsonmev1alpha1.Model{},
+			replicaHistory: history(),
+		},
+		{
+			description: "Fail no seasonal periods",
+			expected:    0,
+			expectedErr: errors.New("no required 'seasonalPeriods' value provided for model"),
+			predicter:   &mstl.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeMSTL,
+				MSTL: &jamiethompsonmev1alpha1.MSTL{
+					StoredSeasons: 3,
+				},
+			},
+			replicaHistory: history(),
+		},
+		{
+			description: "Fail invalid seasonal period",
+			expected:    0,
+			expectedErr: errors.New("invalid seasonal period 1 provided for model, must be at least 2"),
+			predicter:   &mstl.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeMSTL,
+				MSTL: &jamiethompsonmev1alpha1.MSTL{
+					SeasonalPeriods: []int{2, 1},
+					StoredSeasons:   3,
+				},
+			},
+			replicaHistory: history(),
+		},
+		{
+			description: "Less than 2 * longest seasonal period, return 0",
+			expected:    0,
+			expectedErr: nil,
+			predicter:   &mstl.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeMSTL,
+				MSTL: &jamiethompsonmev1alpha1.MSTL{
+					SeasonalPeriods: []int{2, 4},
+					StoredSeasons:   3,
+				},
+			},
+			replicaHistory: history(1, 2, 1, 2, 1, 2, 1),
+		},
+		{
+			description: "Fail algorithm runner fails",
+			expected:    0,
+			expectedErr: errors.New("algorithm fail"),
+			predicter: &mstl.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return "", errors.New("algorithm fail")
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeMSTL,
+				MSTL: &jamiethompsonmev1alpha1.MSTL{
+					SeasonalPeriods: []int{2, 4},
+					StoredSeasons:   3,
+				},
+			},
+			replicaHistory: history(1, 2, 1, 2, 1, 2, 1, 2),
+		},
+		{
+			description: "Fail algorithm returns non-integer",
+			expected:    0,
+			expectedErr: errors.New(`strconv.Atoi: parsing "invalid": invalid syntax`),
+			predicter: &mstl.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return "invalid", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeMSTL,
+				MSTL: &jamiethompsonmev1alpha1.MSTL{
+					SeasonalPeriods: []int{2, 4},
+					StoredSeasons:   3,
+				},
+			},
+			replicaHistory: history(1, 2, 1, 2, 1, 2, 1, 2),
+		},
+		{
+			description: "Success, default timeout",
+			expected:    1,
+			expectedErr: nil,
+			predicter: &mstl.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						if !cmp.Equal(algorithmPath, "algorithms/mstl/mstl.py") {
+							return "", fmt.Errorf("algorithm path mismatch (-want +got):\n%s",
+								cmp.Diff("algorithms/mstl/mstl.py", algorithmPath))
+						}
+						expectedValue := `{"series":[1,2,1,2,1,2,1,2],"seasonalPeriods":[2,4]}`
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						if !cmp.Equal(timeout, 30000) {
+							return "", fmt.Errorf("timeout mismatch (-want +got):\n%s", cmp.Diff(30000, timeout))
+						}
+						return "1", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeMSTL,
+				MSTL: &jamiethompsonmev1alpha1.MSTL{
+					SeasonalPeriods: []int{2, 4},
+					StoredSeasons:   3,
+				},
+			},
+			replicaHistory: history(1, 2, 1, 2, 1, 2, 1, 2),
+		},
+		{
+			description: "Success, unsorted history and custom timeout",
+			expected:    3,
+			expectedErr: nil,
+			predicter: &mstl.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						expectedValue := `{"series":[1,3,1,1,3,1,1,3,1,1,3,1],"seasonalPeriods":[3,6]}`
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						if !cmp.Equal(timeout, 5000) {
+							return "", fmt.Errorf("timeout mismatch (-want +got):\n%s", cmp.Diff(5000, timeout))
+						}
+						return "3", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type:               jamiethompsonmev1alpha1.TypeMSTL,
+				CalculationTimeout: intPtr(5000),
+				MSTL: &jamiethompsonmev1alpha1.MSTL{
+					SeasonalPeriods: []int{3, 6},
+					StoredSeasons:   3,
+				},
+			},
+			replicaHistory: reversed(history(1, 3, 1, 1, 3, 1, 1, 3, 1, 1, 3, 1)),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.predicter.GetPrediction(test.model, test.replicaHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_GetMetricPrediction(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description   string
+		expected      float64
+		expectedErr   error
+		predicter     *mstl.Predict
+		model         *jamiethompsonmev1alpha1.Model
+		metricHistory []jamiethompsonmev1alpha1.TimestampedValue
+	}{
+		{
+			description:   "Fail no MSTL configuration",
+			expected:      0,
+			expectedErr:   errors.New("no MSTL configuration provided for model"),
+			predicter:     &mstl.Predict{},
+			model:         &jamiethompsonmev1alpha1.Model{},
+			metricHistory: []jamiethompsonmev1alpha1.TimestampedValue{},
+		},
+		{
+			description: "Success, unsorted metric history",
+			expected:    2600,
+			expectedErr: nil,
+			predicter: &mstl.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						expectedValue := `{"series":[1000.5,2000,1500,2500],"seasonalPeriods":[2]}`
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						return "2600", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeMSTL,
+				MSTL: &jamiethompsonmev1alpha1.MSTL{
+					SeasonalPeriods: []int{2},
+					StoredSeasons:   2,
+				},
+			},
+			metricHistory: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 2500,
+					Time:  &metav1.Time{Time: time.Time{}.Add(time.Duration(3) * time.Second)},
+				},
+				{
+					Value: 1500,
+					Time:  &metav1.Time{Time: time.Time{}.Add(time.Duration(2) * time.Second)},
+				},
+				{
+					Value: 2000,
+					Time:  &metav1.Time{Time: time.Time{}.Add(time.Duration(1) * time.Second)},
+				},
+				{
+					Value: 1000.5,
+					Time:  &metav1.Time{Time: time.Time{}.Add(time.Duration(0) * time.Second)},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.predicter.GetMetricPrediction(test.model, test.metricHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestModelPredict_PruneHistory(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description    string
+		expected       []jamiethompsonmev1alpha1.TimestampedReplicas
+		expectedErr    error
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+	}{
+		{
+			description:    "Fail no MSTL configuration",
+			expected:       nil,
+			expectedErr:    errors.New("no MSTL configuration provided for model"),
+			model:          &jamiethompsonmev1alpha1.Model{},
+			replicaHistory: history(),
+		},
+		{
+			description: "7 in history, seasonal periods 2 and 3, 2 stored seasons, don't prune",
+			expected:    reversed(history(1, 2, 3, 4, 5, 6, 7)),
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				MSTL: &jamiethompsonmev1alpha1.MSTL{
+					SeasonalPeriods: []int{2, 3},
+					StoredSeasons:   2,
+				},
+			},
+			replicaHistory: history(1, 2, 3, 4, 5, 6, 7),
+		},
+		{
+			description: "10 in history, seasonal periods 2 and 3, 2 stored seasons, prune oldest longest season",
+			expected:    reversed(history(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)[3:]),
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				MSTL: &jamiethompsonmev1alpha1.MSTL{
+					SeasonalPeriods: []int{3, 2},
+					StoredSeasons:   2,
+				},
+			},
+			replicaHistory: history(1, 2, 3, 4, 5, 6, 7, 8, 9, 10),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &mstl.Predict{}
+			result, err := predicter.PruneHistory(test.model, test.replicaHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("remove IDs mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_HasEnoughHistory(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description   string
+		expected      bool
+		expectedErr   error
+		model         *jamiethompsonmev1alpha1.Model
+		historyLength int
+	}{
+		{
+			description:   "Fail no MSTL configuration",
+			expected:      false,
+			expectedErr:   errors.New("no MSTL configuration provided for model"),
+			model:         &jamiethompsonmev1alpha1.Model{},
+			historyLength: 10,
+		},
+		{
+			description: "9 in history, longest seasonal period 5, not enough",
+			expected:    false,
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				MSTL: &jamiethompsonmev1alpha1.MSTL{
+					SeasonalPeriods: []int{2, 5},
+				},
+			},
+			historyLength: 9,
+		},
+		{
+			description: "10 in history, longest seasonal period 5, enough",
+			expected:    true,
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				MSTL: &jamiethompsonmev1alpha1.MSTL{
+					SeasonalPeriods: []int{2, 5},
+				},
+			},
+			historyLength: 10,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &mstl.Predict{}
+			result, err := predicter.HasEnoughHistory(test.model, test.historyLength)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("enough history mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_GetType(t *testing.T) {
+	var tests = []struct {
+		description string
+		expected    string
+	}{
+		{
+			description: "Successful get type",
+			expected:    "MSTL",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &mstl.Predict{}
+			result := predicter.GetType()
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("type mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
@@ -30,6 +30,48 @@ type Predicter interface {
 	GetType() string
 }
 
+// MetricPredicter is an interface providing methods for forecasting a raw metric value based on a model and the
+// metric's history, predicters can optionally implement this to support models that have an input of 'metrics'
+type MetricPredicter interface {
+	GetMetricPrediction(model *jamiethompsonmev1alpha1.Model, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (float64, error)
+}
+
+// Interval is a predicted replica count along with the lower and upper bounds of a prediction interval around it
+type Interval struct {
+	Prediction int32
+	Lower      int32
+	Upper      int32
+}
+
+// IntervalPredicter is an interface providing methods for making a prediction along with a prediction interval, the
+// confidence is the probability (between 0 and 1) that the actual value falls within the interval. Predicters can
+// optionally implement this to support the 'quantile' decision type
+type IntervalPredicter interface {
+	GetPredictionInterval(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, confidence float64) (*Interval, error)
+}
+
+// Tuner is an interface providing methods for fitting a model's smoothing parameters to the model's replica history,
+// predicters can optionally implement this to support the Holt-Winters 'autoTune' option. If there is not enough
+// history to fit the parameters no parameters are returned
+type Tuner interface {
+	Tune(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (*jamiethompsonmev1alpha1.HoltWintersTunedParameters, error)
+}
+
+// TuningFetcher is an interface providing methods for running a model's runtime tuning fetch hook separately from
+// making a prediction, predicters can optionally implement this to allow the hook's runs to be recorded. The model
+// returned has the fetched tuning values set and no runtime tuning fetch hook, so predicting with it does not run the
+// hook again
+type TuningFetcher interface {
+	FetchTuning(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (*jamiethompsonmev1alpha1.Model, error)
+}
+
+// HistoryChecker is an interface providing methods for checking if a model has enough history stored to make a
+// prediction, predicters can optionally implement this if they need a minimum amount of history. Predicters that do
+// not implement this are assumed to be able to make a prediction with any amount of history
+type HistoryChecker interface {
+	HasEnoughHistory(model *jamiethompsonmev1alpha1.Model, historyLength int) (bool, error)
+}
+
 // ModelPredict is used to route a prediction to the appropriate predicter based on the model provided
 // Should be initialised with available predicters for it to use
 type ModelPredict struct {
@@ -46,6 +88,90 @@ func (m *ModelPredict) GetPrediction(model *jamiethompsonmev1alpha1.Model, repli
 	return 0, fmt.Errorf("unknown model type '%s'", model.Type)
 }
 
+// GetMetricPrediction forecasts a metric value for any model that the ModelPredict has been set up to use, if the
+// predicter for the model supports forecasting metrics
+func (m *ModelPredict) GetMetricPrediction(model *jamiethompsonmev1alpha1.Model, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (float64, error) {
+	for _, predicter := range m.Predicters {
+		if predicter.GetType() == model.Type {
+			metricPredicter, ok := predicter.(MetricPredicter)
+			if !ok {
+				return 0, fmt.Errorf("model type '%s' does not support metric input", model.Type)
+			}
+			return metricPredicter.GetMetricPrediction(model, metricHistory)
+		}
+	}
+	return 0, fmt.Errorf("unknown model type '%s'", model.Type)
+}
+
+// GetPredictionInterval generates a prediction and prediction interval for any model that the ModelPredict has been
+// set up to use, if the predicter for the model does not support prediction intervals the bounds of the interval are
+// the predicted value
+func (m *ModelPredict) GetPredictionInterval(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, confidence float64) (*Interval, error) {
+	for _, predicter := range m.Predicters {
+		if predicter.GetType() == model.Type {
+			intervalPredicter, ok := predicter.(IntervalPredicter)
+			if !ok {
+				prediction, err := predicter.GetPrediction(model, replicaHistory)
+				if err != nil {
+					return nil, err
+				}
+				return &Interval{
+					Prediction: prediction,
+					Lower:      prediction,
+					Upper:      prediction,
+				}, nil
+			}
+			return intervalPredicter.GetPredictionInterval(model, replicaHistory, confidence)
+		}
+	}
+	return nil, fmt.Errorf("unknown model type '%s'", model.Type)
+}
+
+// Tune fits the smoothing parameters of any model that the ModelPredict has been set up to use, if the predicter for
+// the model supports tuning
+func (m *ModelPredict) Tune(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (*jamiethompsonmev1alpha1.HoltWintersTunedParameters, error) {
+	for _, predicter := range m.Predicters {
+		if predicter.GetType() == model.Type {
+			tuner, ok := predicter.(Tuner)
+			if !ok {
+				return nil, fmt.Errorf("model type '%s' does not support tuning", model.Type)
+			}
+			return tuner.Tune(model, replicaHistory)
+		}
+	}
+	return nil, fmt.Errorf("unknown model type '%s'", model.Type)
+}
+
+// FetchTuning runs the runtime tuning fetch hook of any model that the ModelPredict has been set up to use, if the
+// predicter for the model does not support fetching tuning values separately the model is returned unchanged
+func (m *ModelPredict) FetchTuning(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (*jamiethompsonmev1alpha1.Model, error) {
+	for _, predicter := range m.Predicters {
+		if predicter.GetType() == model.Type {
+			tuningFetcher, ok := predicter.(TuningFetcher)
+			if !ok {
+				return model, nil
+			}
+			return tuningFetcher.FetchTuning(model, replicaHistory, metricHistory)
+		}
+	}
+	return nil, fmt.Errorf("unknown model type '%s'", model.Type)
+}
+
+// HasEnoughHistory checks if any model that the ModelPredict has been set up to use has enough history to make a
+// prediction, if the predicter for the model does not check its history it is assumed to have enough
+func (m *ModelPredict) HasEnoughHistory(model *jamiethompsonmev1alpha1.Model, historyLength int) (bool, error) {
+	for _, predicter := range m.Predicters {
+		if predicter.GetType() == model.Type {
+			historyChecker, ok := predicter.(HistoryChecker)
+			if !ok {
+				return true, nil
+			}
+			return historyChecker.HasEnoughHistory(model, historyLength)
+		}
+	}
+	return false, fmt.Errorf("unknown model type '%s'", model.Type)
+}
+
 // GetIDsToRemove finds the appropriate logic for the model and gets a list of stored IDs to remove
 func (m *ModelPredict) PruneHistory(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
 	for _, predicter := range m.Predicters {
@@ -18,6 +18,7 @@ package prediction_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -154,6 +155,579 @@ func TestModelPredict_GetPrediction(t *testing.T) {
 	}
 }
 
+func TestModelPredict_GetMetricPrediction(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description   string
+		expected      float64
+		expectedErr   error
+		predicters    []prediction.Predicter
+		model         *jamiethompsonmev1alpha1.Model
+		metricHistory []jamiethompsonmev1alpha1.TimestampedValue
+	}{
+		{
+			description:   "Unknown model type",
+			expected:      0,
+			expectedErr:   errors.New(`unknown model type 'invalid'`),
+			predicters:    []prediction.Predicter{},
+			model:         &jamiethompsonmev1alpha1.Model{Type: "invalid"},
+			metricHistory: []jamiethompsonmev1alpha1.TimestampedValue{},
+		},
+		{
+			description: "Model type does not support metric input",
+			expected:    0,
+			expectedErr: errors.New(`model type 'test' does not support metric input`),
+			predicters: []prediction.Predicter{
+				&fake.Predicter{
+					GetTypeReactor: func() string {
+						return "test"
+					},
+				},
+			},
+			model:         &jamiethompsonmev1alpha1.Model{Type: "test"},
+			metricHistory: []jamiethompsonmev1alpha1.TimestampedValue{},
+		},
+		{
+			description: "Fail child predicter",
+			expected:    0,
+			expectedErr: errors.New("fail to get metric prediction from child"),
+			predicters: []prediction.Predicter{
+				&fake.MetricPredicter{
+					Predicter: fake.Predicter{
+						GetTypeReactor: func() string {
+							return "test"
+						},
+					},
+					GetMetricPredictionReactor: func(model *jamiethompsonmev1alpha1.Model, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (float64, error) {
+						return 0, errors.New("fail to get metric prediction from child")
+					},
+				},
+			},
+			model:         &jamiethompsonmev1alpha1.Model{Type: "test"},
+			metricHistory: []jamiethompsonmev1alpha1.TimestampedValue{},
+		},
+		{
+			description: "Successful metric prediction, two available models",
+			expected:    1500.5,
+			expectedErr: nil,
+			predicters: []prediction.Predicter{
+				&fake.Predicter{
+					GetTypeReactor: func() string {
+						return "incorrect-model"
+					},
+				},
+				&fake.MetricPredicter{
+					Predicter: fake.Predicter{
+						GetTypeReactor: func() string {
+							return "test"
+						},
+					},
+					GetMetricPredictionReactor: func(model *jamiethompsonmev1alpha1.Model, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (float64, error) {
+						return 1500.5, nil
+					},
+				},
+			},
+			model:         &jamiethompsonmev1alpha1.Model{Type: "test"},
+			metricHistory: []jamiethompsonmev1alpha1.TimestampedValue{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &prediction.ModelPredict{
+				Predicters: test.predicters,
+			}
+			result, err := predicter.GetMetricPrediction(test.model, test.metricHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestModelPredict_GetPredictionInterval(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description    string
+		expected       *prediction.Interval
+		expectedErr    error
+		predicters     []prediction.Predicter
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+		confidence     float64
+	}{
+		{
+			description:    "Unknown model type",
+			expected:       nil,
+			expectedErr:    errors.New(`unknown model type 'invalid'`),
+			predicters:     []prediction.Predicter{},
+			model:          &jamiethompsonmev1alpha1.Model{Type: "invalid"},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			confidence:     0.8,
+		},
+		{
+			description: "Model type does not support intervals, fail child predicter",
+			expected:    nil,
+			expectedErr: errors.New("fail to get prediction from child"),
+			predicters: []prediction.Predicter{
+				&fake.Predicter{
+					GetTypeReactor: func() string {
+						return "test"
+					},
+					GetPredictionReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
+						return 0, errors.New("fail to get prediction from child")
+					},
+				},
+			},
+			model:          &jamiethompsonmev1alpha1.Model{Type: "test"},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			confidence:     0.8,
+		},
+		{
+			description: "Model type does not support intervals, use prediction as bounds",
+			expected: &prediction.Interval{
+				Prediction: 3,
+				Lower:      3,
+				Upper:      3,
+			},
+			expectedErr: nil,
+			predicters: []prediction.Predicter{
+				&fake.Predicter{
+					GetTypeReactor: func() string {
+						return "test"
+					},
+					GetPredictionReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
+						return 3, nil
+					},
+				},
+			},
+			model:          &jamiethompsonmev1alpha1.Model{Type: "test"},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			confidence:     0.8,
+		},
+		{
+			description: "Fail child interval predicter",
+			expected:    nil,
+			expectedErr: errors.New("fail to get prediction interval from child"),
+			predicters: []prediction.Predicter{
+				&fake.IntervalPredicter{
+					Predicter: fake.Predicter{
+						GetTypeReactor: func() string {
+							return "test"
+						},
+					},
+					GetPredictionIntervalReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, confidence float64) (*prediction.Interval, error) {
+						return nil, errors.New("fail to get prediction interval from child")
+					},
+				},
+			},
+			model:          &jamiethompsonmev1alpha1.Model{Type: "test"},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			confidence:     0.8,
+		},
+		{
+			description: "Successful prediction interval, two available models",
+			expected: &prediction.Interval{
+				Prediction: 3,
+				Lower:      1,
+				Upper:      5,
+			},
+			expectedErr: nil,
+			predicters: []prediction.Predicter{
+				&fake.Predicter{
+					GetTypeReactor: func() string {
+						return "incorrect-model"
+					},
+				},
+				&fake.IntervalPredicter{
+					Predicter: fake.Predicter{
+						GetTypeReactor: func() string {
+							return "test"
+						},
+					},
+					GetPredictionIntervalReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, confidence float64) (*prediction.Interval, error) {
+						if confidence != 0.8 {
+							return nil, fmt.Errorf("confidence mismatch, expected 0.8 got %f", confidence)
+						}
+						return &prediction.Interval{
+							Prediction: 3,
+							Lower:      1,
+							Upper:      5,
+						}, nil
+					},
+				},
+			},
+			model:          &jamiethompsonmev1alpha1.Model{Type: "test"},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			confidence:     0.8,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &prediction.ModelPredict{
+				Predicters: test.predicters,
+			}
+			result, err := predicter.GetPredictionInterval(test.model, test.replicaHistory, test.confidence)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestModelPredict_Tune(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description    string
+		expected       *jamiethompsonmev1alpha1.HoltWintersTunedParameters
+		expectedErr    error
+		predicters     []prediction.Predicter
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+	}{
+		{
+			description:    "Unknown model type",
+			expected:       nil,
+			expectedErr:    errors.New(`unknown model type 'invalid'`),
+			predicters:     []prediction.Predicter{},
+			model:          &jamiethompsonmev1alpha1.Model{Type: "invalid"},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+		},
+		{
+			description: "Model type does not support tuning",
+			expected:    nil,
+			expectedErr: errors.New(`model type 'test' does not support tuning`),
+			predicters: []prediction.Predicter{
+				&fake.Predicter{
+					GetTypeReactor: func() string {
+						return "test"
+					},
+				},
+			},
+			model:          &jamiethompsonmev1alpha1.Model{Type: "test"},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+		},
+		{
+			description: "Fail child tuner",
+			expected:    nil,
+			expectedErr: errors.New("fail to tune from child"),
+			predicters: []prediction.Predicter{
+				&fake.Tuner{
+					Predicter: fake.Predicter{
+						GetTypeReactor: func() string {
+							return "test"
+						},
+					},
+					TuneReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (*jamiethompsonmev1alpha1.HoltWintersTunedParameters, error) {
+						return nil, errors.New("fail to tune from child")
+					},
+				},
+			},
+			model:          &jamiethompsonmev1alpha1.Model{Type: "test"},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+		},
+		{
+			description: "Successful tune, two available models",
+			expected: &jamiethompsonmev1alpha1.HoltWintersTunedParameters{
+				Alpha: 0.5,
+				Beta:  0.25,
+				Gamma: 0.75,
+			},
+			expectedErr: nil,
+			predicters: []prediction.Predicter{
+				&fake.Predicter{
+					GetTypeReactor: func() string {
+						return "incorrect-model"
+					},
+				},
+				&fake.Tuner{
+					Predicter: fake.Predicter{
+						GetTypeReactor: func() string {
+							return "test"
+						},
+					},
+					TuneReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (*jamiethompsonmev1alpha1.HoltWintersTunedParameters, error) {
+						return &jamiethompsonmev1alpha1.HoltWintersTunedParameters{
+							Alpha: 0.5,
+							Beta:  0.25,
+							Gamma: 0.75,
+						}, nil
+					},
+				},
+			},
+			model:          &jamiethompsonmev1alpha1.Model{Type: "test"},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &prediction.ModelPredict{
+				Predicters: test.predicters,
+			}
+			result, err := predicter.Tune(test.model, test.replicaHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestModelPredict_FetchTuning(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description    string
+		expected       *jamiethompsonmev1alpha1.Model
+		expectedErr    error
+		predicters     []prediction.Predicter
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+		metricHistory  []jamiethompsonmev1alpha1.TimestampedValue
+	}{
+		{
+			description:    "Unknown model type",
+			expected:       nil,
+			expectedErr:    errors.New(`unknown model type 'invalid'`),
+			predicters:     []prediction.Predicter{},
+			model:          &jamiethompsonmev1alpha1.Model{Type: "invalid"},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			metricHistory:  nil,
+		},
+		{
+			description: "Model type does not support fetching tuning values, model unchanged",
+			expected:    &jamiethompsonmev1alpha1.Model{Type: "test"},
+			expectedErr: nil,
+			predicters: []prediction.Predicter{
+				&fake.Predicter{
+					GetTypeReactor: func() string {
+						return "test"
+					},
+				},
+			},
+			model:          &jamiethompsonmev1alpha1.Model{Type: "test"},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			metricHistory:  nil,
+		},
+		{
+			description: "Fail child tuning fetcher",
+			expected:    nil,
+			expectedErr: errors.New("fail to fetch tuning from child"),
+			predicters: []prediction.Predicter{
+				&fake.TuningFetcher{
+					Predicter: fake.Predicter{
+						GetTypeReactor: func() string {
+							return "test"
+						},
+					},
+					FetchTuningReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (*jamiethompsonmev1alpha1.Model, error) {
+						return nil, errors.New("fail to fetch tuning from child")
+					},
+				},
+			},
+			model:          &jamiethompsonmev1alpha1.Model{Type: "test"},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			metricHistory:  nil,
+		},
+		{
+			description: "Successful fetch, two available models",
+			expected:    &jamiethompsonmev1alpha1.Model{Type: "test", Name: "fetched"},
+			expectedErr: nil,
+			predicters: []prediction.Predicter{
+				&fake.Predicter{
+					GetTypeReactor: func() string {
+						return "incorrect-model"
+					},
+				},
+				&fake.TuningFetcher{
+					Predicter: fake.Predicter{
+						GetTypeReactor: func() string {
+							return "test"
+						},
+					},
+					FetchTuningReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (*jamiethompsonmev1alpha1.Model, error) {
+						return &jamiethompsonmev1alpha1.Model{Type: "test", Name: "fetched"}, nil
+					},
+				},
+			},
+			model:          &jamiethompsonmev1alpha1.Model{Type: "test"},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+			metricHistory:  nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &prediction.ModelPredict{
+				Predicters: test.predicters,
+			}
+			result, err := predicter.FetchTuning(test.model, test.replicaHistory, test.metricHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestModelPredict_HasEnoughHistory(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description   string
+		expected      bool
+		expectedErr   error
+		predicters    []prediction.Predicter
+		model         *jamiethompsonmev1alpha1.Model
+		historyLength int
+	}{
+		{
+			description:   "Unknown model type",
+			expected:      false,
+			expectedErr:   errors.New(`unknown model type 'invalid'`),
+			predicters:    []prediction.Predicter{},
+			model:         &jamiethompsonmev1alpha1.Model{Type: "invalid"},
+			historyLength: 1,
+		},
+		{
+			description: "Model type does not check history, enough history",
+			expected:    true,
+			expectedErr: nil,
+			predicters: []prediction.Predicter{
+				&fake.Predicter{
+					GetTypeReactor: func() string {
+						return "test"
+					},
+				},
+			},
+			model:         &jamiethompsonmev1alpha1.Model{Type: "test"},
+			historyLength: 1,
+		},
+		{
+			description: "Fail child history checker",
+			expected:    false,
+			expectedErr: errors.New("fail to check history from child"),
+			predicters: []prediction.Predicter{
+				&fake.HistoryChecker{
+					Predicter: fake.Predicter{
+						GetTypeReactor: func() string {
+							return "test"
+						},
+					},
+					HasEnoughHistoryReactor: func(model *jamiethompsonmev1alpha1.Model, historyLength int) (bool, error) {
+						return false, errors.New("fail to check history from child")
+					},
+				},
+			},
+			model:         &jamiethompsonmev1alpha1.Model{Type: "test"},
+			historyLength: 1,
+		},
+		{
+			description: "Not enough history, two available models",
+			expected:    false,
+			expectedErr: nil,
+			predicters: []prediction.Predicter{
+				&fake.Predicter{
+					GetTypeReactor: func() string {
+						return "incorrect-model"
+					},
+				},
+				&fake.HistoryChecker{
+					Predicter: fake.Predicter{
+						GetTypeReactor: func() string {
+							return "test"
+						},
+					},
+					HasEnoughHistoryReactor: func(model *jamiethompsonmev1alpha1.Model, historyLength int) (bool, error) {
+						return historyLength >= 5, nil
+					},
+				},
+			},
+			model:         &jamiethompsonmev1alpha1.Model{Type: "test"},
+			historyLength: 4,
+		},
+		{
+			description: "Enough history, two available models",
+			expected:    true,
+			expectedErr: nil,
+			predicters: []prediction.Predicter{
+				&fake.Predicter{
+					GetTypeReactor: func() string {
+						return "incorrect-model"
+					},
+				},
+				&fake.HistoryChecker{
+					Predicter: fake.Predicter{
+						GetTypeReactor: func() string {
+							return "test"
+						},
+					},
+					HasEnoughHistoryReactor: func(model *jamiethompsonmev1alpha1.Model, historyLength int) (bool, error) {
+						return historyLength >= 5, nil
+					},
+				},
+			},
+			model:         &jamiethompsonmev1alpha1.Model{Type: "test"},
+			historyLength: 5,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &prediction.ModelPredict{
+				Predicters: test.predicters,
+			}
+			result, err := predicter.HasEnoughHistory(test.model, test.historyLength)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("enough history mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
 func TestModelPredict_PruneHistory(t *testing.T) {
 	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
 		if x == nil || y == nil {
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/hook"
+)
+
+// Predict provides logic for using a remote prediction service to make a prediction
+type Predict struct {
+	HookExecute hook.Executer
+}
+
+type predictionHookRequest struct {
+	Model          jamiethompsonmev1alpha1.Model                 `json:"model"`
+	ReplicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas `json:"replicaHistory"`
+}
+
+type predictionHookResult struct {
+	Replicas *int32 `json:"replicas"`
+}
+
+// GetPrediction calls the remote prediction service to predict what the replica count should be based on historical
+// evaluations
+func (p *Predict) GetPrediction(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
+	if model.Remote == nil {
+		return 0, errors.New("no Remote configuration provided for model")
+	}
+
+	if len(replicaHistory) == 0 {
+		return 0, errors.New("no evaluations provided for Remote model")
+	}
+
+	// Convert request into JSON string
+	request, err := json.Marshal(&predictionHookRequest{
+		Model:          *model,
+		ReplicaHistory: replicaHistory,
+	})
+	if err != nil {
+		// Should not occur
+		panic(err)
+	}
+
+	// Request prediction
+	hookResult, err := p.HookExecute.ExecuteWithValue(&model.Remote.PredictionHook, string(request))
+	if err != nil {
+		return 0, err
+	}
+
+	// Parse result
+	var result predictionHookResult
+	err = json.Unmarshal([]byte(hookResult), &result)
+	if err != nil {
+		return 0, fmt.Errorf("invalid response from remote prediction: %w", err)
+	}
+
+	if result.Replicas == nil {
+		return 0, errors.New("no replicas value returned from remote prediction")
+	}
+
+	return *result.Replicas, nil
+}
+
+func (p *Predict) PruneHistory(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+	if model.Remote == nil {
+		return nil, errors.New("no Remote configuration provided for model")
+	}
+
+	if len(replicaHistory) < model.Remote.HistorySize {
+		return replicaHistory, nil
+	}
+
+	// Sort by date created, newest first
+	sort.Slice(replicaHistory, func(i, j int) bool {
+		return !replicaHistory[i].Time.Before(replicaHistory[j].Time)
+	})
+
+	// Remove oldest to fit into requirements
+	return replicaHistory[:model.Remote.HistorySize], nil
+}
+
+// GetType returns the type of the Prediction model
+func (p *Predict) GetType() string {
+	return jamiethompsonmev1alpha1.TypeRemote
+}
@@ -0,0 +1,347 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/remote"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPredict_GetPrediction(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description    string
+		expected       int32
+		expectedErr    error
+		predicter      *remote.Predict
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+	}{
+		{
+			description:    "Fail no Remote configuration",
+			expected:       0,
+			expectedErr:    errors.New("no Remote configuration provided for model"),
+			predicter:      &remote.Predict{},
+			model:          &jamiethompsonmev1alpha1.Model{},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+		},
+		{
+			description: "Fail no evaluations",
+			expected:    0,
+			expectedErr: errors.New("no evaluations provided for Remote model"),
+			predicter:   &remote.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeRemote,
+				Remote: &jamiethompsonmev1alpha1.Remote{
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+		},
+		{
+			description: "Fail prediction hook fails",
+			expected:    0,
+			expectedErr: errors.New("hook fail"),
+			predicter: &remote.Predict{
+				HookExecute: &fake.Execute{
+					ExecuteWithValueReactor: func(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+						return "", errors.New("hook fail")
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeRemote,
+				Remote: &jamiethompsonmev1alpha1.Remote{
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Fail prediction hook returns invalid JSON",
+			expected:    0,
+			expectedErr: errors.New("invalid response from remote prediction: invalid character 'i' looking for beginning of value"),
+			predicter: &remote.Predict{
+				HookExecute: &fake.Execute{
+					ExecuteWithValueReactor: func(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+						return "invalid", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeRemote,
+				Remote: &jamiethompsonmev1alpha1.Remote{
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Fail prediction hook returns no replicas",
+			expected:    0,
+			expectedErr: errors.New("no replicas value returned from remote prediction"),
+			predicter: &remote.Predict{
+				HookExecute: &fake.Execute{
+					ExecuteWithValueReactor: func(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+						return "{}", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeRemote,
+				Remote: &jamiethompsonmev1alpha1.Remote{
+					HistorySize: 5,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			description: "Success",
+			expected:    7,
+			expectedErr: nil,
+			predicter: &remote.Predict{
+				HookExecute: &fake.Execute{
+					ExecuteWithValueReactor: func(definition *jamiethompsonmev1alpha1.HookDefinition, value string) (string, error) {
+						expectedDefinition := &jamiethompsonmev1alpha1.HookDefinition{
+							Type:    jamiethompsonmev1alpha1.HookTypeGRPC,
+							Timeout: 2500,
+							GRPC: &jamiethompsonmev1alpha1.GRPCHook{
+								Address: "prediction:50051",
+							},
+						}
+						if !cmp.Equal(definition, expectedDefinition) {
+							return "", fmt.Errorf("definition mismatch (-want +got):\n%s", cmp.Diff(expectedDefinition, definition))
+						}
+
+						// Only check the parts of the request the remote predicter provides, the model and the replica
+						// history, rather than every field of the model
+						var request struct {
+							Model struct {
+								Type   string                          `json:"type"`
+								Name   string                          `json:"name"`
+								Remote *jamiethompsonmev1alpha1.Remote `json:"remote"`
+							} `json:"model"`
+							ReplicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas `json:"replicaHistory"`
+						}
+						err := json.Unmarshal([]byte(value), &request)
+						if err != nil {
+							return "", fmt.Errorf("invalid request: %w", err)
+						}
+						if !cmp.Equal(request.Model.Type, jamiethompsonmev1alpha1.TypeRemote) {
+							return "", fmt.Errorf("model type mismatch (-want +got):\n%s", cmp.Diff(jamiethompsonmev1alpha1.TypeRemote, request.Model.Type))
+						}
+						if !cmp.Equal(request.Model.Name, "remote") {
+							return "", fmt.Errorf("model name mismatch (-want +got):\n%s", cmp.Diff("remote", request.Model.Name))
+						}
+						expectedRemote := &jamiethompsonmev1alpha1.Remote{
+							HistorySize:    5,
+							PredictionHook: *expectedDefinition,
+						}
+						if !cmp.Equal(request.Model.Remote, expectedRemote) {
+							return "", fmt.Errorf("model remote mismatch (-want +got):\n%s", cmp.Diff(expectedRemote, request.Model.Remote))
+						}
+						expectedReplicaHistory := []jamiethompsonmev1alpha1.TimestampedReplicas{
+							{
+								Replicas: 5,
+								Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+							},
+						}
+						if !cmp.Equal(request.ReplicaHistory, expectedReplicaHistory) {
+							return "", fmt.Errorf("replica history mismatch (-want +got):\n%s", cmp.Diff(expectedReplicaHistory, request.ReplicaHistory))
+						}
+						return `{"replicas": 7}`, nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeRemote,
+				Name: "remote",
+				Remote: &jamiethompsonmev1alpha1.Remote{
+					HistorySize: 5,
+					PredictionHook: jamiethompsonmev1alpha1.HookDefinition{
+						Type:    jamiethompsonmev1alpha1.HookTypeGRPC,
+						Timeout: 2500,
+						GRPC: &jamiethompsonmev1alpha1.GRPCHook{
+							Address: "prediction:50051",
+						},
+					},
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 5,
+					Time:     &metav1.Time{Time: time.Date(2020, time.February, 1, 0, 55, 33, 0, time.UTC)},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.predicter.GetPrediction(test.model, test.replicaHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestModelPredict_PruneHistory(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description    string
+		expected       []jamiethompsonmev1alpha1.TimestampedReplicas
+		expectedErr    error
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+	}{
+		{
+			description:    "Fail no Remote configuration",
+			expected:       nil,
+			expectedErr:    errors.New("no Remote configuration provided for model"),
+			model:          &jamiethompsonmev1alpha1.Model{},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{},
+		},
+		{
+			description: "Only 1 in history, max size 2",
+			expected: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 1,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(4) * time.Second)},
+				},
+			},
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				Remote: &jamiethompsonmev1alpha1.Remote{
+					HistorySize: 2,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 1,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(4) * time.Second)},
+				},
+			},
+		},
+		{
+			description: "1 too many, remove oldest",
+			expected: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 3,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(3) * time.Second)},
+				},
+				{
+					Replicas: 2,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(2) * time.Second)},
+				},
+			},
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				Remote: &jamiethompsonmev1alpha1.Remote{
+					HistorySize: 2,
+				},
+			},
+			replicaHistory: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				{
+					Replicas: 2,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(2) * time.Second)},
+				},
+				{
+					Replicas: 1,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(1) * time.Second)},
+				},
+				{
+					Replicas: 3,
+					Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(3) * time.Second)},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &remote.Predict{}
+			result, err := predicter.PruneHistory(test.model, test.replicaHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("remove IDs mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_GetType(t *testing.T) {
+	var tests = []struct {
+		description string
+		expected    string
+	}{
+		{
+			description: "Successful get type",
+			expected:    "Remote",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &remote.Predict{}
+			result := predicter.GetType()
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("type mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
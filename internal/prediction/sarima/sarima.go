@@ -0,0 +1,203 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sarima
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+)
+
+const algorithmPath = "algorithms/sarima/sarima.py"
+
+const (
+	defaultTimeout = 30000
+)
+
+// AlgorithmRunner defines an algorithm runner, allowing algorithms to be run
+type AlgorithmRunner interface {
+	RunAlgorithmWithValue(algorithmPath string, value string, timeout int) (string, error)
+}
+
+// Predict provides logic for using SARIMA to make a prediction
+type Predict struct {
+	Runner AlgorithmRunner
+}
+
+type sarimaParameters struct {
+	Series        []float64 `json:"series"`
+	Order         []int     `json:"order"`
+	SeasonalOrder []int     `json:"seasonalOrder"`
+	Trend         *string   `json:"trend,omitempty"`
+}
+
+// GetPrediction uses SARIMA to predict what the replica count should be based on historical evaluations
+func (p *Predict) GetPrediction(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
+	history := make([]jamiethompsonmev1alpha1.TimestampedValue, len(replicaHistory))
+	for i, timestampedReplica := range replicaHistory {
+		history[i] = jamiethompsonmev1alpha1.TimestampedValue{
+			Time:  timestampedReplica.Time,
+			Value: float64(timestampedReplica.Replicas),
+		}
+	}
+
+	prediction, err := p.predict(model, history)
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(math.Ceil(prediction)), nil
+}
+
+// GetMetricPrediction uses SARIMA to forecast a metric value based on the metric's history
+func (p *Predict) GetMetricPrediction(model *jamiethompsonmev1alpha1.Model, metricHistory []jamiethompsonmev1alpha1.TimestampedValue) (float64, error) {
+	return p.predict(model, metricHistory)
+}
+
+func (p *Predict) predict(model *jamiethompsonmev1alpha1.Model, history []jamiethompsonmev1alpha1.TimestampedValue) (float64, error) {
+	if model.SARIMA == nil {
+		return 0, errors.New("no SARIMA configuration provided for model")
+	}
+
+	order, seasonalOrder := orders(model.SARIMA)
+
+	if len(history) < minimumObservations(order, seasonalOrder) {
+		return 0, nil
+	}
+
+	// Make sure the series is in the order the values were recorded in, oldest first
+	sortedHistory := make([]jamiethompsonmev1alpha1.TimestampedValue, len(history))
+	copy(sortedHistory, history)
+	sort.SliceStable(sortedHistory, func(i, j int) bool {
+		return sortedHistory[i].Time.Before(sortedHistory[j].Time)
+	})
+
+	// Collect data for historical series
+	series := make([]float64, len(sortedHistory))
+	for i, timestampedValue := range sortedHistory {
+		series[i] = timestampedValue.Value
+	}
+
+	parameters, err := json.Marshal(sarimaParameters{
+		Series:        series,
+		Order:         order,
+		SeasonalOrder: seasonalOrder,
+		Trend:         model.SARIMA.Trend,
+	})
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	timeout := defaultTimeout
+	if model.CalculationTimeout != nil {
+		timeout = *model.CalculationTimeout
+	}
+
+	value, err := p.Runner.RunAlgorithmWithValue(algorithmPath, string(parameters), timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	prediction, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(prediction), nil
+}
+
+func (p *Predict) PruneHistory(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+	if model.SARIMA == nil {
+		return nil, errors.New("no SARIMA configuration provided for model")
+	}
+
+	// Sort by date created, newest first
+	sort.Slice(replicaHistory, func(i, j int) bool {
+		return !replicaHistory[i].Time.Before(replicaHistory[j].Time)
+	})
+
+	// If there are too many stored seasons, remove the oldest ones
+
+	// This rounds down in the same way as Holt-Winters, allowing full seasons to build up before pruning the old ones
+	numberOfSeasonsToRemove := len(replicaHistory)/model.SARIMA.SeasonalPeriods - model.SARIMA.StoredSeasons
+	if numberOfSeasonsToRemove <= 0 {
+		return replicaHistory, nil
+	}
+
+	return replicaHistory[:len(replicaHistory)-numberOfSeasonsToRemove*model.SARIMA.SeasonalPeriods], nil
+}
+
+// HasEnoughHistory returns if there are enough observations in the history for statsmodels to fit the model
+func (p *Predict) HasEnoughHistory(model *jamiethompsonmev1alpha1.Model, historyLength int) (bool, error) {
+	if model.SARIMA == nil {
+		return false, errors.New("no SARIMA configuration provided for model")
+	}
+
+	order, seasonalOrder := orders(model.SARIMA)
+
+	return historyLength >= minimumObservations(order, seasonalOrder), nil
+}
+
+// GetType returns the type of the Prediction model
+func (p *Predict) GetType() string {
+	return jamiethompsonmev1alpha1.TypeSARIMA
+}
+
+// orders returns the order and seasonal order of the model in the form statsmodels expects
+func orders(sarima *jamiethompsonmev1alpha1.SARIMA) ([]int, []int) {
+	order := []int{sarima.Order.P, sarima.Order.D, sarima.Order.Q}
+
+	// Without a seasonal order the model is a plain ARIMA model, statsmodels represents this as a seasonal order of
+	// all zeros
+	seasonalOrder := []int{0, 0, 0, 0}
+	if sarima.SeasonalOrder != nil {
+		seasonalOrder = []int{
+			sarima.SeasonalOrder.P,
+			sarima.SeasonalOrder.D,
+			sarima.SeasonalOrder.Q,
+			sarima.SeasonalPeriods,
+		}
+	}
+
+	return order, seasonalOrder
+}
+
+// minimumObservations returns the number of observations needed for statsmodels to fit the model, the algorithm
+// applies the same checks
+func minimumObservations(order []int, seasonalOrder []int) int {
+	p, d, q := order[0], order[1], order[2]
+	seasonalP, seasonalD, seasonalQ, s := seasonalOrder[0], seasonalOrder[1], seasonalOrder[2], seasonalOrder[3]
+
+	required := d + seasonalD*s + max(p+seasonalP*s, q+seasonalQ*s) + 1
+	if s > 1 && required < 2*s {
+		required = 2 * s
+	}
+
+	return required
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,528 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sarima_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/sarima"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// history builds a replica history from the replica counts provided, with the first replica count being the oldest
+func history(replicas ...int32) []jamiethompsonmev1alpha1.TimestampedReplicas {
+	replicaHistory := make([]jamiethompsonmev1alpha1.TimestampedReplicas, len(replicas))
+	for i, replicaCount := range replicas {
+		replicaHistory[i] = jamiethompsonmev1alpha1.TimestampedReplicas{
+			Replicas: replicaCount,
+			Time:     &metav1.Time{Time: time.Time{}.Add(time.Duration(i) * time.Second)},
+		}
+	}
+	return replicaHistory
+}
+
+func TestPredict_GetPrediction(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description    string
+		expected       int32
+		expectedErr    error
+		predicter      *sarima.Predict
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+	}{
+		{
+			description:    "Fail no SARIMA configuration",
+			expected:       0,
+			expectedErr:    errors.New("no SARIMA configuration provided for model"),
+			predicter:      &sarima.Predict{},
+			model:          &jamiethompsonmev1alpha1.Model{},
+			replicaHistory: history(),
+		},
+		{
+			description: "Less than 2 * seasonal periods, return 0",
+			expected:    0,
+			expectedErr: nil,
+			predicter:   &sarima.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeSARIMA,
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					Order:           jamiethompsonmev1alpha1.SARIMAOrder{P: 0, D: 0, Q: 0},
+					SeasonalOrder:   &jamiethompsonmev1alpha1.SARIMAOrder{P: 0, D: 0, Q: 0},
+					SeasonalPeriods: 3,
+					StoredSeasons:   4,
+				},
+			},
+			replicaHistory: history(1, 2, 3, 1, 2),
+		},
+		{
+			description: "Less than required observations for order, return 0",
+			expected:    0,
+			expectedErr: nil,
+			predicter:   &sarima.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeSARIMA,
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					Order:           jamiethompsonmev1alpha1.SARIMAOrder{P: 1, D: 0, Q: 1},
+					SeasonalOrder:   &jamiethompsonmev1alpha1.SARIMAOrder{P: 1, D: 1, Q: 0},
+					SeasonalPeriods: 3,
+					StoredSeasons:   4,
+				},
+			},
+			replicaHistory: history(1, 2, 3, 1, 2, 3, 1),
+		},
+		{
+			description: "Less than required observations for non-seasonal order, return 0",
+			expected:    0,
+			expectedErr: nil,
+			predicter:   &sarima.Predict{},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeSARIMA,
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					Order:           jamiethompsonmev1alpha1.SARIMAOrder{P: 2, D: 1, Q: 1},
+					SeasonalPeriods: 3,
+					StoredSeasons:   4,
+				},
+			},
+			replicaHistory: history(1, 2, 3),
+		},
+		{
+			description: "Fail algorithm runner fails",
+			expected:    0,
+			expectedErr: errors.New("algorithm fail"),
+			predicter: &sarima.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return "", errors.New("algorithm fail")
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeSARIMA,
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					Order:           jamiethompsonmev1alpha1.SARIMAOrder{P: 1, D: 0, Q: 0},
+					SeasonalPeriods: 3,
+					StoredSeasons:   4,
+				},
+			},
+			replicaHistory: history(1, 2, 3, 1, 2, 3),
+		},
+		{
+			description: "Fail algorithm returns non-integer",
+			expected:    0,
+			expectedErr: errors.New(`strconv.Atoi: parsing "invalid": invalid syntax`),
+			predicter: &sarima.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return "invalid", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeSARIMA,
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					Order:           jamiethompsonmev1alpha1.SARIMAOrder{P: 1, D: 0, Q: 0},
+					SeasonalPeriods: 3,
+					StoredSeasons:   4,
+				},
+			},
+			replicaHistory: history(1, 2, 3, 1, 2, 3),
+		},
+		{
+			description: "Success, ARIMA without seasonal order, default timeout",
+			expected:    4,
+			expectedErr: nil,
+			predicter: &sarima.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						expectedValue := `{"series":[1,2,3,4],"order":[1,1,0],"seasonalOrder":[0,0,0,0]}`
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						if !cmp.Equal(timeout, 30000) {
+							return "", fmt.Errorf("timeout mismatch (-want +got):\n%s", cmp.Diff(30000, timeout))
+						}
+						return "4", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeSARIMA,
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					Order:           jamiethompsonmev1alpha1.SARIMAOrder{P: 1, D: 1, Q: 0},
+					SeasonalPeriods: 3,
+					StoredSeasons:   4,
+				},
+			},
+			replicaHistory: history(1, 2, 3, 4),
+		},
+		{
+			description: "Success, SARIMA with unsorted history, trend and custom timeout",
+			expected:    3,
+			expectedErr: nil,
+			predicter: &sarima.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						if !cmp.Equal(algorithmPath, "algorithms/sarima/sarima.py") {
+							return "", fmt.Errorf("algorithm path mismatch (-want +got):\n%s",
+								cmp.Diff("algorithms/sarima/sarima.py", algorithmPath))
+						}
+						expectedValue := `{"series":[1,3,1,1,3,1,1,3,1],"order":[1,0,0],"seasonalOrder":[0,1,1,3],"trend":"c"}`
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						if !cmp.Equal(timeout, 5000) {
+							return "", fmt.Errorf("timeout mismatch (-want +got):\n%s", cmp.Diff(5000, timeout))
+						}
+						return "3", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type:               jamiethompsonmev1alpha1.TypeSARIMA,
+				CalculationTimeout: intPtr(5000),
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					Order:           jamiethompsonmev1alpha1.SARIMAOrder{P: 1, D: 0, Q: 0},
+					SeasonalOrder:   &jamiethompsonmev1alpha1.SARIMAOrder{P: 0, D: 1, Q: 1},
+					SeasonalPeriods: 3,
+					StoredSeasons:   4,
+					Trend:           strPtr("c"),
+				},
+			},
+			replicaHistory: func() []jamiethompsonmev1alpha1.TimestampedReplicas {
+				replicaHistory := history(1, 3, 1, 1, 3, 1, 1, 3, 1)
+				// Newest first, as stored after pruning
+				for i, j := 0, len(replicaHistory)-1; i < j; i, j = i+1, j-1 {
+					replicaHistory[i], replicaHistory[j] = replicaHistory[j], replicaHistory[i]
+				}
+				return replicaHistory
+			}(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.predicter.GetPrediction(test.model, test.replicaHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_GetMetricPrediction(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description   string
+		expected      float64
+		expectedErr   error
+		predicter     *sarima.Predict
+		model         *jamiethompsonmev1alpha1.Model
+		metricHistory []jamiethompsonmev1alpha1.TimestampedValue
+	}{
+		{
+			description:   "Fail no SARIMA configuration",
+			expected:      0,
+			expectedErr:   errors.New("no SARIMA configuration provided for model"),
+			predicter:     &sarima.Predict{},
+			model:         &jamiethompsonmev1alpha1.Model{},
+			metricHistory: []jamiethompsonmev1alpha1.TimestampedValue{},
+		},
+		{
+			description: "Success, unsorted metric history",
+			expected:    2600,
+			expectedErr: nil,
+			predicter: &sarima.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						expectedValue := `{"series":[1000.5,2000,1500],"order":[1,0,0],"seasonalOrder":[0,0,0,0]}`
+						if !cmp.Equal(value, expectedValue) {
+							return "", fmt.Errorf("value mismatch (-want +got):\n%s", cmp.Diff(expectedValue, value))
+						}
+						return "2600", nil
+					},
+				},
+			},
+			model: &jamiethompsonmev1alpha1.Model{
+				Type: jamiethompsonmev1alpha1.TypeSARIMA,
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					Order:           jamiethompsonmev1alpha1.SARIMAOrder{P: 1, D: 0, Q: 0},
+					SeasonalPeriods: 3,
+					StoredSeasons:   4,
+				},
+			},
+			metricHistory: []jamiethompsonmev1alpha1.TimestampedValue{
+				{
+					Value: 1500,
+					Time:  &metav1.Time{Time: time.Time{}.Add(time.Duration(2) * time.Second)},
+				},
+				{
+					Value: 2000,
+					Time:  &metav1.Time{Time: time.Time{}.Add(time.Duration(1) * time.Second)},
+				},
+				{
+					Value: 1000.5,
+					Time:  &metav1.Time{Time: time.Time{}.Add(time.Duration(0) * time.Second)},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.predicter.GetMetricPrediction(test.model, test.metricHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestModelPredict_PruneHistory(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description    string
+		expected       []jamiethompsonmev1alpha1.TimestampedReplicas
+		expectedErr    error
+		model          *jamiethompsonmev1alpha1.Model
+		replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas
+	}{
+		{
+			description:    "Fail no SARIMA configuration",
+			expected:       nil,
+			expectedErr:    errors.New("no SARIMA configuration provided for model"),
+			model:          &jamiethompsonmev1alpha1.Model{},
+			replicaHistory: history(),
+		},
+		{
+			description: "6 in history, seasonal period 2, 3 stored seasons, don't prune",
+			expected: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				history(1, 2, 3, 4, 5, 6)[5],
+				history(1, 2, 3, 4, 5, 6)[4],
+				history(1, 2, 3, 4, 5, 6)[3],
+				history(1, 2, 3, 4, 5, 6)[2],
+				history(1, 2, 3, 4, 5, 6)[1],
+				history(1, 2, 3, 4, 5, 6)[0],
+			},
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					SeasonalPeriods: 2,
+					StoredSeasons:   3,
+				},
+			},
+			replicaHistory: history(1, 2, 3, 4, 5, 6),
+		},
+		{
+			description: "7 in history, seasonal period 2, 3 stored seasons, don't prune",
+			expected: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				history(1, 2, 3, 4, 5, 6, 7)[6],
+				history(1, 2, 3, 4, 5, 6, 7)[5],
+				history(1, 2, 3, 4, 5, 6, 7)[4],
+				history(1, 2, 3, 4, 5, 6, 7)[3],
+				history(1, 2, 3, 4, 5, 6, 7)[2],
+				history(1, 2, 3, 4, 5, 6, 7)[1],
+				history(1, 2, 3, 4, 5, 6, 7)[0],
+			},
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					SeasonalPeriods: 2,
+					StoredSeasons:   3,
+				},
+			},
+			replicaHistory: history(1, 2, 3, 4, 5, 6, 7),
+		},
+		{
+			description: "8 in history, seasonal period 2, 3 stored seasons, prune oldest season",
+			expected: []jamiethompsonmev1alpha1.TimestampedReplicas{
+				history(1, 2, 3, 4, 5, 6, 7, 8)[7],
+				history(1, 2, 3, 4, 5, 6, 7, 8)[6],
+				history(1, 2, 3, 4, 5, 6, 7, 8)[5],
+				history(1, 2, 3, 4, 5, 6, 7, 8)[4],
+				history(1, 2, 3, 4, 5, 6, 7, 8)[3],
+				history(1, 2, 3, 4, 5, 6, 7, 8)[2],
+			},
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					SeasonalPeriods: 2,
+					StoredSeasons:   3,
+				},
+			},
+			replicaHistory: history(1, 2, 3, 4, 5, 6, 7, 8),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &sarima.Predict{}
+			result, err := predicter.PruneHistory(test.model, test.replicaHistory)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("remove IDs mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_HasEnoughHistory(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description   string
+		expected      bool
+		expectedErr   error
+		model         *jamiethompsonmev1alpha1.Model
+		historyLength int
+	}{
+		{
+			description:   "Fail no SARIMA configuration",
+			expected:      false,
+			expectedErr:   errors.New("no SARIMA configuration provided for model"),
+			model:         &jamiethompsonmev1alpha1.Model{},
+			historyLength: 10,
+		},
+		{
+			description: "ARIMA, 3 in history, 4 required, not enough",
+			expected:    false,
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					Order: jamiethompsonmev1alpha1.SARIMAOrder{P: 2, D: 1, Q: 0},
+				},
+			},
+			historyLength: 3,
+		},
+		{
+			description: "ARIMA, 4 in history, 4 required, enough",
+			expected:    true,
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					Order: jamiethompsonmev1alpha1.SARIMAOrder{P: 2, D: 1, Q: 0},
+				},
+			},
+			historyLength: 4,
+		},
+		{
+			description: "SARIMA, 10 in history, 11 required, not enough",
+			expected:    false,
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					Order:           jamiethompsonmev1alpha1.SARIMAOrder{P: 1, D: 1, Q: 1},
+					SeasonalOrder:   &jamiethompsonmev1alpha1.SARIMAOrder{P: 1, D: 1, Q: 1},
+					SeasonalPeriods: 4,
+				},
+			},
+			historyLength: 10,
+		},
+		{
+			description: "SARIMA, 11 in history, 11 required, enough",
+			expected:    true,
+			expectedErr: nil,
+			model: &jamiethompsonmev1alpha1.Model{
+				SARIMA: &jamiethompsonmev1alpha1.SARIMA{
+					Order:           jamiethompsonmev1alpha1.SARIMAOrder{P: 1, D: 1, Q: 1},
+					SeasonalOrder:   &jamiethompsonmev1alpha1.SARIMAOrder{P: 1, D: 1, Q: 1},
+					SeasonalPeriods: 4,
+				},
+			},
+			historyLength: 11,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &sarima.Predict{}
+			result, err := predicter.HasEnoughHistory(test.model, test.historyLength)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("enough history mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredict_GetType(t *testing.T) {
+	var tests = []struct {
+		description string
+		expected    string
+	}{
+		{
+			description: "Successful get type",
+			expected:    "SARIMA",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			predicter := &sarima.Predict{}
+			result := predicter.GetType()
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("type mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
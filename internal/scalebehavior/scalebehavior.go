@@ -32,7 +32,24 @@ import (
 	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
 )
 
-func DecideTargetReplicasByScalingStrategy(decisionType string, predictedReplicas []int32) int32 {
+// PredictedReplicas is a replica count predicted by a model, or calculated by the HPA logic, along with the weight to
+// give it when using the weighted decision type
+type PredictedReplicas struct {
+	Name     string
+	Replicas int32
+	Weight   float64
+}
+
+func DecideTargetReplicasByScalingStrategy(decisionType string, predictions []PredictedReplicas) int32 {
+
+	if decisionType == jamiethompsonmev1alpha1.DecisionWeighted {
+		return decideWeightedTargetReplicas(predictions)
+	}
+
+	predictedReplicas := make([]int32, len(predictions))
+	for i, prediction := range predictions {
+		predictedReplicas[i] = prediction.Replicas
+	}
 
 	// Sort in ascending order
 	sort.Slice(predictedReplicas, func(i, j int) bool { return predictedReplicas[i] < predictedReplicas[j] })
@@ -40,7 +57,8 @@ func DecideTargetReplicasByScalingStrategy(decisionType string, predictedReplica
 	// Decide which replica count to use based on decision type
 	var targetReplicas int32
 	switch decisionType {
-	case jamiethompsonmev1alpha1.DecisionMaximum:
+	case jamiethompsonmev1alpha1.DecisionMaximum, jamiethompsonmev1alpha1.DecisionQuantile:
+		// The quantile decision type has already used the upper bounds of the predictions, so pick the highest
 		max := int32(0)
 		for i, predictedReplica := range predictedReplicas {
 			if i == 0 || predictedReplica > max {
@@ -85,6 +103,23 @@ func DecideTargetReplicasByScalingStrategy(decisionType string, predictedReplica
 	return targetReplicas
 }
 
+// decideWeightedTargetReplicas calculates the weighted mean of the predicted replicas (rounded to nearest integer), if
+// every prediction has a weight of 0 this falls back to the unweighted mean
+func decideWeightedTargetReplicas(predictions []PredictedReplicas) int32 {
+	totalWeight := float64(0)
+	weightedTotal := float64(0)
+	for _, prediction := range predictions {
+		totalWeight += prediction.Weight
+		weightedTotal += prediction.Weight * float64(prediction.Replicas)
+	}
+
+	if totalWeight <= 0 {
+		return DecideTargetReplicasByScalingStrategy(jamiethompsonmev1alpha1.DecisionMean, predictions)
+	}
+
+	return int32(math.Round(weightedTotal / totalWeight))
+}
+
 func DecideTargetReplicasByBehavior(
 	behavior *autoscalingv2.HorizontalPodAutoscalerBehavior, currentReplicas int32, targetReplicas int32,
 	minReplicas int32, maxReplicas int32,
@@ -197,61 +197,93 @@ func TestDecideTargetReplicasByScalingStrategy(t *testing.T) {
 		description       string
 		expected          int32
 		decisionType      string
-		predictedReplicas []int32
+		predictedReplicas []scalebehavior.PredictedReplicas
 	}{
 		{
 			description:       "Max decision type, no predicted replicas",
 			expected:          0,
 			decisionType:      jamiethompsonmev1alpha1.DecisionMaximum,
-			predictedReplicas: []int32{},
+			predictedReplicas: unweightedPredictions(),
 		},
 		{
 			description:       "Max decision type, 5 predicted replicas",
 			expected:          15,
 			decisionType:      jamiethompsonmev1alpha1.DecisionMaximum,
-			predictedReplicas: []int32{1, 10, 8, 15, 0},
+			predictedReplicas: unweightedPredictions(1, 10, 8, 15, 0),
+		},
+		{
+			description:       "Quantile decision type, 5 predicted replicas",
+			expected:          15,
+			decisionType:      jamiethompsonmev1alpha1.DecisionQuantile,
+			predictedReplicas: unweightedPredictions(1, 10, 8, 15, 0),
 		},
 		{
 			description:       "Min decision type, no predicted replicas",
 			expected:          0,
 			decisionType:      jamiethompsonmev1alpha1.DecisionMinimum,
-			predictedReplicas: []int32{},
+			predictedReplicas: unweightedPredictions(),
 		},
 		{
 			description:       "Min decision type, 5 predicted replicas",
 			expected:          0,
 			decisionType:      jamiethompsonmev1alpha1.DecisionMinimum,
-			predictedReplicas: []int32{1, 10, 8, 15, 0},
+			predictedReplicas: unweightedPredictions(1, 10, 8, 15, 0),
 		},
 		{
 			description:       "Mean decision type, no predicted replicas",
 			expected:          0,
 			decisionType:      jamiethompsonmev1alpha1.DecisionMean,
-			predictedReplicas: []int32{},
+			predictedReplicas: unweightedPredictions(),
 		},
 		{
 			description:       "Mean decision type, 5 predicted replicas",
 			expected:          7,
 			decisionType:      jamiethompsonmev1alpha1.DecisionMean,
-			predictedReplicas: []int32{1, 10, 8, 15, 0},
+			predictedReplicas: unweightedPredictions(1, 10, 8, 15, 0),
 		},
 		{
 			description:       "Median decision type, no predicted replicas",
 			expected:          0,
 			decisionType:      jamiethompsonmev1alpha1.DecisionMedian,
-			predictedReplicas: []int32{},
+			predictedReplicas: unweightedPredictions(),
 		},
 		{
 			description:       "Median decision type, 5 predicted replicas",
 			expected:          8,
 			decisionType:      jamiethompsonmev1alpha1.DecisionMedian,
-			predictedReplicas: []int32{1, 10, 8, 15, 0},
+			predictedReplicas: unweightedPredictions(1, 10, 8, 15, 0),
 		},
 		{
 			description:       "Median decision type, 6 predicted replicas",
 			expected:          7,
 			decisionType:      jamiethompsonmev1alpha1.DecisionMedian,
-			predictedReplicas: []int32{1, 10, 8, 15, 0, 7},
+			predictedReplicas: unweightedPredictions(1, 10, 8, 15, 0, 7),
+		},
+		{
+			description:       "Weighted decision type, no predicted replicas",
+			expected:          0,
+			decisionType:      jamiethompsonmev1alpha1.DecisionWeighted,
+			predictedReplicas: []scalebehavior.PredictedReplicas{},
+		},
+		{
+			description:  "Weighted decision type, 3 predicted replicas",
+			expected:     8,
+			decisionType: jamiethompsonmev1alpha1.DecisionWeighted,
+			predictedReplicas: []scalebehavior.PredictedReplicas{
+				{Name: "calculated", Replicas: 2, Weight: 1},
+				{Name: "linear", Replicas: 10, Weight: 3},
+				{Name: "holt-winters", Replicas: 15, Weight: 0},
+			},
+		},
+		{
+			description:  "Weighted decision type, all weights 0, use mean",
+			expected:     9,
+			decisionType: jamiethompsonmev1alpha1.DecisionWeighted,
+			predictedReplicas: []scalebehavior.PredictedReplicas{
+				{Name: "calculated", Replicas: 2, Weight: 0},
+				{Name: "linear", Replicas: 10, Weight: 0},
+				{Name: "holt-winters", Replicas: 15, Weight: 0},
+			},
 		},
 	}
 	for _, test := range tests {
@@ -1012,3 +1044,14 @@ func int32Ptr(i int32) *int32 {
 func selectPolicyPtr(policy autoscalingv2.ScalingPolicySelect) *autoscalingv2.ScalingPolicySelect {
 	return &policy
 }
+
+func unweightedPredictions(replicas ...int32) []scalebehavior.PredictedReplicas {
+	predictions := make([]scalebehavior.PredictedReplicas, len(replicas))
+	for i, replica := range replicas {
+		predictions[i] = scalebehavior.PredictedReplicas{
+			Replicas: replica,
+			Weight:   1,
+		}
+	}
+	return predictions
+}
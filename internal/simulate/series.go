@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// FormatCSV is a series of observations in CSV format, with a header row naming the columns. An optional 'time'
+	// column holds the RFC 3339 time of each observation, a 'replicas' column holds calculated replica counts, and any
+	// other columns hold metric values in the same order as the PHPA's metrics
+	FormatCSV = "csv"
+	// FormatJSON is a series of observations in JSON format, as a list of observations
+	FormatJSON = "json"
+)
+
+const (
+	timeColumn     = "time"
+	replicasColumn = "replicas"
+)
+
+// ReadSeries reads a series of observations in the format provided
+func ReadSeries(reader io.Reader, format string) ([]Observation, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(reader)
+	case FormatJSON:
+		var series []Observation
+		err := json.NewDecoder(reader).Decode(&series)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON series: %w", err)
+		}
+		return series, nil
+	}
+	return nil, fmt.Errorf("unknown series format '%s'", format)
+}
+
+func readCSV(reader io.Reader) ([]Observation, error) {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV series: %w", err)
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("no header row provided in CSV series")
+	}
+
+	timeIndex := -1
+	replicasIndex := -1
+	var metricIndexes []int
+	for i, column := range records[0] {
+		switch strings.TrimSpace(column) {
+		case timeColumn:
+			timeIndex = i
+		case replicasColumn:
+			replicasIndex = i
+		default:
+			metricIndexes = append(metricIndexes, i)
+		}
+	}
+
+	if replicasIndex != -1 && len(metricIndexes) > 0 {
+		return nil, errors.New("CSV series must have either a replicas column or metric columns, not both")
+	}
+
+	if replicasIndex == -1 && len(metricIndexes) == 0 {
+		return nil, errors.New("CSV series must have either a replicas column or metric columns")
+	}
+
+	series := make([]Observation, len(records)-1)
+	for i, record := range records[1:] {
+		row := i + 2
+
+		if timeIndex != -1 {
+			observationTime, err := time.Parse(time.RFC3339, strings.TrimSpace(record[timeIndex]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid time on row %d: %w", row, err)
+			}
+			series[i].Time = &observationTime
+		}
+
+		if replicasIndex != -1 {
+			replicas, err := strconv.ParseInt(strings.TrimSpace(record[replicasIndex]), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid replicas on row %d: %w", row, err)
+			}
+			replicas32 := int32(replicas)
+			series[i].Replicas = &replicas32
+			continue
+		}
+
+		series[i].Metrics = make([]float64, len(metricIndexes))
+		for j, index := range metricIndexes {
+			metricValue, err := strconv.ParseFloat(strings.TrimSpace(record[index]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid metric value in column '%s' on row %d: %w", records[0][index], row,
+					err)
+			}
+			series[i].Metrics[j] = metricValue
+		}
+	}
+
+	return series, nil
+}
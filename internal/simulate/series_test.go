@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/simulate"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestReadSeries(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description string
+		expected    []simulate.Observation
+		expectedErr error
+		input       string
+		format      string
+	}{
+		{
+			description: "Fail, unknown format",
+			expected:    nil,
+			expectedErr: errors.New("unknown series format 'xml'"),
+			input:       "",
+			format:      "xml",
+		},
+		{
+			description: "Fail, invalid JSON",
+			expected:    nil,
+			expectedErr: errors.New("failed to parse JSON series: invalid character 'i' looking for beginning of value"),
+			input:       "invalid",
+			format:      simulate.FormatJSON,
+		},
+		{
+			description: "Fail, empty CSV",
+			expected:    nil,
+			expectedErr: errors.New("no header row provided in CSV series"),
+			input:       "",
+			format:      simulate.FormatCSV,
+		},
+		{
+			description: "Fail, CSV with replicas and metric columns",
+			expected:    nil,
+			expectedErr: errors.New("CSV series must have either a replicas column or metric columns, not both"),
+			input:       "replicas,requests\n1,2\n",
+			format:      simulate.FormatCSV,
+		},
+		{
+			description: "Fail, CSV with only a time column",
+			expected:    nil,
+			expectedErr: errors.New("CSV series must have either a replicas column or metric columns"),
+			input:       "time\n2022-01-01T00:00:00Z\n",
+			format:      simulate.FormatCSV,
+		},
+		{
+			description: "Fail, CSV with invalid time",
+			expected:    nil,
+			expectedErr: errors.New(`invalid time on row 2: parsing time "invalid" as "2006-01-02T15:04:05Z07:00": cannot parse "invalid" as "2006"`),
+			input:       "time,replicas\ninvalid,1\n",
+			format:      simulate.FormatCSV,
+		},
+		{
+			description: "Fail, CSV with invalid replicas",
+			expected:    nil,
+			expectedErr: errors.New(`invalid replicas on row 3: strconv.ParseInt: parsing "invalid": invalid syntax`),
+			input:       "replicas\n1\ninvalid\n",
+			format:      simulate.FormatCSV,
+		},
+		{
+			description: "Fail, CSV with invalid metric value",
+			expected:    nil,
+			expectedErr: errors.New(`invalid metric value in column 'latency' on row 2: strconv.ParseFloat: parsing "invalid": invalid syntax`),
+			input:       "requests,latency\n1,invalid\n",
+			format:      simulate.FormatCSV,
+		},
+		{
+			description: "Success, CSV replicas without times",
+			expected: []simulate.Observation{
+				{Replicas: int32Ptr(1)},
+				{Replicas: int32Ptr(3)},
+				{Replicas: int32Ptr(2)},
+			},
+			expectedErr: nil,
+			input:       "replicas\n1\n3\n2\n",
+			format:      simulate.FormatCSV,
+		},
+		{
+			description: "Success, CSV metrics with times",
+			expected: []simulate.Observation{
+				{
+					Time:    timePtr(time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)),
+					Metrics: []float64{10, 0.5},
+				},
+				{
+					Time:    timePtr(time.Date(2022, time.January, 1, 0, 0, 15, 0, time.UTC)),
+					Metrics: []float64{12.5, 0.25},
+				},
+			},
+			expectedErr: nil,
+			input:       "time, requests, latency\n2022-01-01T00:00:00Z, 10, 0.5\n2022-01-01T00:00:15Z, 12.5, 0.25\n",
+			format:      simulate.FormatCSV,
+		},
+		{
+			description: "Success, JSON",
+			expected: []simulate.Observation{
+				{
+					Time:     timePtr(time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)),
+					Replicas: int32Ptr(2),
+				},
+				{
+					Metrics: []float64{10},
+				},
+			},
+			expectedErr: nil,
+			input:       `[{"time":"2022-01-01T00:00:00Z","replicas":2},{"metrics":[10]}]`,
+			format:      simulate.FormatJSON,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := simulate.ReadSeries(strings.NewReader(test.input), test.format)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("values mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
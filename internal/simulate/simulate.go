@@ -0,0 +1,248 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulate provides functionality for replaying a recorded time series against a PHPA offline, using a
+// virtual clock to run the PHPA's models, decision type and scaling behavior without a Kubernetes cluster.
+package simulate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics/external"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics/object"
+	"github.com/jthomperoo/k8shorizmetrics/v2/metrics/value"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	testingclock "k8s.io/utils/clock/testing"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/controllers"
+)
+
+// Observation is a single entry in a recorded time series, either the replica count calculated from the metrics or
+// the value of each of the PHPA's metrics. If no time is provided the observation is one sync period after the
+// previous observation
+type Observation struct {
+	Time     *time.Time `json:"time,omitempty"`
+	Replicas *int32     `json:"replicas,omitempty"`
+	Metrics  []float64  `json:"metrics,omitempty"`
+}
+
+// Step is the result of a single simulated sync period, the current replicas are the replicas the target had during
+// the sync period and the target replicas are the replicas it was scaled to
+type Step struct {
+	Time               time.Time `json:"time"`
+	CurrentReplicas    int32     `json:"currentReplicas"`
+	CalculatedReplicas int32     `json:"calculatedReplicas"`
+	DecidedReplicas    int32     `json:"decidedReplicas"`
+	TargetReplicas     int32     `json:"targetReplicas"`
+}
+
+// Provisioning summarises how well the target was provisioned over the simulation, comparing the replicas the target
+// had during each sync period with the replicas calculated from the metrics for that sync period
+type Provisioning struct {
+	// Syncs is the number of sync periods simulated
+	Syncs int `json:"syncs"`
+	// UnderProvisionedSyncs is the number of sync periods the target had fewer replicas than calculated
+	UnderProvisionedSyncs int `json:"underProvisionedSyncs"`
+	// OverProvisionedSyncs is the number of sync periods the target had more replicas than calculated
+	OverProvisionedSyncs int `json:"overProvisionedSyncs"`
+	// UnderProvisionedReplicas is the total number of replicas missing across every under provisioned sync period
+	UnderProvisionedReplicas int64 `json:"underProvisionedReplicas"`
+	// OverProvisionedReplicas is the total number of extra replicas across every over provisioned sync period
+	OverProvisionedReplicas int64 `json:"overProvisionedReplicas"`
+}
+
+// Result is the result of a simulation, with the replica timeline, provisioning summary and the final status of each
+// model
+type Result struct {
+	Timeline     []Step                                `json:"timeline"`
+	Provisioning Provisioning                          `json:"provisioning"`
+	Models       []jamiethompsonmev1alpha1.ModelStatus `json:"models"`
+}
+
+// Simulator replays time series against a PHPA using the reconciler provided, the reconciler does not need any
+// Kubernetes clients set up since only its predicter, evaluator and event recorder are used. The clock is set to the
+// time of each simulated sync period, so it should be the clock used by the reconciler and its predicters
+type Simulator struct {
+	Reconciler *controllers.PredictiveHorizontalPodAutoscalerReconciler
+	Clock      *testingclock.FakePassiveClock
+}
+
+// Run simulates the PHPA for every observation in the series, starting at the start time with the target at the
+// initial replica count. The target is scaled to the target replicas decided at each sync period
+func (s *Simulator) Run(ctx context.Context, instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler,
+	series []Observation, start time.Time, initialReplicas int32) (*Result, error) {
+	if len(series) == 0 {
+		return nil, errors.New("no observations provided to simulate")
+	}
+
+	// Work on a copy so the PHPA provided is not modified by the simulation
+	instance = instance.DeepCopy()
+	instance.Status = jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerStatus{}
+
+	phpaData := &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerData{
+		ModelHistories: map[string]jamiethompsonmev1alpha1.ModelHistory{},
+	}
+
+	syncPeriod := controllers.SyncPeriod(instance)
+	now := start
+	currentReplicas := initialReplicas
+
+	result := &Result{}
+	for i, entry := range series {
+		if entry.Time != nil {
+			if i > 0 && !entry.Time.After(now) {
+				return nil, fmt.Errorf("observation %d is not after the previous observation", i)
+			}
+			now = *entry.Time
+		} else if i > 0 {
+			now = now.Add(syncPeriod)
+		}
+
+		observation, err := observe(instance, entry, currentReplicas, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid observation %d: %w", i, err)
+		}
+
+		s.Clock.SetTime(now)
+
+		sync, err := s.Reconciler.SimulateSync(ctx, instance, phpaData, currentReplicas, *observation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to simulate observation %d: %w", i, err)
+		}
+
+		result.Timeline = append(result.Timeline, Step{
+			Time:               now,
+			CurrentReplicas:    currentReplicas,
+			CalculatedReplicas: sync.CalculatedReplicas,
+			DecidedReplicas:    sync.DecidedReplicas,
+			TargetReplicas:     sync.TargetReplicas,
+		})
+
+		currentReplicas = sync.TargetReplicas
+	}
+
+	result.Provisioning = provisioning(result.Timeline)
+	result.Models = instance.Status.Models
+
+	return result, nil
+}
+
+// observe converts an entry in the series into an observation, converting metric values into gathered metrics
+func observe(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler, entry Observation,
+	currentReplicas int32, now time.Time) (*controllers.SimulatedObservation, error) {
+	if entry.Replicas != nil {
+		return &controllers.SimulatedObservation{
+			CalculatedReplicas: entry.Replicas,
+		}, nil
+	}
+
+	if entry.Metrics == nil {
+		return nil, errors.New("no replicas or metric values provided")
+	}
+
+	gatheredMetrics, err := gather(instance.Spec.Metrics, entry.Metrics, currentReplicas, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &controllers.SimulatedObservation{
+		GatheredMetrics: gatheredMetrics,
+	}, nil
+}
+
+// gather builds gathered metrics from the metric specs and the recorded value of each metric, in the same order as
+// the specs. Only object and external metrics are supported, since resource and pods metrics are gathered per pod
+func gather(specs []autoscalingv2.MetricSpec, values []float64, currentReplicas int32,
+	now time.Time) ([]*metrics.Metric, error) {
+	if len(specs) != len(values) {
+		return nil, fmt.Errorf("mismatch between number of metrics in spec (%d) and metric values (%d)", len(specs),
+			len(values))
+	}
+
+	readyPodCount := int64(currentReplicas)
+
+	gatheredMetrics := make([]*metrics.Metric, len(specs))
+	for i, spec := range specs {
+		// Metric values are evaluated as milli values, the same as the values gathered from the metrics APIs
+		milliValue := int64(math.Round(values[i] * 1000))
+
+		switch spec.Type {
+		case autoscalingv2.ObjectMetricSourceType:
+			if spec.Object == nil {
+				return nil, fmt.Errorf("missing object metric spec for metric %d", i)
+			}
+			current := value.MetricValue{Value: &milliValue}
+			if spec.Object.Target.Type == autoscalingv2.AverageValueMetricType {
+				current = value.MetricValue{AverageValue: &milliValue}
+			}
+			gatheredMetrics[i] = &metrics.Metric{
+				Spec: spec,
+				Object: &object.Metric{
+					Current:       current,
+					ReadyPodCount: &readyPodCount,
+					Timestamp:     now,
+				},
+			}
+		case autoscalingv2.ExternalMetricSourceType:
+			if spec.External == nil {
+				return nil, fmt.Errorf("missing external metric spec for metric %d", i)
+			}
+			current := value.MetricValue{Value: &milliValue}
+			if spec.External.Target.AverageValue != nil {
+				current = value.MetricValue{AverageValue: &milliValue}
+			}
+			gatheredMetrics[i] = &metrics.Metric{
+				Spec: spec,
+				External: &external.Metric{
+					Current:       current,
+					ReadyPodCount: &readyPodCount,
+					Timestamp:     now,
+				},
+			}
+		default:
+			return nil, fmt.Errorf("unsupported metric source type '%s' for metric %d, only Object and External "+
+				"metrics can be simulated from metric values", spec.Type, i)
+		}
+	}
+
+	return gatheredMetrics, nil
+}
+
+// provisioning summarises how well provisioned the target was in each step of the timeline
+func provisioning(timeline []Step) Provisioning {
+	summary := Provisioning{
+		Syncs: len(timeline),
+	}
+
+	for _, step := range timeline {
+		difference := int64(step.CurrentReplicas) - int64(step.CalculatedReplicas)
+		if difference < 0 {
+			summary.UnderProvisionedSyncs++
+			summary.UnderProvisionedReplicas -= difference
+		} else if difference > 0 {
+			summary.OverProvisionedSyncs++
+			summary.OverProvisionedReplicas += difference
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,373 @@
+/*
+Copyright 2023 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jthomperoo/k8shorizmetrics/v2"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	testingclock "k8s.io/utils/clock/testing"
+
+	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/controllers"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/simulate"
+)
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func TestSimulator_Run(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	start := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	objectMetric := autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ObjectMetricSourceType,
+		Object: &autoscalingv2.ObjectMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{
+				Name: "requests",
+			},
+			Target: autoscalingv2.MetricTarget{
+				Type:  autoscalingv2.ValueMetricType,
+				Value: resource.NewQuantity(10, resource.DecimalSI),
+			},
+		},
+	}
+
+	var tests = []struct {
+		description     string
+		expected        *simulate.Result
+		expectedErr     error
+		simulator       simulate.Simulator
+		instance        *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler
+		series          []simulate.Observation
+		initialReplicas int32
+	}{
+		{
+			description: "Fail, no observations",
+			expected:    nil,
+			expectedErr: errors.New("no observations provided to simulate"),
+			simulator: simulate.Simulator{
+				Reconciler: &controllers.PredictiveHorizontalPodAutoscalerReconciler{},
+			},
+			instance: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+				Spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+					MaxReplicas: 10,
+				},
+			},
+			series:          []simulate.Observation{},
+			initialReplicas: 1,
+		},
+		{
+			description: "Fail, observation time not after previous observation",
+			expected:    nil,
+			expectedErr: errors.New("observation 1 is not after the previous observation"),
+			simulator: simulate.Simulator{
+				Reconciler: &controllers.PredictiveHorizontalPodAutoscalerReconciler{
+					Recorder: &record.FakeRecorder{},
+				},
+			},
+			instance: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+				Spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+					MaxReplicas: 10,
+				},
+			},
+			series: []simulate.Observation{
+				{Time: timePtr(start), Replicas: int32Ptr(1)},
+				{Time: timePtr(start), Replicas: int32Ptr(2)},
+			},
+			initialReplicas: 1,
+		},
+		{
+			description: "Fail, observation with no replicas or metrics",
+			expected:    nil,
+			expectedErr: errors.New("invalid observation 0: no replicas or metric values provided"),
+			simulator: simulate.Simulator{
+				Reconciler: &controllers.PredictiveHorizontalPodAutoscalerReconciler{},
+			},
+			instance: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+				Spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+					MaxReplicas: 10,
+				},
+			},
+			series: []simulate.Observation{
+				{},
+			},
+			initialReplicas: 1,
+		},
+		{
+			description: "Fail, mismatch between metric specs and metric values",
+			expected:    nil,
+			expectedErr: errors.New("invalid observation 0: mismatch between number of metrics in spec (1) and metric values (2)"),
+			simulator: simulate.Simulator{
+				Reconciler: &controllers.PredictiveHorizontalPodAutoscalerReconciler{},
+			},
+			instance: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+				Spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+					MaxReplicas: 10,
+					Metrics:     []autoscalingv2.MetricSpec{objectMetric},
+				},
+			},
+			series: []simulate.Observation{
+				{Metrics: []float64{1, 2}},
+			},
+			initialReplicas: 1,
+		},
+		{
+			description: "Fail, unsupported resource metric",
+			expected:    nil,
+			expectedErr: errors.New("invalid observation 0: unsupported metric source type 'Resource' for metric 0, only Object and External metrics can be simulated from metric values"),
+			simulator: simulate.Simulator{
+				Reconciler: &controllers.PredictiveHorizontalPodAutoscalerReconciler{},
+			},
+			instance: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+				Spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+					MaxReplicas: 10,
+					Metrics: []autoscalingv2.MetricSpec{
+						{
+							Type: autoscalingv2.ResourceMetricSourceType,
+						},
+					},
+				},
+			},
+			series: []simulate.Observation{
+				{Metrics: []float64{1}},
+			},
+			initialReplicas: 1,
+		},
+		{
+			description: "Success, replicas without models, scale down stabilized",
+			expected: &simulate.Result{
+				Timeline: []simulate.Step{
+					{
+						Time:               start,
+						CurrentReplicas:    1,
+						CalculatedReplicas: 1,
+						DecidedReplicas:    1,
+						TargetReplicas:     1,
+					},
+					{
+						Time:               start.Add(15 * time.Second),
+						CurrentReplicas:    1,
+						CalculatedReplicas: 3,
+						DecidedReplicas:    3,
+						TargetReplicas:     3,
+					},
+					{
+						Time:               start.Add(30 * time.Second),
+						CurrentReplicas:    3,
+						CalculatedReplicas: 5,
+						DecidedReplicas:    5,
+						TargetReplicas:     5,
+					},
+					{
+						Time:               start.Add(45 * time.Second),
+						CurrentReplicas:    5,
+						CalculatedReplicas: 2,
+						DecidedReplicas:    2,
+						TargetReplicas:     5,
+					},
+				},
+				Provisioning: simulate.Provisioning{
+					Syncs:                    4,
+					UnderProvisionedSyncs:    2,
+					OverProvisionedSyncs:     1,
+					UnderProvisionedReplicas: 4,
+					OverProvisionedReplicas:  3,
+				},
+				Models: nil,
+			},
+			expectedErr: nil,
+			simulator: simulate.Simulator{
+				Reconciler: &controllers.PredictiveHorizontalPodAutoscalerReconciler{
+					Recorder: &record.FakeRecorder{},
+				},
+			},
+			instance: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+				Spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+					MaxReplicas: 10,
+				},
+			},
+			series: []simulate.Observation{
+				{Replicas: int32Ptr(1)},
+				{Replicas: int32Ptr(3)},
+				{Replicas: int32Ptr(5)},
+				{Replicas: int32Ptr(2)},
+			},
+			initialReplicas: 1,
+		},
+		{
+			description: "Success, object metric values with times",
+			expected: &simulate.Result{
+				Timeline: []simulate.Step{
+					{
+						Time:               start,
+						CurrentReplicas:    2,
+						CalculatedReplicas: 4,
+						DecidedReplicas:    4,
+						TargetReplicas:     4,
+					},
+					{
+						Time:               start.Add(time.Minute),
+						CurrentReplicas:    4,
+						CalculatedReplicas: 6,
+						DecidedReplicas:    6,
+						TargetReplicas:     6,
+					},
+				},
+				Provisioning: simulate.Provisioning{
+					Syncs:                    2,
+					UnderProvisionedSyncs:    2,
+					OverProvisionedSyncs:     0,
+					UnderProvisionedReplicas: 4,
+					OverProvisionedReplicas:  0,
+				},
+				Models: nil,
+			},
+			expectedErr: nil,
+			simulator: simulate.Simulator{
+				Reconciler: &controllers.PredictiveHorizontalPodAutoscalerReconciler{
+					Evaluator: *k8shorizmetrics.NewEvaluator(0.1),
+					Recorder:  &record.FakeRecorder{},
+				},
+			},
+			instance: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+				Spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+					MaxReplicas: 10,
+					Metrics:     []autoscalingv2.MetricSpec{objectMetric},
+				},
+			},
+			series: []simulate.Observation{
+				{Time: timePtr(start), Metrics: []float64{20}},
+				{Time: timePtr(start.Add(time.Minute)), Metrics: []float64{15}},
+			},
+			initialReplicas: 2,
+		},
+		{
+			description: "Success, model predicting ahead of calculated replicas",
+			expected: &simulate.Result{
+				Timeline: []simulate.Step{
+					{
+						Time:               start,
+						CurrentReplicas:    1,
+						CalculatedReplicas: 2,
+						DecidedReplicas:    6,
+						TargetReplicas:     5,
+					},
+					{
+						Time:               start.Add(15 * time.Second),
+						CurrentReplicas:    5,
+						CalculatedReplicas: 4,
+						DecidedReplicas:    6,
+						TargetReplicas:     5,
+					},
+				},
+				Provisioning: simulate.Provisioning{
+					Syncs:                    2,
+					UnderProvisionedSyncs:    1,
+					OverProvisionedSyncs:     1,
+					UnderProvisionedReplicas: 1,
+					OverProvisionedReplicas:  1,
+				},
+				Models: []jamiethompsonmev1alpha1.ModelStatus{
+					{
+						Name: "simple-linear",
+						Accuracy: &jamiethompsonmev1alpha1.ModelAccuracy{
+							Samples:                     1,
+							MeanAbsoluteError:           2,
+							MeanAbsolutePercentageError: float64Ptr(50),
+							Bias:                        2,
+						},
+						Ran:                   true,
+						LastPredictedReplicas: int32Ptr(6),
+						LastRunTime:           &metav1.Time{Time: start.Add(15 * time.Second)},
+						HistoryLength:         2,
+					},
+				},
+			},
+			expectedErr: nil,
+			simulator: simulate.Simulator{
+				Reconciler: &controllers.PredictiveHorizontalPodAutoscalerReconciler{
+					Recorder: &record.FakeRecorder{},
+					Predicter: &fake.Predicter{
+						GetPredictionReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) (int32, error) {
+							return 6, nil
+						},
+						PruneHistoryReactor: func(model *jamiethompsonmev1alpha1.Model, replicaHistory []jamiethompsonmev1alpha1.TimestampedReplicas) ([]jamiethompsonmev1alpha1.TimestampedReplicas, error) {
+							return replicaHistory, nil
+						},
+						GetTypeReactor: func() string {
+							return jamiethompsonmev1alpha1.TypeLinear
+						},
+					},
+				},
+			},
+			instance: &jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler{
+				Spec: jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscalerSpec{
+					MaxReplicas: 10,
+					Models: []jamiethompsonmev1alpha1.Model{
+						{
+							Type: jamiethompsonmev1alpha1.TypeLinear,
+							Name: "simple-linear",
+							Linear: &jamiethompsonmev1alpha1.Linear{
+								HistorySize: 6,
+								LookAhead:   15000,
+							},
+						},
+					},
+				},
+			},
+			series: []simulate.Observation{
+				{Replicas: int32Ptr(2)},
+				{Replicas: int32Ptr(4)},
+			},
+			initialReplicas: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			simClock := testingclock.NewFakePassiveClock(start)
+			test.simulator.Clock = simClock
+			test.simulator.Reconciler.Clock = simClock
+
+			result, err := test.simulator.Run(context.Background(), test.instance, test.series, start,
+				test.initialReplicas)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("values mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
@@ -23,10 +23,18 @@ import (
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 
 	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/custom"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/holtwinters"
 )
 
-// Validate performs validation on the PHPA, will return an error if the PHPA is not valid
-func Validate(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler) error {
+// CalculatedModelName is the name that the replica count calculated from the current metrics is given when it is
+// considered alongside the models' predictions, so it cannot be used as a model name
+const CalculatedModelName = "calculated"
+
+// Validate performs validation on the PHPA, will return an error if the PHPA is not valid. Custom models are only
+// valid if their algorithm is inside the custom algorithm directory provided
+func Validate(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscaler,
+	customAlgorithmDirectory string) error {
 	spec := instance.Spec
 
 	err := validateMinMax(spec)
@@ -34,11 +42,23 @@ func Validate(instance *jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscale
 		return err
 	}
 
-	err = validateModels(spec.Models)
+	err = validateModels(spec.Models, customAlgorithmDirectory)
 	if err != nil {
 		return err
 	}
 
+	if spec.DecisionQuantile != nil && (*spec.DecisionQuantile < 0.5 || *spec.DecisionQuantile >= 1) {
+		return fmt.Errorf("spec.decisionQuantile (%g) must be at least 0.5 and less than 1", *spec.DecisionQuantile)
+	}
+
+	if spec.CalculatedWeight != nil && *spec.CalculatedWeight < 0 {
+		return fmt.Errorf("spec.calculatedWeight (%g) cannot be less than 0", *spec.CalculatedWeight)
+	}
+
+	if spec.SharedHistorySize != nil && *spec.SharedHistorySize < 1 {
+		return fmt.Errorf("spec.sharedHistorySize (%d) must be at least 1", *spec.SharedHistorySize)
+	}
+
 	return nil
 }
 
@@ -65,8 +85,13 @@ func validateMinMax(spec jamiethompsonmev1alpha1.PredictiveHorizontalPodAutoscal
 	return nil
 }
 
-func validateModels(models []jamiethompsonmev1alpha1.Model) error {
+func validateModels(models []jamiethompsonmev1alpha1.Model, customAlgorithmDirectory string) error {
 	for _, model := range models {
+		if model.Name == CalculatedModelName {
+			return fmt.Errorf("invalid model '%s', the name '%s' is reserved for the calculated replicas",
+				model.Name, CalculatedModelName)
+		}
+
 		if model.Type == jamiethompsonmev1alpha1.TypeHoltWinters {
 			hw := model.HoltWinters
 			if hw == nil {
@@ -75,10 +100,45 @@ func validateModels(models []jamiethompsonmev1alpha1.Model) error {
 			}
 
 			if hw.RuntimeTuningFetchHook != nil {
-				hook := hw.RuntimeTuningFetchHook
-				if hook.Type == jamiethompsonmev1alpha1.HookTypeHTTP && hook.HTTP == nil {
-					return fmt.Errorf("invalid model '%s', runtimeTuningFetchHook is type '%s' but no HTTP hook configuration provided",
-						model.Name, hook.Type)
+				err := validateHook(hw.RuntimeTuningFetchHook)
+				if err != nil {
+					return fmt.Errorf("invalid model '%s', runtimeTuningFetchHook %w", model.Name, err)
+				}
+			}
+
+			if hw.LookAhead != nil && hw.LookAhead.Steps < 1 {
+				return fmt.Errorf("invalid model '%s', lookAhead steps must be at least 1", model.Name)
+			}
+
+			backend := holtwinters.DefaultBackend
+			if model.Backend != nil {
+				backend = *model.Backend
+			}
+
+			// The python backend leaves statsmodels to choose the damping factor of a damped trend if none is
+			// provided, the native backend has no equivalent so the damping factor must be set
+			if backend == jamiethompsonmev1alpha1.BackendNative && hw.DampedTrend != nil && *hw.DampedTrend &&
+				hw.DampingTrend == nil {
+				return fmt.Errorf("invalid model '%s', dampedTrend requires a dampingTrend with backend '%s'",
+					model.Name, backend)
+			}
+
+			if hw.AutoTune != nil {
+				if hw.RuntimeTuningFetchHook != nil {
+					return fmt.Errorf("invalid model '%s', autoTune cannot be used with a runtimeTuningFetchHook",
+						model.Name)
+				}
+
+				// Only the python backend can fit the parameters, this also applies to models using the default
+				// backend so that changing the default cannot make a valid model fail when it is tuned
+				if backend != jamiethompsonmev1alpha1.BackendPython {
+					return fmt.Errorf("invalid model '%s', autoTune is not supported by backend '%s'",
+						model.Name, backend)
+				}
+
+				if model.Input != nil && *model.Input != jamiethompsonmev1alpha1.InputReplicas {
+					return fmt.Errorf("invalid model '%s', autoTune is not supported for input '%s'",
+						model.Name, *model.Input)
 				}
 			}
 		}
@@ -87,6 +147,96 @@ func validateModels(models []jamiethompsonmev1alpha1.Model) error {
 			return fmt.Errorf("invalid model '%s', type is '%s' but no Linear Regression configuration provided",
 				model.Name, model.Type)
 		}
+
+		if model.Type == jamiethompsonmev1alpha1.TypeCustom {
+			if model.Custom == nil {
+				return fmt.Errorf("invalid model '%s', type is '%s' but no Custom configuration provided",
+					model.Name, model.Type)
+			}
+
+			_, err := custom.ResolvePath(customAlgorithmDirectory, model.Custom.Path)
+			if err != nil {
+				return fmt.Errorf("invalid model '%s', %w", model.Name, err)
+			}
+		}
+
+		if model.Type == jamiethompsonmev1alpha1.TypeSARIMA {
+			if model.SARIMA == nil {
+				return fmt.Errorf("invalid model '%s', type is '%s' but no SARIMA configuration provided",
+					model.Name, model.Type)
+			}
+
+			if model.SARIMA.SeasonalOrder != nil && model.SARIMA.SeasonalPeriods < 2 {
+				return fmt.Errorf("invalid model '%s', seasonalPeriods must be at least 2 if a seasonalOrder is provided",
+					model.Name)
+			}
+		}
+
+		if model.Type == jamiethompsonmev1alpha1.TypeMSTL {
+			if model.MSTL == nil {
+				return fmt.Errorf("invalid model '%s', type is '%s' but no MSTL configuration provided",
+					model.Name, model.Type)
+			}
+
+			for _, period := range model.MSTL.SeasonalPeriods {
+				if period < 2 {
+					return fmt.Errorf("invalid model '%s', every seasonalPeriods value must be at least 2", model.Name)
+				}
+			}
+		}
+
+		if model.Type == jamiethompsonmev1alpha1.TypeRemote {
+			if model.Remote == nil {
+				return fmt.Errorf("invalid model '%s', type is '%s' but no Remote configuration provided",
+					model.Name, model.Type)
+			}
+
+			err := validateHook(&model.Remote.PredictionHook)
+			if err != nil {
+				return fmt.Errorf("invalid model '%s', predictionHook %w", model.Name, err)
+			}
+		}
+
+		if model.Weight != nil && *model.Weight < 0 {
+			return fmt.Errorf("invalid model '%s', weight cannot be less than 0", model.Name)
+		}
+
+		if model.Fallback != nil && model.Fallback.RecoveryThreshold != nil &&
+			*model.Fallback.RecoveryThreshold > model.Fallback.Threshold {
+			return fmt.Errorf("invalid model '%s', fallback recoveryThreshold cannot be greater than threshold",
+				model.Name)
+		}
+
+		if model.Input != nil && *model.Input == jamiethompsonmev1alpha1.InputMetrics &&
+			(model.Type == jamiethompsonmev1alpha1.TypeCustom || model.Type == jamiethompsonmev1alpha1.TypeRemote) {
+			return fmt.Errorf("invalid model '%s', input '%s' is not supported for model type '%s'",
+				model.Name, *model.Input, model.Type)
+		}
+
+		if model.HistoryBootstrap != nil {
+			if model.Input != nil && *model.Input == jamiethompsonmev1alpha1.InputMetrics {
+				return fmt.Errorf("invalid model '%s', historyBootstrap is not supported for input '%s'",
+					model.Name, *model.Input)
+			}
+
+			if model.HistoryBootstrap.Type == jamiethompsonmev1alpha1.HistoryBootstrapPrometheus &&
+				model.HistoryBootstrap.Prometheus == nil {
+				return fmt.Errorf("invalid model '%s', historyBootstrap is type '%s' but no Prometheus configuration provided",
+					model.Name, model.HistoryBootstrap.Type)
+			}
+		}
+	}
+	return nil
+}
+
+func validateHook(hook *jamiethompsonmev1alpha1.HookDefinition) error {
+	if hook.Type == jamiethompsonmev1alpha1.HookTypeHTTP && hook.HTTP == nil {
+		return fmt.Errorf("is type '%s' but no HTTP hook configuration provided", hook.Type)
+	}
+
+	if hook.Type == jamiethompsonmev1alpha1.HookTypeGRPC && hook.GRPC == nil {
+		return fmt.Errorf("is type '%s' but no gRPC hook configuration provided", hook.Type)
 	}
+
 	return nil
 }
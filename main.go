@@ -18,6 +18,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"time"
 
@@ -33,6 +34,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	cached "k8s.io/client-go/discovery/cached"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -43,11 +45,22 @@ import (
 
 	jamiethompsonmev1alpha1 "github.com/jthomperoo/predictive-horizontal-pod-autoscaler/api/v1alpha1"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/algorithm"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/bootstrap"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/bootstrap/prometheus"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/controllers"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history/configmap"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/history/modelstate"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/hook"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/hook/grpc"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/hook/http"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/custom"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/holtwinters"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/linear"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/mstl"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/remote"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/sarima"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -67,11 +80,23 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var algorithmWorkers int
+	var historyStoreType string
+	var customAlgorithmDirectory string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.IntVar(&algorithmWorkers, "algorithm-workers", 0,
+		"The number of long-lived Python worker processes to keep for running algorithms. "+
+			"If set to 0 a new Python process is started every time an algorithm is run.")
+	flag.StringVar(&historyStoreType, "history-store", history.TypeConfigMap,
+		"Where model histories are stored, either 'configmap' to store them in a config map or 'modelstate' to "+
+			"store them in a PHPAModelState.")
+	flag.StringVar(&customAlgorithmDirectory, "custom-algorithm-dir", "",
+		"The directory Custom model algorithms must be inside, any algorithm outside of it is rejected. "+
+			"If not set Custom models cannot be used.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -124,24 +149,78 @@ func main() {
 	cpuInitializationPeriod := time.Duration(300) * time.Second
 	initialReadinessDelay := time.Duration(30) * time.Second
 	tolerance := 0.1
-	pyRunner := algorithm.NewAlgorithmPython()
-	httpExec := &http.Execute{}
+
+	realClock := clock.RealClock{}
+
+	recorder := mgr.GetEventRecorderFor("predictive-horizontal-pod-autoscaler")
+
+	var historyStore history.Store
+	switch historyStoreType {
+	case history.TypeConfigMap:
+		historyStore = &configmap.Store{Client: mgr.GetClient()}
+	case history.TypeModelState:
+		historyStore = &modelstate.Store{Client: mgr.GetClient(), Recorder: recorder}
+	default:
+		setupLog.Error(fmt.Errorf("unknown history store '%s'", historyStoreType), "unable to set up history store")
+		os.Exit(1)
+	}
+
+	var pyRunner algorithm.Runner
+	var pool *algorithm.Pool
+	if algorithmWorkers > 0 {
+		pool = algorithm.NewAlgorithmPool(algorithmWorkers)
+		pyRunner = pool
+	} else {
+		pyRunner = algorithm.NewAlgorithmPython()
+	}
+
+	grpcExec := &grpc.Execute{}
+	hookExec := &hook.CombinedExecute{
+		Executers: []hook.Executer{
+			&http.Execute{},
+			grpcExec,
+		},
+	}
 
 	if err = (&controllers.PredictiveHorizontalPodAutoscalerReconciler{
-		Client:      mgr.GetClient(),
-		Scheme:      mgr.GetScheme(),
-		ScaleClient: scaleClient,
-		Gatherer:    *k8shorizmetrics.NewGatherer(metricsclient, podsclient, cpuInitializationPeriod, initialReadinessDelay),
-		Evaluator:   *k8shorizmetrics.NewEvaluator(tolerance),
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		ScaleClient:  scaleClient,
+		Gatherer:     *k8shorizmetrics.NewGatherer(metricsclient, podsclient, cpuInitializationPeriod, initialReadinessDelay),
+		Evaluator:    *k8shorizmetrics.NewEvaluator(tolerance),
+		Recorder:     recorder,
+		HistoryStore: historyStore,
+		Bootstrapper: &bootstrap.CombinedBootstrap{
+			Bootstrappers: []bootstrap.Bootstrapper{
+				&prometheus.Bootstrap{},
+			},
+		},
+		Clock:                    realClock,
+		CustomAlgorithmDirectory: customAlgorithmDirectory,
 		Predicter: &prediction.ModelPredict{
 			Predicters: []prediction.Predicter{
 				&linear.Predict{
 					Runner: pyRunner,
+					Clock:  realClock,
 				},
 				&holtwinters.Predict{
-					HookExecute: httpExec,
+					HookExecute: hookExec,
 					Runner:      pyRunner,
 				},
+				&custom.Predict{
+					PythonRunner:     pyRunner,
+					ExecutableRunner: algorithm.NewAlgorithmExecutable(),
+					AllowedDirectory: customAlgorithmDirectory,
+				},
+				&remote.Predict{
+					HookExecute: hookExec,
+				},
+				&sarima.Predict{
+					Runner: pyRunner,
+				},
+				&mstl.Predict{
+					Runner: pyRunner,
+				},
 			},
 		},
 	}).SetupWithManager(mgr); err != nil {
@@ -160,7 +239,16 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+
+	// The manager has stopped its controllers, so no more algorithms or hooks will be run, stop the algorithm workers
+	// and close the gRPC connections before exiting
+	if pool != nil {
+		pool.Close()
+	}
+	grpcExec.Close()
+
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}